@@ -0,0 +1,121 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Sent with 401 responses, so clients know which credentials are accepted
+const authChallenge = `Bearer realm="Frigate-Notify", Basic realm="Frigate-Notify"`
+
+// authEnabled returns whether any API tokens or users are configured
+func authEnabled(auth models.APIAuth) bool {
+	return len(auth.Tokens) > 0 || len(auth.Users) > 0
+}
+
+// authenticate checks an Authorization header against configured API tokens & users.
+// Returns name of the matching credential & the scopes it was granted
+func authenticate(auth models.APIAuth, header string) (string, []string, bool) {
+	scheme, value, _ := strings.Cut(header, " ")
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		for id, token := range auth.Tokens {
+			if token.Token != "" && subtle.ConstantTimeCompare([]byte(value), []byte(token.Token)) == 1 {
+				name := token.Name
+				if name == "" {
+					name = fmt.Sprintf("token %v", id)
+				}
+				return name, token.Scopes, true
+			}
+		}
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", nil, false
+		}
+		username, password, _ := strings.Cut(string(decoded), ":")
+		for _, user := range auth.Users {
+			userMatch := subtle.ConstantTimeCompare([]byte(username), []byte(user.Username))
+			passMatch := subtle.ConstantTimeCompare([]byte(password), []byte(user.Password))
+			if user.Username != "" && userMatch&passMatch == 1 {
+				return user.Username, user.Scopes, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// authorize returns HTTP status code to reject a request that requires scope, or 0 if request is allowed
+func authorize(header string, scope string) int {
+	auth := config.Current().App.API.Auth
+	if scope == "" || !authEnabled(auth) {
+		return 0
+	}
+	name, scopes, ok := authenticate(auth, header)
+	if !ok {
+		log.Debug().Msg("API request denied - Invalid or missing credentials")
+		return http.StatusUnauthorized
+	}
+	if !slices.Contains(scopes, scope) {
+		log.Debug().
+			Str("credential", name).
+			Str("scope", scope).
+			Msg("API request denied - Missing required scope")
+		return http.StatusForbidden
+	}
+	return 0
+}
+
+// operationScope returns scope required by an API route, or empty string if route is public
+func operationScope(op *huma.Operation) string {
+	if op == nil {
+		return ""
+	}
+	for _, requirement := range op.Security {
+		for _, scopes := range requirement {
+			if len(scopes) > 0 {
+				return scopes[0]
+			}
+		}
+	}
+	return ""
+}
+
+// authMiddleware rejects API requests without credentials granting the scope required by each route
+func authMiddleware(api huma.API) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		switch authorize(ctx.Header("Authorization"), operationScope(ctx.Operation())) {
+		case http.StatusUnauthorized:
+			ctx.SetHeader("WWW-Authenticate", authChallenge)
+			huma.WriteErr(api, ctx, http.StatusUnauthorized, "authentication required")
+		case http.StatusForbidden:
+			huma.WriteErr(api, ctx, http.StatusForbidden, "credentials do not grant access to this resource")
+		default:
+			next(ctx)
+		}
+	}
+}
+
+// requireScope wraps a plain HTTP handler with the same checks as API routes
+func requireScope(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch authorize(r.Header.Get("Authorization"), scope) {
+		case http.StatusUnauthorized:
+			w.Header().Set("WWW-Authenticate", authChallenge)
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+		case http.StatusForbidden:
+			http.Error(w, "credentials do not grant access to this resource", http.StatusForbidden)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestAuth(t *testing.T) {
+	// Setup
+	original := config.Current()
+	defer config.Set(original)
+	router := newRouter()
+
+	request := func(method, uri string, setAuth func(r *http.Request)) int {
+		req := httptest.NewRequest(method, uri, nil)
+		if setAuth != nil {
+			setAuth(req)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	bearer := func(token string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	// All routes allowed when no credentials configured
+	if code := request(http.MethodGet, "/api/v1/version", nil); code != http.StatusOK {
+		t.Errorf("Expected: HTTP 200 without auth configured, Got: %v", code)
+	}
+
+	auth := models.APIAuth{
+		Tokens: []models.APIToken{{Token: "read-token", Scopes: []string{"read"}}},
+		Users:  []models.APIUser{{Username: "admin", Password: "secret", Scopes: []string{"read", "config"}}},
+	}
+	config.Set(&config.Config{App: models.App{API: models.API{Auth: auth}}})
+
+	tests := []struct {
+		method   string
+		uri      string
+		auth     func(r *http.Request)
+		expected int
+	}{
+		// Health checks are always public
+		{http.MethodGet, "/api/v1/healthz", nil, http.StatusOK},
+		// Credentials required once configured
+		{http.MethodGet, "/api/v1/version", nil, http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/version", bearer("wrong-token"), http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/version", bearer("read-token"), http.StatusOK},
+		{http.MethodGet, "/metrics", nil, http.StatusUnauthorized},
+		{http.MethodGet, "/metrics", bearer("read-token"), http.StatusOK},
+		// Scopes limit access to config & control routes
+		{http.MethodGet, "/api/v1/config", bearer("read-token"), http.StatusForbidden},
+		{http.MethodPost, "/api/v1/reload", bearer("read-token"), http.StatusForbidden},
+		{http.MethodGet, "/api/v1/config", func(r *http.Request) { r.SetBasicAuth("admin", "secret") }, http.StatusOK},
+		{http.MethodGet, "/api/v1/config", func(r *http.Request) { r.SetBasicAuth("admin", "wrong") }, http.StatusUnauthorized},
+	}
+	for i, test := range tests {
+		if code := request(test.method, test.uri, test.auth); code != test.expected {
+			t.Errorf("Test %d - %v %v - Expected: HTTP %v, Got: %v", i, test.method, test.uri, test.expected, code)
+		}
+	}
+}
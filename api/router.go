@@ -1,19 +1,47 @@
 package api
 
 import (
-	"fmt"
+	"io/fs"
 	"net"
 	"net/http"
+	"strconv"
 
 	apiv1 "github.com/0x2142/frigate-notify/api/v1"
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	"github.com/rs/zerolog/log"
 )
 
+// WebFiles holds the embedded web UI, served under /ui/
+var WebFiles fs.FS
+
 func RunAPIServer() error {
+	settings := config.Current().App.API
+	router := newRouter()
+
+	if !authEnabled(settings.Auth) {
+		log.Warn().Msg("API authentication not configured, anyone that can reach the API server can view & change config")
+	}
+
+	log.Debug().Msg("Starting API server...")
+	listenAddr := net.JoinHostPort(settings.Address, strconv.Itoa(settings.Port))
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+	if settings.TLS.Enabled {
+		log.Debug().Msg("API server using TLS")
+		go http.ServeTLS(listener, router, settings.TLS.Cert, settings.TLS.Key)
+	} else {
+		go http.Serve(listener, router)
+	}
+	return nil
+}
 
+// newRouter sets up API routes & authentication
+func newRouter() *http.ServeMux {
 	router := http.NewServeMux()
 
 	// Configure API
@@ -23,16 +51,27 @@ func RunAPIServer() error {
 	apiConfig.Info.Contact = &huma.Contact{Name: "Matt Schmitz",
 		URL: "https://github.com/0x2142/frigate-notify",
 	}
+	apiConfig.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+		"basic":  {Type: "http", Scheme: "basic"},
+	}
 	api := humago.New(router, apiConfig)
 
+	// Credentials are checked per request, so changes apply after a config reload
+	api.UseMiddleware(authMiddleware(api))
 	apiv1.Registerv1Routes(api)
 
-	log.Debug().Msg("Starting API server...")
-	listenAddr := fmt.Sprintf("0.0.0.0:%v", config.ConfigData.App.API.Port)
-	listener, err := net.Listen("tcp", listenAddr)
-	if err != nil {
-		return err
+	// Prometheus metrics
+	router.Handle("GET /metrics", requireScope(apiv1.ScopeRead, metrics.Handler()))
+
+	// Web UI only contains static files, data is loaded via API using the same authentication
+	if WebFiles != nil {
+		ui, err := fs.Sub(WebFiles, "web")
+		if err == nil {
+			router.Handle("GET /ui/", http.StripPrefix("/ui/", http.FileServerFS(ui)))
+			router.Handle("GET /{$}", http.RedirectHandler("/ui/", http.StatusFound))
+		}
 	}
-	go http.Serve(listener, router)
-	return nil
+
+	return router
 }
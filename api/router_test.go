@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestWebUI(t *testing.T) {
+	// Setup
+	original := config.Current()
+	defer config.Set(original)
+	WebFiles = fstest.MapFS{"web/index.html": {Data: []byte("<html>Frigate-Notify</html>")}}
+	defer func() { WebFiles = nil }()
+	router := newRouter()
+
+	auth := models.APIAuth{Tokens: []models.APIToken{{Token: "read-token", Scopes: []string{"read"}}}}
+	config.Set(&config.Config{App: models.App{API: models.API{Auth: auth}}})
+
+	// UI is served without credentials, since data is loaded via authenticated API calls
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "<html>Frigate-Notify</html>" {
+		t.Errorf("Expected: HTTP 200 with UI, Got: %v %v", rec.Code, rec.Body.String())
+	}
+
+	// Root redirects to UI
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/ui/" {
+		t.Errorf("Expected: redirect to /ui/, Got: %v %v", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// API still requires credentials
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/status", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected: HTTP 401, Got: %v", rec.Code)
+	}
+}
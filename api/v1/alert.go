@@ -0,0 +1,161 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/notifier"
+	"github.com/0x2142/frigate-notify/util"
+)
+
+// Maximum request size for manual alerts, which may include an image
+const maxAlertBodyBytes = 10 * 1024 * 1024
+
+type AlertInput struct {
+	Body struct {
+		Title    string `json:"title,omitempty" example:"Doorbell" doc:"Notification title. Defaults to configured alert title"`
+		Message  string `json:"message" example:"Someone rang the doorbell" doc:"Notification message" minLength:"1"`
+		Image    []byte `json:"image,omitempty" doc:"Base64 encoded image to attach"`
+		ImageURL string `json:"image_url,omitempty" example:"/api/front_door/latest.jpg" doc:"Path or URL on the configured Frigate server to download image to attach"`
+		Camera   string `json:"camera,omitempty" example:"front_door" doc:"Camera name, used by alert filters & templates"`
+		Label    string `json:"label,omitempty" example:"doorbell" doc:"Label, used by alert filters & templates"`
+	}
+}
+
+type AlertOutput struct {
+	Body struct {
+		Message   string `json:"message" example:"ok"`
+		EventID   string `json:"event_id" example:"1700000000.123456-manual" doc:"ID generated for this alert"`
+		HistoryID string `json:"history_id,omitempty" doc:"Notification history item, used to check result of sending to each provider"`
+	}
+}
+
+// PostAlert sends a manual alert via all enabled notification providers
+func PostAlert(ctx context.Context, input *AlertInput) (*AlertOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/alert").
+		Str("method", "POST").
+		Str("title", input.Body.Title).
+		Str("camera", input.Body.Camera).
+		Str("label", input.Body.Label).
+		Msg("Received API request")
+
+	resp := &AlertOutput{}
+
+	if !config.State.NotificationsEnabled() {
+		return resp, huma.Error409Conflict("notifications are currently disabled")
+	}
+	if len(input.Body.Image) != 0 && input.Body.ImageURL != "" {
+		return resp, huma.Error422UnprocessableEntity("image & image_url cannot both be set")
+	}
+
+	image := input.Body.Image
+	if input.Body.ImageURL != "" {
+		imageURL, onFrigate, err := resolveImageURL(input.Body.ImageURL)
+		if err != nil {
+			return resp, huma.Error422UnprocessableEntity(err.Error())
+		}
+		if onFrigate {
+			image, err = util.HTTPGet(ctx, imageURL, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
+		} else {
+			// Other hosts are checked against the allow list once resolved, & never sent Frigate credentials
+			allowed := allowedImageAddrs(ctx, config.Current().App.API.AllowedImageHosts)
+			image, err = util.HTTPGet(util.WithAllowedAddrs(ctx, addrAllowed(allowed)), imageURL, false, "")
+		}
+		if errors.Is(err, util.ErrAddrNotAllowed) {
+			return resp, huma.Error422UnprocessableEntity("image_url host is not on the allowed_image_hosts list")
+		}
+		if err != nil {
+			return resp, huma.Error502BadGateway("unable to download image", err)
+		}
+	}
+	if len(image) == 0 {
+		image = nil
+	} else if !strings.HasPrefix(http.DetectContentType(image), "image/") {
+		return resp, huma.Error422UnprocessableEntity("image is not a supported image type")
+	}
+
+	alert := models.ManualAlert{Title: input.Body.Title, Message: input.Body.Message}
+	resp.Body.EventID, resp.Body.HistoryID = notifier.SendManualAlert(alert, input.Body.Camera, input.Body.Label, image)
+	resp.Body.Message = "ok"
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/alert").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// resolveImageURL resolves an image path against the Frigate server & returns whether the URL is on the Frigate server.
+// URLs on other hosts are rejected, unless allowed_image_hosts is configured
+func resolveImageURL(raw string) (string, bool, error) {
+	server, err := url.Parse(config.Current().Frigate.Server)
+	if err != nil || server.Host == "" {
+		return "", false, fmt.Errorf("frigate server URL is not configured")
+	}
+	target, err := server.Parse(raw)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid image_url: %s", raw)
+	}
+	if target.User != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return "", false, fmt.Errorf("image_url must be an HTTP or HTTPS URL without credentials")
+	}
+	if target.Scheme == server.Scheme && target.Host == server.Host {
+		return target.String(), true, nil
+	}
+	if len(config.Current().App.API.AllowedImageHosts) == 0 {
+		return "", false, fmt.Errorf("image_url must be on the configured Frigate server")
+	}
+	return target.String(), false, nil
+}
+
+// allowedImageAddrs resolves allowed_image_hosts to the IP ranges that images may be downloaded from
+func allowedImageAddrs(ctx context.Context, hosts []string) []netip.Prefix {
+	var allowed []netip.Prefix
+	for _, host := range hosts {
+		if prefix, err := netip.ParsePrefix(host); err == nil {
+			allowed = append(allowed, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(host); err == nil {
+			allowed = append(allowed, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("host", host).
+				Msg("Unable to resolve allowed image host")
+			continue
+		}
+		for _, addr := range addrs {
+			allowed = append(allowed, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		}
+	}
+	return allowed
+}
+
+// addrAllowed returns a check for whether an IP address is within any allowed range
+func addrAllowed(allowed []netip.Prefix) func(netip.Addr) bool {
+	return func(addr netip.Addr) bool {
+		for _, prefix := range allowed {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+}
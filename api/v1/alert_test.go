@@ -0,0 +1,134 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestPostAlert(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	// Setup mock webhook server
+	received := make(chan string, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+	}))
+	defer webhook.Close()
+	defer config.Update(func(c *config.Config) {
+		c.Alerts.Webhook = []models.Webhook{{AlertCommon: models.AlertCommon{Enabled: true}, Server: webhook.URL, Method: "POST"}}
+	})()
+
+	// Check alert sent
+	resp := api.Post("/api/v1/alert", map[string]any{"title": "Doorbell", "message": "Someone rang the doorbell", "camera": "front_door"})
+	if resp.Code != http.StatusAccepted {
+		t.Fatal("Expected HTTP 202, got ", resp.Code, resp.Body.String())
+	}
+	var result AlertOutput
+	json.Unmarshal(resp.Body.Bytes(), &result.Body)
+	if !strings.HasSuffix(result.Body.EventID, "-manual") {
+		t.Errorf("Expected: generated event ID, Got: %v", result.Body.EventID)
+	}
+	select {
+	case payload := <-received:
+		if !strings.Contains(payload, `"manual":{"title":"Doorbell","message":"Someone rang the doorbell"}`) || !strings.Contains(payload, `"camera":"Front Door"`) {
+			t.Errorf("Expected: manual alert details in payload, Got: %v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected: webhook received alert")
+	}
+
+	// Check invalid image
+	resp = api.Post("/api/v1/alert", map[string]any{"message": "test", "image": []byte("not an image")})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+
+	// Check image downloaded from Frigate
+	frigate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/front_door/latest.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))
+	}))
+	defer frigate.Close()
+	defer config.Update(func(c *config.Config) { c.Frigate.Server = frigate.URL })()
+	resp = api.Post("/api/v1/alert", map[string]any{"message": "test", "image_url": "/api/front_door/latest.jpg"})
+	if resp.Code != http.StatusAccepted {
+		t.Error("Expected HTTP 202, got ", resp.Code, resp.Body.String())
+	}
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected: webhook received alert")
+	}
+
+	// Check image_url outside Frigate server rejected
+	for _, u := range []string{"http://192.0.2.10/snapshot.jpg", "//192.0.2.10/snapshot.jpg", strings.Replace(frigate.URL, "http://", "http://user@", 1) + "/api/front_door/latest.jpg"} {
+		resp = api.Post("/api/v1/alert", map[string]any{"message": "test", "image_url": u})
+		if resp.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected HTTP 422 for %s, got %v", u, resp.Code)
+		}
+	}
+
+	// Check image_url on an allowed host, checked once host is resolved
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))
+	}))
+	defer images.Close()
+	imageURL := strings.Replace(images.URL, "127.0.0.1", "localhost", 1) + "/snapshot.jpg"
+	restore := config.Update(func(c *config.Config) {
+		c.Frigate.Headers = []map[string]string{{"Authorization": "Bearer frigate"}}
+		c.App.API.AllowedImageHosts = []string{"192.0.2.0/24"}
+	})
+	resp = api.Post("/api/v1/alert", map[string]any{"message": "test", "image_url": imageURL})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422 for host not allowed, got ", resp.Code, resp.Body.String())
+	}
+	restore()
+	restore = config.Update(func(c *config.Config) {
+		c.Frigate.Headers = []map[string]string{{"Authorization": "Bearer frigate"}}
+		c.App.API.AllowedImageHosts = []string{"localhost"}
+	})
+	resp = api.Post("/api/v1/alert", map[string]any{"message": "test", "image_url": imageURL})
+	if resp.Code != http.StatusAccepted {
+		t.Error("Expected HTTP 202 for allowed host, got ", resp.Code, resp.Body.String())
+	}
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected: webhook received alert")
+	}
+	restore()
+
+	// Check missing message
+	resp = api.Post("/api/v1/alert", map[string]any{"title": "test"})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+
+	// Check notifications disabled
+	config.State.SetNotificationsEnabled(false)
+	defer config.State.SetNotificationsEnabled(true)
+	resp = api.Post("/api/v1/alert", map[string]any{"message": "test"})
+	if resp.Code != http.StatusConflict {
+		t.Error("Expected HTTP 409, got ", resp.Code)
+	}
+}
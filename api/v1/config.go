@@ -2,9 +2,12 @@ package apiv1
 
 import (
 	"context"
+	"sync"
 
 	"github.com/0x2142/frigate-notify/config"
 	"github.com/0x2142/frigate-notify/events"
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/snooze"
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/rs/zerolog/log"
 )
@@ -32,7 +35,7 @@ type PutConfigOutput struct {
 	}
 }
 
-// GetConfig returns the current running configuration
+// GetConfig returns the current running configuration, with secret values redacted
 func GetConfig(ctx context.Context, input *struct{}) (*ConfigOutput, error) {
 	log.Trace().
 		Str("uri", V1_PREFIX+"/config").
@@ -40,7 +43,7 @@ func GetConfig(ctx context.Context, input *struct{}) (*ConfigOutput, error) {
 		Msg("Received API request")
 
 	resp := &ConfigOutput{}
-	resp.Body.Config = config.ConfigData
+	resp.Body.Config = config.Current().Redacted()
 
 	log.Trace().
 		Str("uri", V1_PREFIX+"/config").
@@ -59,7 +62,11 @@ func PutConfig(ctx context.Context, input *PutConfigInput) (*PutConfigOutput, er
 
 	resp := &PutConfigOutput{}
 
+	// Keep existing value for any secrets sent back as redacted
 	newConfig := input.Body.Config
+	if err := newConfig.RestoreSecrets(config.Current()); err != nil {
+		return resp, huma.Error422UnprocessableEntity(err.Error())
+	}
 
 	var validationErrors []string
 	if !input.Body.SkipValidate {
@@ -70,7 +77,7 @@ func PutConfig(ctx context.Context, input *PutConfigInput) (*PutConfigOutput, er
 	if len(validationErrors) == 0 {
 		resp.Body.Status = "ok"
 		if !input.Body.SkipReload {
-			go reloadCfg(newConfig, input.Body.SkipSave, input.Body.SkipBackup)
+			go reloadCfg(&newConfig, input.Body.SkipSave, input.Body.SkipBackup)
 		}
 
 		log.Trace().
@@ -87,27 +94,44 @@ func PutConfig(ctx context.Context, input *PutConfigInput) (*PutConfigOutput, er
 			Interface("response_json", resp.Body).
 			Msg("Sent API response")
 
-		return resp, huma.Error422UnprocessableEntity("config validation failed")
+		// Return each validation error, since response body is not sent with errors
+		details := make([]error, len(validationErrors))
+		for i, msg := range validationErrors {
+			details[i] = &huma.ErrorDetail{Message: msg, Location: "body.config"}
+		}
+		return resp, huma.Error422UnprocessableEntity("config validation failed", details...)
 	}
 }
 
-func reloadCfg(newconfig config.Config, skipSave bool, skipBackup bool) {
+// Only one config reload may run at a time, so MQTT is not connected twice
+var reloadLock sync.Mutex
+
+func reloadCfg(newconfig *config.Config, skipSave bool, skipBackup bool) {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
 	log.Info().Msg("Reloading app config...")
 	log.Trace().
 		Bool("skipSave", skipSave).
 		Bool("skipBackup", skipBackup).
 		Msg("Config reload via API")
-	if config.ConfigData.Frigate.MQTT.Enabled {
+	if config.Current().Frigate.MQTT.Enabled {
 		events.DisconnectMQTT()
 	}
 
-	config.ConfigData = newconfig
+	config.Set(newconfig)
+	snooze.Refresh()
+	history.Refresh()
 	if !skipSave {
 		config.Save(skipBackup)
 	}
 
-	if config.ConfigData.Frigate.MQTT.Enabled {
-		events.SubscribeMQTT()
+	if config.Current().Frigate.MQTT.Enabled {
+		if err := events.SubscribeMQTT(); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Unable to connect to MQTT, no events will be received via MQTT")
+		}
 	}
 	log.Info().Msg("Config reload completed")
 }
@@ -3,8 +3,10 @@ package apiv1
 import (
 	"bytes"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/0x2142/frigate-notify/config"
 	"github.com/danielgtaylor/huma/v2/humatest"
 )
 
@@ -13,11 +15,16 @@ func TestGetConfig(t *testing.T) {
 
 	Registerv1Routes(api)
 
+	defer config.Update(func(c *config.Config) { c.Frigate.Password = "frigate-pass" })()
+
 	resp := api.Get("/api/v1/config")
 
 	if resp.Code != http.StatusOK {
 		t.Error("Expected HTTP 200, got ", resp.Code)
 	}
+	if strings.Contains(resp.Body.String(), "frigate-pass") || !strings.Contains(resp.Body.String(), config.SecretRedacted) {
+		t.Error("Expected Frigate password to be redacted, got ", resp.Body.String())
+	}
 }
 
 func TestPutConfig(t *testing.T) {
@@ -48,3 +55,30 @@ func TestPutConfig(t *testing.T) {
 		t.Error("Expected HTTP 202, got ", resp.Code)
 	}
 }
+
+func TestPutConfigUnmatchedSecret(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	// New profile can't keep a redacted secret, as there is no existing profile to copy it from
+	newconfig := `{
+   "config":{
+      "frigate":{
+         "server":"http://192.0.2.10:5000"
+      },
+      "alerts":{
+         "discord":[{"enabled": true, "webhook":"` + config.SecretRedacted + `"}]
+      }
+   },
+	  "skipbackup": true,
+	  "skipsave": true,
+	  "skipreload": true
+}`
+
+	resp := api.Put("/api/v1/config", bytes.NewReader([]byte(newconfig)))
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+}
@@ -0,0 +1,59 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/events"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+type ExplainInput struct {
+	Body struct {
+		ID   string `json:"id" example:"1700000000.123456-abcdef" doc:"Frigate event or review ID" required:"true" minLength:"1"`
+		Type string `json:"type,omitempty" enum:"event,review" doc:"Whether ID is an event or review. Defaults to app mode"`
+	}
+}
+
+type ExplainOutput struct {
+	Body models.Explanation
+}
+
+// PostExplain runs a Frigate event or review through all filters without sending & returns the result of each step
+func PostExplain(ctx context.Context, input *ExplainInput) (*ExplainOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/explain").
+		Str("method", "POST").
+		Interface("body", input.Body).
+		Msg("Received API request")
+
+	kind := input.Body.Type
+	if kind == "" {
+		kind = "event"
+		if strings.ToLower(config.Current().App.Mode) == "reviews" {
+			kind = "review"
+		}
+	}
+
+	resp := &ExplainOutput{}
+	result, err := events.Explain(ctx, input.Body.ID, kind)
+	if err != nil {
+		if errors.Is(err, events.ErrNotFound) {
+			return resp, huma.Error404NotFound(kind + " " + err.Error())
+		}
+		return resp, huma.Error502BadGateway("unable to retrieve "+kind+" from Frigate", err)
+	}
+	resp.Body = result
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/explain").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
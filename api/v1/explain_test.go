@@ -0,0 +1,63 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/events"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestPostExplain(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	// Setup mock Frigate server
+	frigate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/events/test-event" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id": "test-event", "camera": "front_door", "label": "person", "has_snapshot": true, "zones": ["driveway"]}`))
+	}))
+	defer frigate.Close()
+	defer config.Update(func(c *config.Config) {
+		c.Frigate.Server = frigate.URL
+		c.Alerts.Discord = []models.Discord{{AlertCommon: models.AlertCommon{Enabled: true}}}
+		c.Alerts.Discord[0].Filters.Cameras = []string{"back_yard"}
+	})()
+	events.InitZoneCache()
+	defer events.CloseZoneCache()
+
+	// Check event explained
+	resp := api.Post("/api/v1/explain", map[string]any{"id": "test-event", "type": "event"})
+	if resp.Code != http.StatusOK {
+		t.Fatal("Expected HTTP 200, got ", resp.Code)
+	}
+	var result models.Explanation
+	json.Unmarshal(resp.Body.Bytes(), &result)
+	if result.Notify || len(result.Events) != 1 || len(result.Providers) != 1 {
+		t.Fatalf("Expected: 1 event & 1 provider, no notification, Got: %+v", result)
+	}
+	if result.Providers[0].Notify || result.Providers[0].Steps[1].Reason != "Camera not on filter list" {
+		t.Errorf("Expected: dropped by camera filter, Got: %+v", result.Providers[0])
+	}
+
+	// Check event not found
+	resp = api.Post("/api/v1/explain", map[string]any{"id": "asdf", "type": "event"})
+	if resp.Code != http.StatusNotFound {
+		t.Error("Expected HTTP 404, got ", resp.Code)
+	}
+
+	// Check missing ID
+	resp = api.Post("/api/v1/explain", map[string]any{})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+}
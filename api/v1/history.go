@@ -0,0 +1,66 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+type HistoryInput struct {
+	Camera   string `query:"camera" doc:"Only return events from this camera"`
+	Label    string `query:"label" doc:"Only return events with this label"`
+	Provider string `query:"provider" doc:"Only return events sent or filtered by this notification provider"`
+	Outcome  string `query:"outcome" enum:"filtered,pending,sent,partial,failed" doc:"Only return events with this outcome"`
+	Page     int    `query:"page" minimum:"1" default:"1" doc:"Page number"`
+	PageSize int    `query:"page_size" minimum:"1" maximum:"500" default:"50" doc:"Number of items per page"`
+}
+
+type HistoryOutput struct {
+	Body struct {
+		Items    []models.HistoryItem `json:"items" doc:"Processed events, newest first"`
+		Total    int                  `json:"total" doc:"Total number of matching items"`
+		Page     int                  `json:"page" doc:"Page number"`
+		PageSize int                  `json:"page_size" doc:"Number of items per page"`
+	}
+}
+
+// GetHistory returns processed events & the result of sending notifications for each
+func GetHistory(ctx context.Context, input *HistoryInput) (*HistoryOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/history").
+		Str("method", "GET").
+		Msg("Received API request")
+
+	resp := &HistoryOutput{}
+	items, total, err := history.Items(history.Query{
+		Camera:   input.Camera,
+		Label:    input.Label,
+		Provider: input.Provider,
+		Outcome:  input.Outcome,
+		Page:     input.Page,
+		PageSize: input.PageSize,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotReady) {
+			return resp, huma.Error503ServiceUnavailable(err.Error())
+		}
+		return resp, huma.Error500InternalServerError("unable to read notification history", err)
+	}
+	resp.Body.Items = items
+	resp.Body.Total = total
+	resp.Body.Page = input.Page
+	resp.Body.PageSize = input.PageSize
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/history").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
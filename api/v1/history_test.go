@@ -0,0 +1,37 @@
+package apiv1
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+func TestGetHistory(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	// Check local storage not available
+	resp := api.Get("/api/v1/history")
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Error("Expected HTTP 503, got ", resp.Code)
+	}
+
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+
+	resp = api.Get("/api/v1/history?camera=front_door&outcome=sent&page=2")
+	if resp.Code != http.StatusOK {
+		t.Error("Expected HTTP 200, got ", resp.Code)
+	}
+
+	// Check invalid filter
+	resp = api.Get("/api/v1/history?outcome=asdf")
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+}
@@ -2,20 +2,27 @@ package apiv1
 
 import (
 	"context"
+	"time"
 
-	"github.com/0x2142/frigate-notify/config"
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
 )
 
 type NotifStateInput struct {
 	Body struct {
-		Enabled bool `json:"enabled" enum:"true,false" doc:"Set state of notifications" required:"true"`
+		Enabled  bool      `json:"enabled" enum:"true,false" doc:"Set state of notifications" required:"true"`
+		Duration string    `json:"duration,omitempty" example:"4h" doc:"Revert to previous state after duration, ex: 30m or 4h"`
+		Until    time.Time `json:"until,omitempty" doc:"Revert to previous state at this time, instead of duration"`
 	}
 }
 
 type NotifStateOutput struct {
 	Body struct {
-		Enabled bool `json:"enabled" enum:"true,false" doc:"Frigate-Notify enabled for notifications" default:"true"`
+		Enabled          bool       `json:"enabled" enum:"true,false" doc:"Frigate-Notify enabled for notifications" default:"true"`
+		Until            *time.Time `json:"until,omitempty" doc:"Time notification state will revert, if changed for a limited time"`
+		RemainingSeconds *int64     `json:"remaining_seconds,omitempty" example:"3600" doc:"Seconds until notification state will revert, if changed for a limited time"`
 	}
 }
 
@@ -26,8 +33,7 @@ func GetNotifState(ctx context.Context, input *struct{}) (*NotifStateOutput, err
 		Str("method", "GET").
 		Msg("Received API request")
 
-	resp := &NotifStateOutput{}
-	resp.Body.Enabled = config.Internal.Status.Notifications.Enabled
+	resp := notifState()
 
 	log.Trace().
 		Str("uri", V1_PREFIX+"/notif_state").
@@ -37,21 +43,29 @@ func GetNotifState(ctx context.Context, input *struct{}) (*NotifStateOutput, err
 	return resp, nil
 }
 
-// PostNotifState updates state to enable or disable app notifications
+// PostNotifState updates state to enable or disable app notifications, optionally for a limited time
 func PostNotifState(ctx context.Context, input *NotifStateInput) (*NotifStateOutput, error) {
 	log.Trace().
 		Str("uri", V1_PREFIX+"/notif_state").
 		Str("method", "POST").
 		Msg("Received API request")
 
-	config.Internal.Status.Notifications.Enabled = input.Body.Enabled
+	var until time.Time
+	if input.Body.Duration != "" || !input.Body.Until.IsZero() {
+		var err error
+		until, err = parseExpiry(input.Body.Until, input.Body.Duration)
+		if err != nil {
+			return &NotifStateOutput{}, huma.Error422UnprocessableEntity(err.Error())
+		}
+	}
+	config.State.SetNotificationsEnabledUntil(input.Body.Enabled, until)
 
 	log.Debug().
 		Bool("state", input.Body.Enabled).
+		Time("until", until).
 		Msg("App state changed via API")
 
-	resp := &NotifStateOutput{}
-	resp.Body.Enabled = config.Internal.Status.Notifications.Enabled
+	resp := notifState()
 
 	log.Trace().
 		Str("uri", V1_PREFIX+"/notif_state").
@@ -60,3 +74,16 @@ func PostNotifState(ctx context.Context, input *NotifStateInput) (*NotifStateOut
 
 	return resp, nil
 }
+
+// notifState returns current notification state & remaining time of any timed change
+func notifState() *NotifStateOutput {
+	resp := &NotifStateOutput{}
+	status := config.State.Status().Notifications
+	resp.Body.Enabled = status.Enabled
+	if !status.Until.IsZero() {
+		remaining := int64(status.Remaining().Seconds())
+		resp.Body.Until = &status.Until
+		resp.Body.RemainingSeconds = &remaining
+	}
+	return resp
+}
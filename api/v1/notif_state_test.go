@@ -2,10 +2,15 @@ package apiv1
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/config"
 )
 
 func TestGetNotifState(t *testing.T) {
@@ -31,3 +36,41 @@ func TestPostNotifState(t *testing.T) {
 		t.Error("Expected HTTP 202, got ", resp.Code)
 	}
 }
+
+func TestPostNotifStateTimed(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+	config.State.SetNotificationsEnabled(true)
+	defer config.State.SetNotificationsEnabled(true)
+
+	resp := api.Post("/api/v1/notif_state", map[string]any{"enabled": false, "duration": "invalid"})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+
+	resp = api.Post("/api/v1/notif_state", map[string]any{"enabled": false, "duration": "100ms"})
+	if resp.Code != http.StatusAccepted {
+		t.Fatal("Expected HTTP 202, got ", resp.Code, resp.Body.String())
+	}
+
+	// Check remaining time returned until state reverts
+	resp = api.Get("/api/v1/notif_state")
+	var state NotifStateOutput
+	json.Unmarshal(resp.Body.Bytes(), &state.Body)
+	if state.Body.Enabled || state.Body.Until == nil || state.Body.RemainingSeconds == nil {
+		t.Errorf("Expected timed disable, Got: %s", resp.Body.String())
+	}
+	resp = api.Get("/api/v1/status")
+	if !strings.Contains(resp.Body.String(), `"notif_timer"`) {
+		t.Errorf("Expected remaining time in status, Got: %s", resp.Body.String())
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	resp = api.Get("/api/v1/notif_state")
+	state = NotifStateOutput{}
+	json.Unmarshal(resp.Body.Bytes(), &state.Body)
+	if !state.Body.Enabled || state.Body.Until != nil {
+		t.Errorf("Expected notifications re-enabled, Got: %s", resp.Body.String())
+	}
+}
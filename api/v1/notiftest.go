@@ -2,52 +2,90 @@ package apiv1
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/events"
 	"github.com/0x2142/frigate-notify/models"
 	"github.com/0x2142/frigate-notify/notifier"
-	"github.com/0x2142/frigate-notify/util"
-	"github.com/rs/zerolog/log"
 )
 
+type NotifTestRequest struct {
+	Providers []models.NotifTestTarget   `json:"providers,omitempty" doc:"Notification provider profiles to test. Defaults to all enabled profiles"`
+	ID        string                     `json:"id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event or review ID to send. Defaults to most recent event"`
+	Type      string                     `json:"type,omitempty" enum:"event,review" doc:"Whether ID is an event or review. Defaults to app mode"`
+	Synthetic *models.NotifTestSynthetic `json:"synthetic,omitempty" doc:"Send a generated event with sample snapshot, instead of an event from Frigate"`
+}
+
+type NotifTestInput struct {
+	Body *NotifTestRequest
+}
+
 type NotifTestOutput struct {
 	Body struct {
-		Message string `json:"message"`
+		Results []models.NotifTestResult `json:"results" doc:"Result of sending test notification via each provider profile"`
 	}
 }
 
-// PostNotifTest collects most the recent event from Frigate & sends a test notification to configured providers
-func PostNotifTest(ctx context.Context, input *struct{}) (*NotifTestOutput, error) {
+// PostNotifTest sends a test notification for a Frigate event, or a synthetic event, to selected or all enabled providers
+func PostNotifTest(ctx context.Context, input *NotifTestInput) (*NotifTestOutput, error) {
 	log.Trace().
 		Str("uri", V1_PREFIX+"/notif_test").
 		Str("method", "POST").
+		Interface("body", input.Body).
 		Msg("Received API request")
 
+	log.Info().Msg("Received request to test notifications")
+
 	resp := &NotifTestOutput{}
-	resp.Body.Message = "ok"
-
-	go func() {
-		log.Info().Msg("Received request to test notifications")
-
-		// Query frigate API for most recent event
-		var events []models.Event
-		uri := "/api/events"
-		params := "?include_thumbnails=0&limit=1"
-		url := config.ConfigData.Frigate.Server + uri + params
-
-		response, err := util.HTTPGet(url, config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Msgf("Cannot get event from %s", url)
-			return
+	body := input.Body
+	if body == nil {
+		body = &NotifTestRequest{}
+	}
+	var testEvents []models.Event
+	var snapshot []byte
+	var err error
+
+	switch {
+	case body.Synthetic != nil && body.ID != "":
+		return resp, huma.Error422UnprocessableEntity("id & synthetic cannot both be set")
+	case body.Synthetic != nil:
+		var event models.Event
+		event, snapshot = notifier.SyntheticEvent(*body.Synthetic)
+		testEvents = []models.Event{event}
+	case body.ID != "":
+		kind := body.Type
+		if kind == "" {
+			kind = "event"
+			if strings.ToLower(config.Current().App.Mode) == "reviews" {
+				kind = "review"
+			}
 		}
-		json.Unmarshal([]byte(response), &events)
+		testEvents, err = events.FetchEvents(ctx, body.ID, kind)
+		if errors.Is(err, events.ErrNotFound) {
+			return resp, huma.Error404NotFound(kind + " " + err.Error())
+		}
+	default:
+		testEvents, err = events.LatestEvent(ctx)
+	}
+	if errors.Is(err, events.ErrNotFound) {
+		return resp, huma.Error404NotFound("no events found in Frigate, a synthetic event can be used instead")
+	}
+	if err != nil {
+		return resp, huma.Error502BadGateway("unable to retrieve event from Frigate", err)
+	}
+	if len(testEvents) == 0 {
+		return resp, huma.Error404NotFound("no detection events found for review")
+	}
 
-		// Send test notification
-		notifier.SendAlert(events)
-	}()
+	resp.Body.Results, err = notifier.SendTest(ctx, testEvents, snapshot, body.Providers)
+	if err != nil {
+		return resp, huma.Error422UnprocessableEntity(err.Error())
+	}
 
 	log.Trace().
 		Str("uri", V1_PREFIX+"/notif_test").
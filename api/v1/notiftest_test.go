@@ -0,0 +1,76 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestPostNotifTest(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	// Setup mock Frigate server with no events
+	frigate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer frigate.Close()
+	defer config.Update(func(c *config.Config) {
+		c.Frigate.Server = frigate.URL
+		c.App.DryRun = true
+		c.Alerts.Webhook = []models.Webhook{
+			{AlertCommon: models.AlertCommon{Enabled: true, Name: "primary"}, Server: "https://webhook.test"},
+			{AlertCommon: models.AlertCommon{Name: "new"}, Server: "https://webhook.test"},
+		}
+	})()
+
+	// Check no events in Frigate
+	resp := api.Post("/api/v1/notif_test")
+	if resp.Code != http.StatusNotFound {
+		t.Error("Expected HTTP 404, got ", resp.Code)
+	}
+
+	// Check synthetic event sent to disabled profile selected by name
+	resp = api.Post("/api/v1/notif_test", map[string]any{
+		"providers": []map[string]any{{"provider": "webhook", "name": "new"}},
+		"synthetic": map[string]any{"camera": "front_door", "zones": []string{"driveway"}},
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatal("Expected HTTP 200, got ", resp.Code, resp.Body.String())
+	}
+	var result NotifTestOutput
+	json.Unmarshal(resp.Body.Bytes(), &result.Body)
+	expected := models.NotifTestResult{Provider: "webhook", ProfileID: 1, Name: "new", Status: "dry_run"}
+	if len(result.Body.Results) != 1 || result.Body.Results[0] != expected {
+		t.Errorf("Expected: %+v, Got: %+v", expected, result.Body.Results)
+	}
+
+	// Check synthetic event sent to all enabled profiles
+	resp = api.Post("/api/v1/notif_test", map[string]any{"synthetic": map[string]any{}})
+	json.Unmarshal(resp.Body.Bytes(), &result.Body)
+	if len(result.Body.Results) != 1 || result.Body.Results[0].Name != "primary" {
+		t.Errorf("Expected: primary profile only, Got: %+v", result.Body.Results)
+	}
+
+	// Check unknown profile
+	resp = api.Post("/api/v1/notif_test", map[string]any{
+		"providers": []map[string]any{{"provider": "webhook", "provider_id": 5}},
+		"synthetic": map[string]any{},
+	})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+
+	// Check ID & synthetic event both set
+	resp = api.Post("/api/v1/notif_test", map[string]any{"id": "asdf", "synthetic": map[string]any{}})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+}
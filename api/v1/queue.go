@@ -0,0 +1,131 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/notifier"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+type QueueOutput struct {
+	Body struct {
+		Items []models.QueueItem `json:"items" doc:"Queued alerts"`
+	}
+}
+
+type QueueItemInput struct {
+	ID string `path:"id" doc:"Queue item ID"`
+}
+
+type QueueItemOutput struct {
+	Body struct {
+		Message string `json:"message"`
+	}
+}
+
+// GetQueue returns alerts waiting to be sent
+func GetQueue(ctx context.Context, input *struct{}) (*QueueOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue").
+		Str("method", "GET").
+		Msg("Received API request")
+
+	resp := &QueueOutput{}
+	items, err := notifier.QueuedItems()
+	if err != nil {
+		return resp, queueError(err)
+	}
+	resp.Body.Items = items
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// GetDeadLetter returns alerts that failed to send after all retry attempts
+func GetDeadLetter(ctx context.Context, input *struct{}) (*QueueOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue/dead_letter").
+		Str("method", "GET").
+		Msg("Received API request")
+
+	resp := &QueueOutput{}
+	items, err := notifier.DeadLetterItems()
+	if err != nil {
+		return resp, queueError(err)
+	}
+	resp.Body.Items = items
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue/dead_letter").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// PostDeadLetterRetry moves a dead-lettered alert back to the queue
+func PostDeadLetterRetry(ctx context.Context, input *QueueItemInput) (*QueueItemOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue/dead_letter/"+input.ID+"/retry").
+		Str("method", "POST").
+		Msg("Received API request")
+
+	resp := &QueueItemOutput{}
+	ok, err := notifier.RetryDeadLetter(input.ID)
+	if err != nil {
+		return resp, queueError(err)
+	}
+	if !ok {
+		return resp, huma.Error404NotFound("queue item not found")
+	}
+	resp.Body.Message = "ok"
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue/dead_letter/"+input.ID+"/retry").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// DeleteDeadLetter removes a dead-lettered alert
+func DeleteDeadLetter(ctx context.Context, input *QueueItemInput) (*QueueItemOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue/dead_letter/"+input.ID).
+		Str("method", "DELETE").
+		Msg("Received API request")
+
+	resp := &QueueItemOutput{}
+	ok, err := notifier.DeleteDeadLetter(input.ID)
+	if err != nil {
+		return resp, queueError(err)
+	}
+	if !ok {
+		return resp, huma.Error404NotFound("queue item not found")
+	}
+	resp.Body.Message = "ok"
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/queue/dead_letter/"+input.ID).
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// queueError converts a queue storage error to an API error
+func queueError(err error) error {
+	if errors.Is(err, storage.ErrNotReady) {
+		return huma.Error503ServiceUnavailable(err.Error())
+	}
+	return huma.Error500InternalServerError("unable to read notification queue", err)
+}
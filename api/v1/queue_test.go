@@ -0,0 +1,73 @@
+package apiv1
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+func TestGetQueue(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	// Check local storage not available
+	resp := api.Get("/api/v1/queue")
+	if resp.Code != http.StatusServiceUnavailable {
+		t.Error("Expected HTTP 503, got ", resp.Code)
+	}
+
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+
+	resp = api.Get("/api/v1/queue")
+	if resp.Code != http.StatusOK {
+		t.Error("Expected HTTP 200, got ", resp.Code)
+	}
+}
+
+func TestGetDeadLetter(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+
+	resp := api.Get("/api/v1/queue/dead_letter")
+	if resp.Code != http.StatusOK {
+		t.Error("Expected HTTP 200, got ", resp.Code)
+	}
+}
+
+func TestPostDeadLetterRetry(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+
+	resp := api.Post("/api/v1/queue/dead_letter/asdf/retry")
+	if resp.Code != http.StatusNotFound {
+		t.Error("Expected HTTP 404, got ", resp.Code)
+	}
+}
+
+func TestDeleteDeadLetter(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+
+	resp := api.Delete("/api/v1/queue/dead_letter/asdf")
+	if resp.Code != http.StatusNotFound {
+		t.Error("Expected HTTP 404, got ", resp.Code)
+	}
+}
@@ -22,7 +22,7 @@ func GetReadyz(ctx context.Context, input *struct{}) (*ReadyzOutput, error) {
 
 	resp := &ReadyzOutput{}
 
-	resp.Body.Status = config.Internal.Status.Health
+	resp.Body.Status = config.State.Health()
 
 	log.Trace().
 		Str("uri", V1_PREFIX+"/readyz").
@@ -25,11 +25,16 @@ func PostReload(ctx context.Context, input *struct{}) (*ReloadOutput, error) {
 
 	go func() {
 		log.Info().Msg("Received request to reload config")
-		// Re-load from file & trigger reload
-		config.ConfigData = config.Config{}
-		config.Load()
-		newconfig := config.ConfigData
-		go reloadCfg(newconfig, true, true)
+		// Re-load from file & only apply if valid, so app keeps running with current config
+		newconfig, validationErrors := config.Read()
+		if len(validationErrors) > 0 {
+			for _, msg := range validationErrors {
+				log.Error().Msgf(" - %v", msg)
+			}
+			log.Error().Msg("Config validation failed, keeping current config")
+			return
+		}
+		reloadCfg(newconfig, true, true)
 	}()
 
 	log.Trace().
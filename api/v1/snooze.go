@@ -0,0 +1,177 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/snooze"
+)
+
+type SnoozeInput struct {
+	Body struct {
+		Camera    string    `json:"camera,omitempty" example:"driveway" doc:"Camera to snooze"`
+		Label     string    `json:"label,omitempty" example:"person" doc:"Label to snooze"`
+		Provider  string    `json:"provider,omitempty" example:"discord" doc:"Notification provider to snooze. If not set, all providers are snoozed"`
+		ProfileID *int      `json:"provider_id,omitempty" example:"0" doc:"Notification provider profile ID to snooze" minimum:"0"`
+		Name      string    `json:"name,omitempty" doc:"Notification provider profile name to snooze, instead of ID"`
+		Duration  string    `json:"duration,omitempty" example:"2h" doc:"How long to snooze for, ex: 30m or 2h"`
+		Until     time.Time `json:"until,omitempty" doc:"Time to snooze until, instead of duration"`
+		Note      string    `json:"note,omitempty" example:"Landscaper" doc:"Reason for snooze"`
+	}
+}
+
+type SnoozeOutput struct {
+	Body models.Snooze
+}
+
+type SnoozeListOutput struct {
+	Body struct {
+		Snoozes []models.Snooze `json:"snoozes" doc:"Active snoozes, soonest to expire first"`
+	}
+}
+
+type SnoozeIDInput struct {
+	ID string `path:"id" doc:"Snooze ID"`
+}
+
+type SnoozeCancelOutput struct {
+	Body struct {
+		Message string `json:"message" example:"ok"`
+	}
+}
+
+// GetSnoozes returns all active snoozes
+func GetSnoozes(ctx context.Context, input *struct{}) (*SnoozeListOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/snooze").
+		Str("method", "GET").
+		Msg("Received API request")
+
+	resp := &SnoozeListOutput{}
+	resp.Body.Snoozes = snooze.List()
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/snooze").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// PostSnooze stops notifications for a camera, label, or notification provider profile until snooze expires
+func PostSnooze(ctx context.Context, input *SnoozeInput) (*SnoozeOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/snooze").
+		Str("method", "POST").
+		Interface("body", input.Body).
+		Msg("Received API request")
+
+	resp := &SnoozeOutput{}
+	body := input.Body
+	if body.Camera == "" && body.Label == "" && body.Provider == "" {
+		return resp, huma.Error422UnprocessableEntity("at least one of camera, label or provider must be set")
+	}
+	until, err := parseExpiry(body.Until, body.Duration)
+	if err != nil {
+		return resp, huma.Error422UnprocessableEntity(err.Error())
+	}
+
+	s := models.Snooze{Camera: body.Camera, Label: body.Label, Note: body.Note, Until: until}
+	var profileKey string
+	if body.Provider != "" {
+		s.Provider = body.Provider
+		s.ProfileID, profileKey, err = snoozeProfile(body.Provider, body.ProfileID, body.Name)
+		if err != nil {
+			return resp, huma.Error422UnprocessableEntity(err.Error())
+		}
+	} else if body.ProfileID != nil || body.Name != "" {
+		return resp, huma.Error422UnprocessableEntity("provider must be set to snooze a provider profile")
+	}
+
+	resp.Body, err = snooze.Add(s, profileKey)
+	if err != nil {
+		return resp, huma.Error500InternalServerError("unable to save snooze", err)
+	}
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/snooze").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// DeleteSnooze cancels an active snooze
+func DeleteSnooze(ctx context.Context, input *SnoozeIDInput) (*SnoozeCancelOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/snooze/"+input.ID).
+		Str("method", "DELETE").
+		Msg("Received API request")
+
+	resp := &SnoozeCancelOutput{}
+	ok, err := snooze.Cancel(input.ID)
+	if err != nil {
+		return resp, huma.Error500InternalServerError("unable to cancel snooze", err)
+	}
+	if !ok {
+		return resp, huma.Error404NotFound("snooze not found")
+	}
+	resp.Body.Message = "ok"
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/snooze/"+input.ID).
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
+
+// parseExpiry returns the end time from either a timestamp or duration, only one of which may be set
+func parseExpiry(until time.Time, duration string) (time.Time, error) {
+	switch {
+	case duration != "" && !until.IsZero():
+		return until, fmt.Errorf("only one of until or duration may be set")
+	case duration != "":
+		d, err := time.ParseDuration(duration)
+		if err != nil || d <= 0 {
+			return until, fmt.Errorf("invalid duration: %s", duration)
+		}
+		return time.Now().Add(d), nil
+	case until.IsZero():
+		return until, fmt.Errorf("until or duration must be set")
+	case !until.After(time.Now()):
+		return until, fmt.Errorf("until must be in the future")
+	}
+	return until, nil
+}
+
+// snoozeProfile checks a notification provider profile exists & returns its ID & key, or nil to snooze all profiles
+func snoozeProfile(provider string, id *int, name string) (*int, string, error) {
+	n, ok := config.GetNotifier(provider)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown notification provider: %s", provider)
+	}
+	profiles := n.Profiles(config.Current())
+	switch {
+	case id != nil && name != "":
+		return nil, "", fmt.Errorf("only one of provider_id or name may be set")
+	case id != nil:
+		if *id >= len(profiles) {
+			return nil, "", fmt.Errorf("notification provider profile %v not configured for %s", *id, provider)
+		}
+		return id, config.ProfileKey(profiles[*id]), nil
+	case name != "":
+		index, ok := config.FindProfile(config.Current(), n, name)
+		if !ok {
+			return nil, "", fmt.Errorf("notification provider profile %s not configured for %s", name, provider)
+		}
+		return &index, config.ProfileKey(profiles[index]), nil
+	}
+	return nil, "", nil
+}
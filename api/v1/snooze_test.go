@@ -0,0 +1,71 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestSnooze(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	defer config.Update(func(c *config.Config) {
+		c.Alerts.Webhook = []models.Webhook{
+			{AlertCommon: models.AlertCommon{Enabled: true, Name: "primary"}, Server: "https://webhook.test"},
+		}
+	})()
+
+	// Check invalid requests
+	invalid := []map[string]any{
+		{"duration": "1h"},
+		{"camera": "driveway"},
+		{"camera": "driveway", "duration": "1h", "until": time.Now().Add(time.Hour)},
+		{"camera": "driveway", "duration": "-1h"},
+		{"camera": "driveway", "until": time.Now().Add(-time.Hour)},
+		{"provider": "unknown", "duration": "1h"},
+		{"provider": "webhook", "name": "missing", "duration": "1h"},
+		{"provider_id": 0, "duration": "1h"},
+	}
+	for _, body := range invalid {
+		resp := api.Post("/api/v1/snooze", body)
+		if resp.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected HTTP 422 for %v, got %v", body, resp.Code)
+		}
+	}
+
+	// Check snooze created for provider profile by name
+	resp := api.Post("/api/v1/snooze", map[string]any{"provider": "webhook", "name": "primary", "label": "cat", "duration": "1h"})
+	if resp.Code != http.StatusCreated {
+		t.Fatal("Expected HTTP 201, got ", resp.Code, resp.Body.String())
+	}
+	var created models.Snooze
+	json.Unmarshal(resp.Body.Bytes(), &created)
+	if created.ID == "" || created.ProfileID == nil || *created.ProfileID != 0 {
+		t.Errorf("Expected snooze for profile 0, Got: %+v", created)
+	}
+
+	resp = api.Get("/api/v1/snooze")
+	var list SnoozeListOutput
+	json.Unmarshal(resp.Body.Bytes(), &list.Body)
+	if len(list.Body.Snoozes) != 1 || list.Body.Snoozes[0].ID != created.ID {
+		t.Errorf("Expected snooze %s, Got: %+v", created.ID, list.Body.Snoozes)
+	}
+
+	// Check cancel
+	resp = api.Delete("/api/v1/snooze/" + created.ID)
+	if resp.Code != http.StatusOK {
+		t.Error("Expected HTTP 200, got ", resp.Code)
+	}
+	resp = api.Delete("/api/v1/snooze/" + created.ID)
+	if resp.Code != http.StatusNotFound {
+		t.Error("Expected HTTP 404, got ", resp.Code)
+	}
+}
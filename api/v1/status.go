@@ -22,7 +22,7 @@ func GetStatus(ctx context.Context, input *struct{}) (*StatusOutput, error) {
 		Msg("Received API request")
 
 	resp := &StatusOutput{}
-	resp.Body.Status = config.Internal.Status
+	resp.Body.Status = config.State.Status()
 
 	log.Trace().
 		Str("uri", V1_PREFIX+"/status").
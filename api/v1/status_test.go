@@ -1,10 +1,15 @@
 package apiv1
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
 )
 
 func TestGetStatus(t *testing.T) {
@@ -18,3 +23,43 @@ func TestGetStatus(t *testing.T) {
 		t.Error("Expected HTTP 200, got ", resp.Code)
 	}
 }
+
+func TestGetStatusTimedNotifState(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	defer config.Update(func(c *config.Config) {
+		c.Alerts.Webhook = []models.Webhook{{AlertCommon: models.AlertCommon{Enabled: true}, Server: "https://webhook.test"}}
+	})()
+	config.State.SetNotificationsEnabledUntil(false, time.Now().Add(time.Hour))
+	defer config.State.SetNotificationsEnabled(true)
+
+	resp := api.Get("/api/v1/status")
+	var body struct {
+		Status struct {
+			Notifications map[string]json.RawMessage `json:"notifications"`
+			NotifTimer    *models.NotifTimer         `json:"notif_timer"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check timer is reported separately from notification provider status
+	if body.Status.NotifTimer == nil || body.Status.NotifTimer.RemainingSeconds <= 0 {
+		t.Errorf("Expected notif_timer, Got: %s", resp.Body.String())
+	}
+	if _, ok := body.Status.Notifications["webhook"]; !ok {
+		t.Errorf("Expected webhook provider status, Got: %s", resp.Body.String())
+	}
+	for key, value := range body.Status.Notifications {
+		if key == "enabled" {
+			continue
+		}
+		var profiles []models.NotifierStatus
+		if err := json.Unmarshal(value, &profiles); err != nil {
+			t.Errorf("Expected %s to be a list of provider profiles, Got: %s", key, value)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package apiv1
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/stream"
+	"github.com/danielgtaylor/huma/v2/sse"
+	"github.com/rs/zerolog/log"
+)
+
+// Interval between keepalive messages sent to idle live stream clients
+const keepaliveInterval = 30 * time.Second
+
+// Message types sent to live stream clients, by SSE event name
+var streamMessages = map[string]any{
+	"received":  models.StreamReceived{},
+	"filtered":  models.StreamFiltered{},
+	"delivery":  models.StreamDelivery{},
+	"keepalive": models.StreamKeepalive{},
+}
+
+// GetStream sends new events, filter decisions & notification results to the client as they happen
+func GetStream(ctx context.Context, input *struct{}, send sse.Sender) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/stream").
+		Str("method", "GET").
+		Msg("Received API request")
+
+	messages, unsubscribe := stream.Subscribe()
+	defer unsubscribe()
+	log.Debug().Msg("Live stream client connected")
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			log.Debug().Msg("Live stream client disconnected")
+			return
+		case msg := <-messages:
+			err = send.Data(msg)
+		case t := <-keepalive.C:
+			err = send.Data(models.StreamKeepalive{Time: t})
+		}
+		if err != nil {
+			log.Debug().
+				Err(err).
+				Msg("Unable to write to live stream client, closing connection")
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package apiv1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/stream"
+	"github.com/danielgtaylor/huma/v2/sse"
+)
+
+func TestGetStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan any, 10)
+	done := make(chan struct{})
+	go func() {
+		GetStream(ctx, &struct{}{}, func(msg sse.Message) error {
+			received <- msg.Data
+			return nil
+		})
+		close(done)
+	}()
+
+	// Client receives new events once connected
+	event := models.Event{ID: "event-1", Camera: "front_door", Label: "person"}
+	timeout := time.After(time.Second)
+	var msg any
+	for msg == nil {
+		stream.EventReceived(event, "webapi")
+		select {
+		case msg = <-received:
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("Expected: event sent to stream client")
+		}
+	}
+	if result, ok := msg.(models.StreamReceived); !ok || result.EventID != "event-1" {
+		t.Errorf("Expected: event-1 received, Got: %+v", msg)
+	}
+
+	// Stream is closed once client disconnects
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected: stream closed after client disconnect")
+	}
+}
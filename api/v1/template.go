@@ -0,0 +1,87 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/events"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/notifier"
+)
+
+type TemplateRenderInput struct {
+	Body struct {
+		Template  string                     `json:"template" example:"{{ .Extra.CameraName }} detected {{ .Label }}" doc:"Template to render, or name of built-in template: markdown, html, plaintext or json" minLength:"1"`
+		ID        string                     `json:"id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event or review ID to render template for. Defaults to a synthetic event"`
+		Type      string                     `json:"type,omitempty" enum:"event,review" doc:"Whether ID is an event or review. Defaults to app mode"`
+		Synthetic *models.NotifTestSynthetic `json:"synthetic,omitempty" doc:"Generated event to render template for, instead of an event from Frigate"`
+	}
+}
+
+type TemplateRenderOutput struct {
+	Body struct {
+		Output string `json:"output" doc:"Rendered template. May be incomplete if rendering failed"`
+		Error  string `json:"error,omitempty" doc:"Template parse or execution error, if any"`
+	}
+}
+
+// PostTemplateRender renders a template for a Frigate event or synthetic event, without sending a notification
+func PostTemplateRender(ctx context.Context, input *TemplateRenderInput) (*TemplateRenderOutput, error) {
+	log.Trace().
+		Str("uri", V1_PREFIX+"/template/render").
+		Str("method", "POST").
+		Interface("body", input.Body).
+		Msg("Received API request")
+
+	resp := &TemplateRenderOutput{}
+	var renderEvents []models.Event
+
+	if input.Body.ID != "" {
+		if input.Body.Synthetic != nil {
+			return resp, huma.Error422UnprocessableEntity("id & synthetic cannot both be set")
+		}
+		kind := input.Body.Type
+		if kind == "" {
+			kind = "event"
+			if strings.ToLower(config.Current().App.Mode) == "reviews" {
+				kind = "review"
+			}
+		}
+		var err error
+		renderEvents, err = events.FetchEvents(ctx, input.Body.ID, kind)
+		if err != nil {
+			if errors.Is(err, events.ErrNotFound) {
+				return resp, huma.Error404NotFound(kind + " " + err.Error())
+			}
+			return resp, huma.Error502BadGateway("unable to retrieve "+kind+" from Frigate", err)
+		}
+		if len(renderEvents) == 0 {
+			return resp, huma.Error404NotFound("no detection events found for review")
+		}
+	} else {
+		synthetic := models.NotifTestSynthetic{Camera: "test_camera", Label: "person"}
+		if input.Body.Synthetic != nil {
+			synthetic = *input.Body.Synthetic
+		}
+		event, _ := notifier.SyntheticEvent(synthetic)
+		renderEvents = []models.Event{event}
+	}
+
+	output, err := notifier.RenderTemplate(input.Body.Template, renderEvents)
+	resp.Body.Output = output
+	if err != nil {
+		resp.Body.Error = err.Error()
+	}
+
+	log.Trace().
+		Str("uri", V1_PREFIX+"/template/render").
+		Interface("response_json", resp.Body).
+		Msg("Sent API response")
+
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestPostTemplateRender(t *testing.T) {
+	_, api := humatest.New(t)
+
+	Registerv1Routes(api)
+
+	render := func(body map[string]any) TemplateRenderOutput {
+		t.Helper()
+		var result TemplateRenderOutput
+		resp := api.Post("/api/v1/template/render", body)
+		if resp.Code != http.StatusOK {
+			t.Fatal("Expected HTTP 200, got ", resp.Code)
+		}
+		json.Unmarshal(resp.Body.Bytes(), &result.Body)
+		return result
+	}
+
+	// Check custom template rendered for synthetic event
+	result := render(map[string]any{"template": "{{ .Extra.CameraName }} detected {{ .Label }}", "synthetic": map[string]any{"camera": "front_door"}})
+	if result.Body.Output != "Front Door detected person" || result.Body.Error != "" {
+		t.Errorf("Expected: Front Door detected person, Got: %+v", result.Body)
+	}
+
+	// Check built-in JSON template
+	result = render(map[string]any{"template": "json"})
+	if !strings.Contains(result.Body.Output, `"camera":"Test Camera"`) {
+		t.Errorf("Expected: default webhook payload, Got: %+v", result.Body)
+	}
+
+	// Check parse & execution errors are returned
+	result = render(map[string]any{"template": "{{ .Camera "})
+	if result.Body.Error == "" {
+		t.Errorf("Expected: parse error, Got: %+v", result.Body)
+	}
+	result = render(map[string]any{"template": "Camera: {{ .Camera }} {{ .Missing }}"})
+	if result.Body.Error == "" || result.Body.Output != "Camera: test_camera " {
+		t.Errorf("Expected: execution error with partial output, Got: %+v", result.Body)
+	}
+
+	// Check ID & synthetic event both set
+	resp := api.Post("/api/v1/template/render", map[string]any{"template": "test", "id": "asdf", "synthetic": map[string]any{}})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Error("Expected HTTP 422, got ", resp.Code)
+	}
+}
@@ -4,10 +4,23 @@ import (
 	"net/http"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/sse"
 )
 
 var V1_PREFIX string
 
+// Access scopes required by API routes, which are enforced when API authentication is configured
+const (
+	ScopeRead    = "read"
+	ScopeControl = "control"
+	ScopeConfig  = "config"
+)
+
+// requireScope returns security requirement for a route, which accepts either bearer token or basic auth
+func requireScope(scope string) []map[string][]string {
+	return []map[string][]string{{"bearer": {scope}}, {"basic": {scope}}}
+}
+
 func Registerv1Routes(api huma.API) {
 	V1_PREFIX = "/api/v1"
 
@@ -41,6 +54,7 @@ func Registerv1Routes(api huma.API) {
 		Summary:     V1_PREFIX + "/version",
 		Description: "Retrieve Frigate-Notify application version",
 		Tags:        []string{"Status"},
+		Security:    requireScope(ScopeRead),
 	}, GetVersion)
 
 	// GET /status
@@ -51,8 +65,20 @@ func Registerv1Routes(api huma.API) {
 		Summary:     V1_PREFIX + "/status",
 		Description: "Retrieve detailed health and status of Frigate-Notify",
 		Tags:        []string{"Status"},
+		Security:    requireScope(ScopeRead),
 	}, GetStatus)
 
+	// GET /stream
+	sse.Register(api, huma.Operation{
+		OperationID: "get-stream",
+		Method:      http.MethodGet,
+		Path:        V1_PREFIX + "/stream",
+		Summary:     V1_PREFIX + "/stream",
+		Description: "Stream new events, filter decisions & notification results as they happen, via Server-Sent Events",
+		Tags:        []string{"Status"},
+		Security:    requireScope(ScopeRead),
+	}, streamMessages, GetStream)
+
 	// GET /config
 	huma.Register(api, huma.Operation{
 		OperationID: "get-config",
@@ -61,6 +87,7 @@ func Registerv1Routes(api huma.API) {
 		Summary:     V1_PREFIX + "/config",
 		Description: "Retrieve current running configuration",
 		Tags:        []string{"Config"},
+		Security:    requireScope(ScopeConfig),
 	}, GetConfig)
 
 	// PUT /config
@@ -71,9 +98,21 @@ func Registerv1Routes(api huma.API) {
 		Summary:       V1_PREFIX + "/config",
 		Description:   "Set current running configuration",
 		Tags:          []string{"Config"},
+		Security:      requireScope(ScopeConfig),
 		DefaultStatus: http.StatusAccepted,
 	}, PutConfig)
 
+	// POST /template/render
+	huma.Register(api, huma.Operation{
+		OperationID: "post-template-render",
+		Method:      http.MethodPost,
+		Path:        V1_PREFIX + "/template/render",
+		Summary:     V1_PREFIX + "/template/render",
+		Description: "Render a notification template for a Frigate event or synthetic event, without sending",
+		Tags:        []string{"Config"},
+		Security:    requireScope(ScopeConfig),
+	}, PostTemplateRender)
+
 	// POST /reload
 	huma.Register(api, huma.Operation{
 		OperationID:   "post-reload",
@@ -82,6 +121,7 @@ func Registerv1Routes(api huma.API) {
 		Summary:       V1_PREFIX + "/reload",
 		Description:   "Reload config from file & restart app",
 		Tags:          []string{"Control"},
+		Security:      requireScope(ScopeControl),
 		DefaultStatus: http.StatusAccepted,
 	}, PostReload)
 
@@ -93,6 +133,7 @@ func Registerv1Routes(api huma.API) {
 		Summary:     V1_PREFIX + "/notif_state",
 		Description: "Retrieve current state of Frigate-Notify notifications",
 		Tags:        []string{"Control"},
+		Security:    requireScope(ScopeRead),
 	}, GetNotifState)
 
 	// POST /notif_state
@@ -103,17 +144,133 @@ func Registerv1Routes(api huma.API) {
 		Summary:       V1_PREFIX + "/notif_state",
 		Description:   "Set state of Frigate-Notify alerting",
 		Tags:          []string{"Control"},
+		Security:      requireScope(ScopeControl),
 		DefaultStatus: http.StatusAccepted,
 	}, PostNotifState)
 
+	// GET /snooze
+	huma.Register(api, huma.Operation{
+		OperationID: "get-snooze",
+		Method:      http.MethodGet,
+		Path:        V1_PREFIX + "/snooze",
+		Summary:     V1_PREFIX + "/snooze",
+		Description: "Retrieve active snoozes",
+		Tags:        []string{"Control"},
+		Security:    requireScope(ScopeRead),
+	}, GetSnoozes)
+
+	// POST /snooze
+	huma.Register(api, huma.Operation{
+		OperationID:   "post-snooze",
+		Method:        http.MethodPost,
+		Path:          V1_PREFIX + "/snooze",
+		Summary:       V1_PREFIX + "/snooze",
+		Description:   "Snooze notifications for a camera, label or notification provider profile",
+		Tags:          []string{"Control"},
+		Security:      requireScope(ScopeControl),
+		DefaultStatus: http.StatusCreated,
+	}, PostSnooze)
+
+	// DELETE /snooze/{id}
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-snooze",
+		Method:      http.MethodDelete,
+		Path:        V1_PREFIX + "/snooze/{id}",
+		Summary:     V1_PREFIX + "/snooze/{id}",
+		Description: "Cancel an active snooze",
+		Tags:        []string{"Control"},
+		Security:    requireScope(ScopeControl),
+	}, DeleteSnooze)
+
 	// POST /notiftest
 	huma.Register(api, huma.Operation{
 		OperationID:   "post-notiftest",
 		Method:        http.MethodPost,
 		Path:          V1_PREFIX + "/notif_test",
 		Summary:       V1_PREFIX + "/notif_test",
-		Description:   "Send test notification via configured providers",
+		Description:   "Send test notification via selected or all enabled providers & return the result for each",
 		Tags:          []string{"Control"},
-		DefaultStatus: http.StatusAccepted,
+		Security:      requireScope(ScopeControl),
+		DefaultStatus: http.StatusOK,
 	}, PostNotifTest)
+
+	// POST /alert
+	huma.Register(api, huma.Operation{
+		OperationID:   "post-alert",
+		Method:        http.MethodPost,
+		Path:          V1_PREFIX + "/alert",
+		Summary:       V1_PREFIX + "/alert",
+		Description:   "Send a custom alert via all enabled providers",
+		Tags:          []string{"Control"},
+		Security:      requireScope(ScopeControl),
+		MaxBodyBytes:  maxAlertBodyBytes,
+		DefaultStatus: http.StatusAccepted,
+	}, PostAlert)
+
+	// POST /explain
+	huma.Register(api, huma.Operation{
+		OperationID: "post-explain",
+		Method:      http.MethodPost,
+		Path:        V1_PREFIX + "/explain",
+		Summary:     V1_PREFIX + "/explain",
+		Description: "Check whether a Frigate event or review would generate a notification, without sending",
+		Tags:        []string{"Control"},
+		Security:    requireScope(ScopeRead),
+	}, PostExplain)
+
+	// GET /queue
+	huma.Register(api, huma.Operation{
+		OperationID: "get-queue",
+		Method:      http.MethodGet,
+		Path:        V1_PREFIX + "/queue",
+		Summary:     V1_PREFIX + "/queue",
+		Description: "Retrieve alerts waiting to be sent",
+		Tags:        []string{"Queue"},
+		Security:    requireScope(ScopeRead),
+	}, GetQueue)
+
+	// GET /queue/dead_letter
+	huma.Register(api, huma.Operation{
+		OperationID: "get-queue-dead-letter",
+		Method:      http.MethodGet,
+		Path:        V1_PREFIX + "/queue/dead_letter",
+		Summary:     V1_PREFIX + "/queue/dead_letter",
+		Description: "Retrieve alerts that failed to send after all retry attempts",
+		Tags:        []string{"Queue"},
+		Security:    requireScope(ScopeRead),
+	}, GetDeadLetter)
+
+	// POST /queue/dead_letter/{id}/retry
+	huma.Register(api, huma.Operation{
+		OperationID:   "post-queue-dead-letter-retry",
+		Method:        http.MethodPost,
+		Path:          V1_PREFIX + "/queue/dead_letter/{id}/retry",
+		Summary:       V1_PREFIX + "/queue/dead_letter/{id}/retry",
+		Description:   "Move failed alert back to queue & retry sending",
+		Tags:          []string{"Queue"},
+		Security:      requireScope(ScopeControl),
+		DefaultStatus: http.StatusAccepted,
+	}, PostDeadLetterRetry)
+
+	// DELETE /queue/dead_letter/{id}
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-queue-dead-letter",
+		Method:      http.MethodDelete,
+		Path:        V1_PREFIX + "/queue/dead_letter/{id}",
+		Summary:     V1_PREFIX + "/queue/dead_letter/{id}",
+		Description: "Remove failed alert from dead letter queue",
+		Tags:        []string{"Queue"},
+		Security:    requireScope(ScopeControl),
+	}, DeleteDeadLetter)
+
+	// GET /history
+	huma.Register(api, huma.Operation{
+		OperationID: "get-history",
+		Method:      http.MethodGet,
+		Path:        V1_PREFIX + "/history",
+		Summary:     V1_PREFIX + "/history",
+		Description: "Retrieve processed events & notification results",
+		Tags:        []string{"History"},
+		Security:    requireScope(ScopeRead),
+	}, GetHistory)
 }
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	envconfig "github.com/0x2142/frigate-notify/config/providers/env"
@@ -26,12 +28,113 @@ type Config struct {
 	Monitor models.Monitor `koanf:"monitor" json:"monitor" required:"false"`
 }
 
-var ConfigData Config
+// Running config, replaced as a whole when reloaded so readers never see a partial update
+var current atomic.Pointer[Config]
+
 var ConfigFile string
-var k = koanf.New(".")
 
-// Load opens & attempts to parse configuration file
+// DryRun is set via command line flag & enables dry run mode regardless of app config
+var DryRun bool
+
+func init() {
+	current.Store(&Config{})
+}
+
+// Current returns the running config, which must not be modified. Use Set to apply a new config
+func Current() *Config {
+	return current.Load()
+}
+
+// Set replaces the running config & resets notification provider status to match
+func Set(c *Config) {
+	current.Store(c)
+	State.resetProviders(c)
+}
+
+// Update applies changes to a copy of the running config & sets it, returning a func that restores the previous config.
+// Used by tests, since the running config must not be modified in place
+func Update(fn func(c *Config)) (restore func()) {
+	previous := Current()
+	c := previous.Copy()
+	fn(c)
+	Set(c)
+	return func() { Set(previous) }
+}
+
+// Copy returns a deep copy of config, so changes to it do not affect the original
+func (c *Config) Copy() *Config {
+	copied := *c
+	deepCopy(reflect.ValueOf(&copied).Elem())
+	return &copied
+}
+
+// deepCopy replaces slices, maps & interface values within v with copies, so they no longer share memory with the original
+func deepCopy(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				deepCopy(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		v.Set(copied)
+		for i := range v.Len() {
+			deepCopy(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			deepCopy(value)
+			copied.SetMapIndex(iter.Key(), value)
+		}
+		v.Set(copied)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		value := reflect.New(v.Elem().Type()).Elem()
+		value.Set(v.Elem())
+		deepCopy(value)
+		v.Set(value)
+	}
+}
+
+// Load opens & attempts to parse configuration file, exiting if config is invalid
 func Load() {
+	c, validationErrors := Read()
+
+	if len(validationErrors) > 0 {
+		fmt.Println()
+		log.Error().Msg("Config validation failed:")
+		for _, msg := range validationErrors {
+			log.Error().Msgf(" - %v", msg)
+		}
+		fmt.Println()
+		log.Fatal().Msg("Please fix config errors before restarting app.")
+	} else {
+		log.Info().Msg("Config file validated!")
+	}
+
+	Set(c)
+}
+
+// Read parses & validates config from file, environment variables & docker secrets without applying it
+func Read() (*Config, []string) {
+	k := koanf.New(".")
+	c := &Config{}
+
 	// Set config file location
 	if ConfigFile == "" {
 		var ok bool
@@ -66,38 +169,33 @@ func Load() {
 			Msg("Unable to load docker secrets")
 	}
 
-	k.Unmarshal("", &ConfigData)
+	k.Unmarshal("", c)
 
 	log.Info().Msg("Config loaded")
 
 	log.Trace().
-		Interface("config", ConfigData).
+		Interface("config", c.Redacted()).
 		Msg("Config loaded")
 
 	// Send config file to validation before completing
-	validationErrors := ConfigData.Validate()
+	validationErrors := c.Validate()
 
 	log.Trace().
-		Interface("config", ConfigData).
+		Interface("config", c.Redacted()).
 		Msg("Config file loaded & validation completed")
 
-	if len(validationErrors) > 0 {
-		fmt.Println()
-		log.Error().Msg("Config validation failed:")
-		for _, msg := range validationErrors {
-			log.Error().Msgf(" - %v", msg)
-		}
-		fmt.Println()
-		log.Fatal().Msg("Please fix config errors before restarting app.")
-	} else {
-		log.Info().Msg("Config file validated!")
-	}
+	return c, validationErrors
+}
+
+// IsDryRun returns whether notifications should be logged instead of sent
+func IsDryRun() bool {
+	return DryRun || Current().App.DryRun
 }
 
 func Save(skipBackup bool) {
 	log.Debug().Msg("Writing new config file")
 
-	data, err := yml.Marshal(&ConfigData)
+	data, err := yml.Marshal(Current())
 	if err != nil {
 		log.Error().Err(err).Msg("Unable to save config")
 		return
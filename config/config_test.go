@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestUpdate(t *testing.T) {
+	original := &Config{}
+	original.Alerts.Webhook = []models.Webhook{{Server: "https://webhook.test", Headers: []map[string]string{{"Authorization": "abc"}}, Template: map[string]interface{}{"camera": "{{ .Camera }}"}}}
+	Set(original)
+	defer Set(&Config{})
+
+	restore := Update(func(c *Config) {
+		c.Alerts.Webhook[0].Server = "https://changed.test"
+		c.Alerts.Webhook[0].Headers[0]["Authorization"] = "xyz"
+		c.Alerts.Webhook[0].Template.(map[string]interface{})["camera"] = "changed"
+	})
+
+	// Changes are applied to a copy, leaving original config untouched
+	if Current() == original || Current().Alerts.Webhook[0].Server != "https://changed.test" {
+		t.Errorf("Expected: updated config set, Got: %+v", Current().Alerts.Webhook)
+	}
+	if original.Alerts.Webhook[0].Server != "https://webhook.test" || original.Alerts.Webhook[0].Headers[0]["Authorization"] != "abc" || original.Alerts.Webhook[0].Template.(map[string]interface{})["camera"] != "{{ .Camera }}" {
+		t.Errorf("Expected: original config unchanged, Got: %+v", original.Alerts.Webhook)
+	}
+
+	restore()
+	if Current() != original {
+		t.Error("Expected: original config restored")
+	}
+}
@@ -5,10 +5,29 @@ import "github.com/0x2142/frigate-notify/models"
 // Default config values
 var DefaultConfig Config = Config{
 	App: models.App{
-		Mode: "reviews",
+		Mode:   "reviews",
+		DryRun: false,
 		API: models.API{
 			Enabled: false,
+			Address: "0.0.0.0",
 			Port:    8000},
+		Storage: models.Storage{
+			Path: "./data/frigate-notify.db",
+		},
+		Queue: models.Queue{
+			Enabled:      false,
+			MaxAttempts:  10,
+			InitialDelay: 5,
+			MaxDelay:     300,
+		},
+		Delivery: models.Delivery{
+			Workers:      10,
+			DrainTimeout: 30,
+		},
+		History: models.History{
+			Enabled:   false,
+			Retention: 7,
+		},
 		Internal: models.Internal{
 			HTTP: models.HTTP{
 				Timeout:  10,
@@ -38,6 +57,14 @@ var DefaultConfig Config = Config{
 			Username:    "",
 			Password:    "",
 			TopicPrefix: "frigate",
+			Protocol:    "tcp",
+			QoS:         1,
+			Retry: models.MQTTRetry{
+				Attempts:    0,
+				Interval:    10,
+				MaxInterval: 300,
+			},
+			Failover: false,
 		},
 		Cameras: models.Cameras{
 			Exclude: nil,
@@ -56,6 +83,8 @@ var DefaultConfig Config = Config{
 			NotifyDetections: false,
 			RecheckDelay:     0,
 			AudioOnly:        "allow",
+			UpdateMessages:   false,
+			NotifyEnd:        false,
 		},
 		Quiet: models.Quiet{
 			Start: "",
@@ -81,6 +110,11 @@ var DefaultConfig Config = Config{
 			Allow:   nil,
 			Block:   nil,
 		},
+		Cooldown: models.Cooldown{
+			Duration: 0,
+			Cameras:  nil,
+			Labels:   nil,
+		},
 	},
 	Monitor: models.Monitor{
 		Enabled:  false,
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// Notifier is implemented by each notification provider
+type Notifier interface {
+	// Name returns the config key for this provider, ex: "discord"
+	Name() string
+	// Profiles returns settings for each configured profile of this provider, ex: *models.Discord
+	Profiles(c *Config) []Profile
+	// Validate checks settings for a single profile & sets any default values
+	Validate(c *Config, id int, profile Profile) []string
+	// Send delivers an alert via a single profile, aborting if ctx is cancelled
+	Send(ctx context.Context, event models.Event, snapshot io.Reader, profile Profile) error
+}
+
+// Profile holds settings for a single notification provider profile
+type Profile interface {
+	// Common returns alert settings shared by all notification providers
+	Common() *models.AlertCommon
+}
+
+// MessageEditor is implemented by notification providers that can update a previously sent alert
+type MessageEditor interface {
+	// SendMessage delivers an alert via a single profile & returns an ID that can be used to edit it later
+	SendMessage(ctx context.Context, event models.Event, snapshot io.Reader, profile Profile) (string, error)
+	// EditMessage replaces the content of a previously sent alert
+	EditMessage(ctx context.Context, event models.Event, snapshot io.Reader, profile Profile, messageID string) error
+}
+
+// MessageReplier is implemented by notification providers that can send a follow-up as a reply to a previously sent alert
+type MessageReplier interface {
+	// ReplyMessage delivers a follow-up alert as a reply to a previously sent alert
+	ReplyMessage(ctx context.Context, event models.Event, snapshot io.Reader, profile Profile, messageID string) error
+}
+
+// ProfileList returns each item of a provider's list of profiles from config, ex: ProfileList(c.Alerts.Discord)
+func ProfileList[T any, P interface {
+	*T
+	Profile
+}](list []T) []Profile {
+	profiles := make([]Profile, len(list))
+	for i := range list {
+		profiles[i] = P(&list[i])
+	}
+	return profiles
+}
+
+// CustomProfiles decodes profiles for a provider that is not built-in, configured under alerts.custom.<name>.
+// Profiles are decoded each time, so any defaults must be applied when sending rather than by Validate
+func CustomProfiles[T any, P interface {
+	*T
+	Profile
+}](c *Config, name string) []Profile {
+	list := c.Alerts.Custom[name]
+	profiles := make([]Profile, len(list))
+	for i, settings := range list {
+		profile := P(new(T))
+		data, err := json.Marshal(settings)
+		if err == nil {
+			err = json.Unmarshal(data, profile)
+		}
+		if err != nil {
+			// Leave invalid profile disabled, so remaining profiles keep their ID
+			profile = P(new(T))
+			log.Error().
+				Str("provider", name).
+				Int("provider_id", i).
+				Err(err).
+				Msg("Unable to read notification provider settings")
+		}
+		profiles[i] = profile
+	}
+	return profiles
+}
+
+var notifiers []Notifier
+
+// RegisterNotifier adds a notification provider to the list of available providers
+func RegisterNotifier(n Notifier) {
+	for _, existing := range notifiers {
+		if existing.Name() == n.Name() {
+			panic("notifier already registered: " + n.Name())
+		}
+	}
+	notifiers = append(notifiers, n)
+	sort.Slice(notifiers, func(i, j int) bool {
+		return notifiers[i].Name() < notifiers[j].Name()
+	})
+}
+
+// Notifiers returns all registered notification providers, sorted by name
+func Notifiers() []Notifier {
+	return notifiers
+}
+
+// GetNotifier returns a registered notification provider by name
+func GetNotifier(name string) (Notifier, bool) {
+	for _, n := range notifiers {
+		if n.Name() == name {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// FindProfile returns the index of a provider profile by name, or the first profile if name is empty
+func FindProfile(c *Config, n Notifier, name string) (int, bool) {
+	profiles := n.Profiles(c)
+	for id, profile := range profiles {
+		if name == "" || profile.Common().Name == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// ProfileKey returns a stable identity for a provider profile, which still matches it after other profiles are added, removed or reordered.
+// Profiles are identified by name if set, otherwise by their settings
+func ProfileKey(profile Profile) string {
+	if name := profile.Common().Name; name != "" {
+		return "name:" + name
+	}
+	settings, _ := json.Marshal(profile)
+	sum := sha256.Sum256(settings)
+	return "settings:" + hex.EncodeToString(sum[:8])
+}
+
+// FindProfileKey returns the index of the provider profile matching a key from ProfileKey.
+// Not found if no profile, or more than one profile, matches
+func FindProfileKey(c *Config, n Notifier, key string) (int, bool) {
+	index, found := 0, 0
+	for id, profile := range n.Profiles(c) {
+		if ProfileKey(profile) == key {
+			index = id
+			found++
+		}
+	}
+	return index, found == 1
+}
+
+// GetFallback returns the provider & profile index to send alerts via if a provider profile fails
+func GetFallback(c *Config, profile *models.AlertCommon) (Notifier, int, bool) {
+	if profile.Fallback.Provider == "" {
+		return nil, 0, false
+	}
+	n, ok := GetNotifier(profile.Fallback.Provider)
+	if !ok {
+		return nil, 0, false
+	}
+	id, ok := FindProfile(c, n, profile.Fallback.Profile)
+	if !ok {
+		return nil, 0, false
+	}
+	return n, id, true
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// testNotifier reads Discord profiles, since built-in providers are registered by the notifier package
+type testNotifier struct{}
+
+func (testNotifier) Name() string {
+	return "test"
+}
+
+func (testNotifier) Profiles(c *Config) []Profile {
+	return ProfileList(c.Alerts.Discord)
+}
+
+func (testNotifier) Validate(c *Config, id int, p Profile) []string {
+	return nil
+}
+
+func (testNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p Profile) error {
+	return nil
+}
+
+// customProfile holds settings for customNotifier, which is configured via alerts.custom like providers that are not built-in
+type customProfile struct {
+	models.AlertCommon
+	Server string `json:"server"`
+}
+
+type customNotifier struct{}
+
+func (customNotifier) Name() string {
+	return "custom_test"
+}
+
+func (customNotifier) Profiles(c *Config) []Profile {
+	return CustomProfiles[customProfile](c, "custom_test")
+}
+
+func (customNotifier) Validate(c *Config, id int, p Profile) []string {
+	if p.(*customProfile).Server == "" {
+		return []string{"No custom server specified!"}
+	}
+	return nil
+}
+
+func (customNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p Profile) error {
+	return nil
+}
+
+// registerTestNotifier adds testNotifier to the registry once
+func registerTestNotifier() {
+	if _, ok := GetNotifier("test"); !ok {
+		RegisterNotifier(testNotifier{})
+	}
+}
+
+func TestGetNotifier(t *testing.T) {
+	registerTestNotifier()
+
+	// Check registered provider
+	_, ok := GetNotifier("test")
+	if !ok {
+		t.Error("Expected: test notifier, Got: none")
+	}
+
+	// Check unknown provider
+	_, ok = GetNotifier("asdf")
+	if ok {
+		t.Error("Expected: no notifier, Got: asdf")
+	}
+}
+
+func TestCustomProfiles(t *testing.T) {
+	n, ok := GetNotifier("custom_test")
+	if !ok {
+		n = customNotifier{}
+		RegisterNotifier(n)
+	}
+
+	var c Config
+	c.Alerts.Custom = models.CustomAlerts{"custom_test": {
+		{"enabled": true, "name": "primary", "server": "https://test.test"},
+		{"enabled": "invalid"},
+		{"enabled": true, "name": "missing"},
+	}}
+
+	// Settings are decoded into provider profile type
+	profiles := n.Profiles(&c)
+	if len(profiles) != 3 {
+		t.Fatalf("Expected: 3 profiles, Got: %v", len(profiles))
+	}
+	if profile := profiles[0].(*customProfile); !profile.Enabled || profile.Name != "primary" || profile.Server != "https://test.test" {
+		t.Errorf("Expected: primary profile, Got: %+v", profile)
+	}
+	// Invalid profile is left disabled, keeping ID of later profiles
+	if profiles[1].Common().Enabled || profiles[2].Common().Name != "missing" {
+		t.Errorf("Expected: invalid profile disabled, Got: %+v & %+v", profiles[1], profiles[2])
+	}
+	if id, ok := FindProfile(&c, n, "missing"); !ok || id != 2 {
+		t.Errorf("Expected: profile 2, Got: %v", id)
+	}
+
+	// Custom providers are validated with built-in providers
+	errors := c.Validate()
+	if !slices.Contains(errors, "No custom server specified!") {
+		t.Errorf("Expected: missing server error, Got: %v", errors)
+	}
+}
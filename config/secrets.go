@@ -0,0 +1,404 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SecretRedacted replaces secret config values in API responses & logs.
+// Sending it back via the API keeps the existing secret value
+const SecretRedacted = "**REDACTED**"
+
+// Names that may contain credentials, redacted from secret maps such as HTTP headers & custom provider settings
+var sensitiveKeys = []string{"authorization", "cookie", "token", "key", "secret", "password", "webhook", "url"}
+
+// Redacted returns a copy of config with values tagged as secret replaced, so it can be safely logged or returned via API
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redactSecrets(reflect.ValueOf(&redacted).Elem(), false)
+	return redacted
+}
+
+// RestoreSecrets replaces any redacted secret values with the matching value from current config.
+// Returns an error if a list item holding redacted secrets can't be matched to an existing item
+func (c *Config) RestoreSecrets(current *Config) error {
+	return restoreSecrets(reflect.ValueOf(c).Elem(), reflect.ValueOf(current).Elem(), false, "config")
+}
+
+// redactSecrets walks config & replaces secret values. Slices & maps are copied before
+// changing them, so the original config is left untouched
+func redactSecrets(v reflect.Value, secret bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if field.IsExported() {
+				redactSecrets(v.Field(i), secret || field.Tag.Get("secret") == "true")
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		v.Set(copied)
+		for i := range v.Len() {
+			redactSecrets(v.Index(i), secret)
+		}
+	case reflect.Map:
+		if secret {
+			redactValue(v, false)
+		}
+	case reflect.String:
+		if secret && v.String() != "" {
+			v.SetString(SecretRedacted)
+		}
+	}
+}
+
+// restoreSecrets walks new & current config together, copying current values wherever new config holds the redacted placeholder.
+// List items are matched to current config using matchItems
+func restoreSecrets(v reflect.Value, current reflect.Value, secret bool, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			if field.Anonymous {
+				name = path
+			} else {
+				name = path + "." + name
+			}
+			if err := restoreSecrets(v.Field(i), fieldOrZero(current, i), secret || field.Tag.Get("secret") == "true", name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		matches, err := matchItems(v, current, path, func(item reflect.Value) { redactSecrets(item, secret) })
+		if err != nil {
+			return err
+		}
+		for i, match := range matches {
+			var currentItem reflect.Value
+			if match >= 0 {
+				currentItem = current.Index(match)
+			}
+			if err := restoreSecrets(v.Index(i), currentItem, secret, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if secret {
+			return restoreValue(v, current, false, path)
+		}
+	case reflect.String:
+		if !secret || v.String() != SecretRedacted {
+			return nil
+		}
+		var value string
+		if current.IsValid() {
+			value = current.String()
+		}
+		v.SetString(value)
+	}
+	return nil
+}
+
+// redactValue walks a secret map, such as HTTP headers, & replaces values where the key looks like a credential.
+// Maps, slices & interface values are copied before changing them, so the original config is left untouched
+func redactValue(v reflect.Value, sensitive bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		value := reflect.New(v.Elem().Type()).Elem()
+		value.Set(v.Elem())
+		redactValue(value, sensitive)
+		v.Set(value)
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		v.Set(copied)
+		for i := range v.Len() {
+			redactValue(v.Index(i), sensitive)
+		}
+	case reflect.Map:
+		if v.Len() == 0 || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			redactValue(value, sensitive || IsSensitiveKey(iter.Key().String()))
+			copied.SetMapIndex(iter.Key(), value)
+		}
+		v.Set(copied)
+	case reflect.String:
+		if sensitive && v.String() != "" {
+			v.SetString(SecretRedacted)
+		}
+	}
+}
+
+// restoreValue walks a secret map from new & current config together, copying current values wherever new config holds the redacted placeholder
+func restoreValue(v reflect.Value, current reflect.Value, sensitive bool, path string) error {
+	current = unwrapInterface(current)
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		value := reflect.New(v.Elem().Type()).Elem()
+		value.Set(v.Elem())
+		if err := restoreValue(value, current, sensitive, path); err != nil {
+			return err
+		}
+		v.Set(value)
+	case reflect.Slice:
+		if current.IsValid() && current.Kind() != reflect.Slice {
+			current = reflect.Value{}
+		}
+		matches, err := matchItems(v, current, path, func(item reflect.Value) { redactValue(item, sensitive) })
+		if err != nil {
+			return err
+		}
+		for i, match := range matches {
+			var currentItem reflect.Value
+			if match >= 0 {
+				currentItem = current.Index(match)
+			}
+			if err := restoreValue(v.Index(i), currentItem, sensitive, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			var currentItem reflect.Value
+			if current.IsValid() && current.Kind() == reflect.Map && !current.IsNil() {
+				currentItem = current.MapIndex(iter.Key())
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			key := iter.Key().String()
+			if err := restoreValue(value, currentItem, sensitive || IsSensitiveKey(key), path+"."+key); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.String:
+		if v.String() != SecretRedacted {
+			return nil
+		}
+		var value string
+		if current.IsValid() && current.Kind() == reflect.String {
+			value = current.String()
+		}
+		v.SetString(value)
+	}
+	return nil
+}
+
+// matchItems finds the item in current config that each item of a new list replaces, or -1 for new items.
+// Items are matched by profile name first, then by comparing non-secret values. Items that were edited
+// are only matched by position if the list has the same length & all other items are in the same order.
+// Returns an error if an unmatched item holds the redacted placeholder, as its secrets can't be restored
+func matchItems(v reflect.Value, current reflect.Value, path string, redact func(item reflect.Value)) ([]int, error) {
+	matches := make([]int, v.Len())
+	for i := range matches {
+		matches[i] = -1
+	}
+	if !current.IsValid() || current.Len() == 0 {
+		return matches, checkUnmatched(v, matches, path)
+	}
+
+	used := make([]bool, current.Len())
+	match := func(i, j int) {
+		matches[i] = j
+		used[j] = true
+	}
+
+	// Match by profile name, where it is unique in both lists
+	for i := range v.Len() {
+		name := itemName(v.Index(i))
+		if name == "" || countNames(v, name) != 1 || countNames(current, name) != 1 {
+			continue
+		}
+		for j := range current.Len() {
+			if itemName(current.Index(j)) == name {
+				match(i, j)
+			}
+		}
+	}
+
+	// Match unchanged items by comparing with redacted current values
+	redacted := make([][]byte, current.Len())
+	for j := range current.Len() {
+		item := reflect.New(current.Type().Elem()).Elem()
+		item.Set(current.Index(j))
+		redact(item)
+		redacted[j], _ = json.Marshal(item.Interface())
+	}
+	for i := range v.Len() {
+		if matches[i] >= 0 {
+			continue
+		}
+		value, _ := json.Marshal(v.Index(i).Interface())
+		var candidates []int
+		for j := range current.Len() {
+			if !used[j] && bytes.Equal(value, redacted[j]) {
+				candidates = append(candidates, j)
+			}
+		}
+		switch {
+		case len(candidates) == 1:
+			match(i, candidates[0])
+		case len(candidates) > 1 && v.Len() == current.Len() && slices.Contains(candidates, i):
+			match(i, i)
+		}
+	}
+
+	// Fall back to position for edited items, only if the list was not resized or reordered
+	if v.Len() == current.Len() {
+		inOrder := true
+		for i, j := range matches {
+			if j >= 0 && j != i {
+				inOrder = false
+			}
+		}
+		for i, j := range matches {
+			if inOrder && j < 0 && !used[i] {
+				match(i, i)
+			}
+		}
+	}
+
+	return matches, checkUnmatched(v, matches, path)
+}
+
+// checkUnmatched returns an error if a new list item holds the redacted placeholder without a matching current item
+func checkUnmatched(v reflect.Value, matches []int, path string) error {
+	for i, j := range matches {
+		if j < 0 && hasRedacted(v.Index(i)) {
+			return fmt.Errorf("unable to match %s[%d] to existing config to keep %s values. Set a unique name, or send secret values again", path, i, SecretRedacted)
+		}
+	}
+	return nil
+}
+
+// itemName returns the name of a notification profile, used to match profiles when restoring secrets
+func itemName(v reflect.Value) string {
+	v = unwrapInterface(v)
+	switch v.Kind() {
+	case reflect.Struct:
+		if name := v.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+			return name.String()
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String && !v.IsNil() {
+			if name := unwrapInterface(v.MapIndex(reflect.ValueOf("name").Convert(v.Type().Key()))); name.IsValid() && name.Kind() == reflect.String {
+				return name.String()
+			}
+		}
+	}
+	return ""
+}
+
+// countNames returns how many items in a list have the given name
+func countNames(v reflect.Value, name string) int {
+	count := 0
+	for i := range v.Len() {
+		if itemName(v.Index(i)) == name {
+			count++
+		}
+	}
+	return count
+}
+
+// hasRedacted returns whether a value contains the redacted placeholder anywhere
+func hasRedacted(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		return !v.IsNil() && hasRedacted(v.Elem())
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() && hasRedacted(v.Field(i)) {
+				return true
+			}
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			if hasRedacted(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if hasRedacted(iter.Value()) {
+				return true
+			}
+		}
+	case reflect.String:
+		return v.String() == SecretRedacted
+	}
+	return false
+}
+
+// RedactKeys returns a copy of a value, such as a list of HTTP headers, with values under credential-like names
+// replaced, ex: "Authorization". Used to log requests to notification providers
+func RedactKeys[T any](v T) T {
+	value := reflect.ValueOf(&v).Elem()
+	redactValue(value, false)
+	return v
+}
+
+// unwrapInterface returns the value held by an interface, or an invalid value if it is nil
+func unwrapInterface(v reflect.Value) reflect.Value {
+	if v.IsValid() && v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		return v.Elem()
+	}
+	return v
+}
+
+// fieldOrZero returns a struct field, or an invalid value if struct does not exist in current config
+func fieldOrZero(v reflect.Value, i int) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Value{}
+	}
+	return v.Field(i)
+}
+
+// IsSensitiveKey returns whether a map key, such as an HTTP header name, may contain credentials
+func IsSensitiveKey(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
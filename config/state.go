@@ -0,0 +1,230 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// StateStore holds runtime app status, which is updated & read from many goroutines
+type StateStore struct {
+	lock           sync.RWMutex
+	status         models.Status
+	frigateVersion int
+	// Reverts notification state when a timed change expires
+	notifTimer *time.Timer
+	// Notification state from before a timed change, restored when notifTimer fires
+	notifPrevious bool
+}
+
+// State is the current runtime status of the app
+var State = newStateStore()
+
+func newStateStore() *StateStore {
+	s := &StateStore{}
+	s.status.Notifications.Enabled = true
+	s.status.Health = "n/a"
+	s.status.API = "n/a"
+	s.status.Frigate.API = "n/a"
+	s.status.Frigate.MQTT = "n/a"
+	s.status.Monitor = "n/a"
+	return s
+}
+
+// Status returns a copy of current app status
+func (s *StateStore) Status() models.Status {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	status := s.status
+	status.Notifications.Providers = make(map[string][]models.NotifierStatus, len(s.status.Notifications.Providers))
+	for provider, profiles := range s.status.Notifications.Providers {
+		status.Notifications.Providers[provider] = append([]models.NotifierStatus(nil), profiles...)
+	}
+	if !status.Notifications.Until.IsZero() {
+		status.NotifTimer = &models.NotifTimer{
+			Until:            status.Notifications.Until,
+			RemainingSeconds: int64(status.Notifications.Remaining().Seconds()),
+		}
+	}
+	return status
+}
+
+// Health returns overall health of the app
+func (s *StateStore) Health() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.status.Health
+}
+
+// SetHealth updates overall health of the app
+func (s *StateStore) SetHealth(health string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.Health = health
+}
+
+// SetAPI updates health of the Frigate-Notify API server
+func (s *StateStore) SetAPI(status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.API = status
+}
+
+// SetMonitor updates health of reporting to external health monitor
+func (s *StateStore) SetMonitor(status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.Monitor = status
+}
+
+// FrigateAPI returns health of connection to Frigate via API
+func (s *StateStore) FrigateAPI() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.status.Frigate.API
+}
+
+// SetFrigateAPI updates health of connection to Frigate via API
+func (s *StateStore) SetFrigateAPI(status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.Frigate.API = status
+}
+
+// FrigateMQTT returns health of connection to Frigate via MQTT
+func (s *StateStore) FrigateMQTT() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.status.Frigate.MQTT
+}
+
+// SetFrigateMQTT updates health of connection to Frigate via MQTT
+func (s *StateStore) SetFrigateMQTT(status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.Frigate.MQTT = status
+}
+
+// SetLastEvent updates timestamp of last event received from Frigate
+func (s *StateStore) SetLastEvent(t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.LastEvent = t
+}
+
+// SetLastNotification updates timestamp of last notification sent
+func (s *StateStore) SetLastNotification(t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.LastNotification = t
+}
+
+// NotificationsEnabled returns whether app is enabled for sending notifications
+func (s *StateStore) NotificationsEnabled() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.status.Notifications.Enabled
+}
+
+// SetNotificationsEnabled enables or disables sending notifications until changed again.
+// Cancels any pending timed change
+func (s *StateStore) SetNotificationsEnabled(enabled bool) {
+	s.SetNotificationsEnabledUntil(enabled, time.Time{})
+}
+
+// SetNotificationsEnabledUntil enables or disables sending notifications, then restores the state
+// from before the change at until. If a timed change is already pending, the state from before that
+// change is restored instead. If until is zero, or state is unchanged, it is kept until changed again
+func (s *StateStore) SetNotificationsEnabledUntil(enabled bool, until time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	previous := s.status.Notifications.Enabled
+	if s.notifTimer != nil {
+		previous = s.notifPrevious
+		s.notifTimer.Stop()
+		s.notifTimer = nil
+	}
+	if enabled == previous {
+		until = time.Time{}
+	}
+	s.status.Notifications.Enabled = enabled
+	s.status.Notifications.Until = until
+	if until.IsZero() {
+		return
+	}
+	s.notifPrevious = previous
+	s.notifTimer = time.AfterFunc(time.Until(until), func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		// Skip if state was changed again before timer fired
+		if !s.status.Notifications.Until.Equal(until) {
+			return
+		}
+		s.status.Notifications.Enabled = previous
+		s.status.Notifications.Until = time.Time{}
+		s.notifTimer = nil
+		log.Info().
+			Bool("state", previous).
+			Msg("Notification state restored after timed change")
+	})
+}
+
+// NotificationsUntil returns when notification state will revert, or zero if not scheduled
+func (s *StateStore) NotificationsUntil() time.Time {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.status.Notifications.Until
+}
+
+// NotifSuccess records a notification sent via a provider profile.
+// Ignored if the profile was removed by a config reload
+func (s *StateStore) NotifSuccess(provider string, id int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if status := s.status.Notifications.Get(provider, id); status != nil {
+		status.NotifSuccess()
+	}
+}
+
+// NotifFailure records a failure to send notification via a provider profile.
+// Ignored if the profile was removed by a config reload
+func (s *StateStore) NotifFailure(provider string, id int, message string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if status := s.status.Notifications.Get(provider, id); status != nil {
+		status.NotifFailure(message)
+	}
+}
+
+// FrigateVersion returns major version of the connected Frigate server
+func (s *StateStore) FrigateVersion() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.frigateVersion
+}
+
+// SetFrigateVersion updates major version of the connected Frigate server
+func (s *StateStore) SetFrigateVersion(version int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.frigateVersion = version
+}
+
+// resetProviders replaces provider status to match profiles in a newly applied config
+func (s *StateStore) resetProviders(c *Config) {
+	providers := make(map[string][]models.NotifierStatus)
+	for _, n := range Notifiers() {
+		profiles := n.Profiles(c)
+		status := make([]models.NotifierStatus, len(profiles))
+		for id, profile := range profiles {
+			status[id].InitNotifStatus(id, profile.Common().Enabled)
+		}
+		providers[n.Name()] = status
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status.Notifications.Providers = providers
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetNotificationsEnabledUntil(t *testing.T) {
+	s := newStateStore()
+
+	// Timed change restores state from before the change
+	s.SetNotificationsEnabledUntil(false, time.Now().Add(50*time.Millisecond))
+	if s.NotificationsEnabled() || s.NotificationsUntil().IsZero() {
+		t.Errorf("Expected: disabled with timer, Got: %v until %v", s.NotificationsEnabled(), s.NotificationsUntil())
+	}
+	time.Sleep(100 * time.Millisecond)
+	if !s.NotificationsEnabled() || !s.NotificationsUntil().IsZero() {
+		t.Errorf("Expected: enabled without timer, Got: %v until %v", s.NotificationsEnabled(), s.NotificationsUntil())
+	}
+
+	// Replacing a pending change still restores the original state
+	s.SetNotificationsEnabledUntil(false, time.Now().Add(time.Hour))
+	s.SetNotificationsEnabledUntil(true, time.Now().Add(50*time.Millisecond))
+	if !s.NotificationsUntil().IsZero() {
+		t.Errorf("Expected: no timer when state matches original, Got: %v", s.NotificationsUntil())
+	}
+	s.SetNotificationsEnabledUntil(false, time.Now().Add(time.Hour))
+	s.SetNotificationsEnabledUntil(false, time.Now().Add(50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	if !s.NotificationsEnabled() {
+		t.Error("Expected: enabled after replaced timed change")
+	}
+
+	// Timed request that doesn't change state is kept until changed again
+	s.SetNotificationsEnabled(false)
+	s.SetNotificationsEnabledUntil(false, time.Now().Add(50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	if s.NotificationsEnabled() || !s.NotificationsUntil().IsZero() {
+		t.Errorf("Expected: disabled without timer, Got: %v until %v", s.NotificationsEnabled(), s.NotificationsUntil())
+	}
+}
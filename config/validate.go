@@ -1,8 +1,13 @@
 package config
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"html/template"
+	"net"
+	"os"
 	"slices"
 	"strings"
 	"time"
@@ -36,6 +41,11 @@ func (c *Config) Validate() []string {
 	// Validate Internal settings
 	c.validateInternal()
 
+	// Validate storage, queue, delivery & history settings
+	if results := c.validateQueue(); len(results) > 0 {
+		validationErrors = append(validationErrors, results...)
+	}
+
 	// Validate Frigate polling method
 	if results := c.validateFrigatePolling(); len(results) > 0 {
 		validationErrors = append(validationErrors, results...)
@@ -71,125 +81,31 @@ func (c *Config) Validate() []string {
 		validationErrors = append(validationErrors, results...)
 	}
 
-	// Validate Apprise API
-	Internal.Status.Notifications.AppriseAPI = make([]models.NotifierStatus, len(c.Alerts.AppriseAPI))
-	for id, profile := range c.Alerts.AppriseAPI {
-		Internal.Status.Notifications.AppriseAPI[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateAppriseAPI(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Discord
-	Internal.Status.Notifications.Discord = make([]models.NotifierStatus, len(c.Alerts.Discord))
-	for id, profile := range c.Alerts.Discord {
-		Internal.Status.Notifications.Discord[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateDiscord(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Gotify
-	Internal.Status.Notifications.Gotify = make([]models.NotifierStatus, len(c.Alerts.Gotify))
-	for id, profile := range c.Alerts.Gotify {
-		Internal.Status.Notifications.Gotify[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateGotify(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Matrix
-	Internal.Status.Notifications.Matrix = make([]models.NotifierStatus, len(c.Alerts.Matrix))
-	for id, profile := range c.Alerts.Matrix {
-		Internal.Status.Notifications.Matrix[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateMatrix(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Mattermost
-	Internal.Status.Notifications.Mattermost = make([]models.NotifierStatus, len(c.Alerts.Mattermost))
-	for id, profile := range c.Alerts.Mattermost {
-		Internal.Status.Notifications.Mattermost[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateMattermost(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Ntfy
-	Internal.Status.Notifications.Ntfy = make([]models.NotifierStatus, len(c.Alerts.Ntfy))
-	for id, profile := range c.Alerts.Ntfy {
-		Internal.Status.Notifications.Ntfy[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateNtfy(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Pushover
-	Internal.Status.Notifications.Pushover = make([]models.NotifierStatus, len(c.Alerts.Pushover))
-	for id, profile := range c.Alerts.Pushover {
-		Internal.Status.Notifications.Pushover[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validatePushover(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate Signal
-	Internal.Status.Notifications.Signal = make([]models.NotifierStatus, len(c.Alerts.Signal))
-	for id, profile := range c.Alerts.Signal {
-		Internal.Status.Notifications.Signal[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateSignal(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
-	}
-
-	// Validate SMTP
-	Internal.Status.Notifications.SMTP = make([]models.NotifierStatus, len(c.Alerts.SMTP))
-	for id, profile := range c.Alerts.SMTP {
-		Internal.Status.Notifications.SMTP[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateSMTP(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
+	// Validate cooldown settings
+	if results := c.validateCooldown(); len(results) > 0 {
+		validationErrors = append(validationErrors, results...)
 	}
 
-	// Validate Telegram
-	Internal.Status.Notifications.Telegram = make([]models.NotifierStatus, len(c.Alerts.Telegram))
-	for id, profile := range c.Alerts.Telegram {
-		Internal.Status.Notifications.Telegram[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateTelegram(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
+	// Validate notification providers
+	for _, n := range Notifiers() {
+		for id, p := range n.Profiles(c) {
+			if profile := p.Common(); profile.Enabled {
+				if results := n.Validate(c, id, p); len(results) > 0 {
+					validationErrors = append(validationErrors, results...)
+				}
+				if profile.Cooldown < 0 {
+					validationErrors = append(validationErrors, fmt.Sprintf("Invalid cooldown for %s! Profile ID %v", n.Name(), id))
+				}
+				if profile.Digest.Enabled && profile.Digest.Interval != 0 && profile.Digest.Interval < 60 {
+					validationErrors = append(validationErrors, fmt.Sprintf("Digest interval for %s must be at least 60 seconds! Profile ID %v", n.Name(), id))
+				}
 			}
 		}
 	}
 
-	// Validate Webhook
-	Internal.Status.Notifications.Webhook = make([]models.NotifierStatus, len(c.Alerts.Webhook))
-	for id, profile := range c.Alerts.Webhook {
-		Internal.Status.Notifications.Webhook[id].InitNotifStatus(id, profile.Enabled)
-		if profile.Enabled {
-			if results := c.validateWebhook(id); len(results) > 0 {
-				validationErrors = append(validationErrors, results...)
-			}
-		}
+	// Validate provider fallbacks
+	if results := c.validateFallbacks(); len(results) > 0 {
+		validationErrors = append(validationErrors, results...)
 	}
 
 	// Validate that at least one alert profile is enabled
@@ -214,7 +130,7 @@ func (c *Config) validateAppMode() []string {
 	if strings.ToLower(c.App.Mode) != "events" && strings.ToLower(c.App.Mode) != "reviews" {
 		appErrors = append(appErrors, "MQTT mode must be 'events' or 'reviews'")
 	}
-	if Internal.FrigateVersion < 14 && strings.ToLower(c.App.Mode) == "reviews" {
+	if State.FrigateVersion() < 14 && strings.ToLower(c.App.Mode) == "reviews" {
 		appErrors = append(appErrors, "Frigate must be version 0.14 or higher to use 'reviews' mode. Please use 'events' mode or update Frigate.")
 	}
 	log.Debug().Msgf("App mode: %v", c.App.Mode)
@@ -229,10 +145,70 @@ func (c *Config) validateAPI() []string {
 	if c.App.API.Port <= 0 || c.App.API.Port > 65535 {
 		apiErrors = append(apiErrors, "Invalid API port")
 	}
+	if c.App.API.Address == "" {
+		c.App.API.Address = "0.0.0.0"
+	}
+	if net.ParseIP(c.App.API.Address) == nil && c.App.API.Address != "localhost" {
+		apiErrors = append(apiErrors, "Invalid API listen address: "+c.App.API.Address)
+	}
+
+	// Check TLS certificate can be loaded
+	if c.App.API.TLS.Enabled {
+		if c.App.API.TLS.Cert == "" || c.App.API.TLS.Key == "" {
+			apiErrors = append(apiErrors, "API TLS certificate & key files must be specified")
+		} else if _, err := tls.LoadX509KeyPair(c.App.API.TLS.Cert, c.App.API.TLS.Key); err != nil {
+			apiErrors = append(apiErrors, "Unable to load API TLS certificate: "+err.Error())
+		}
+	}
+
+	// Check API credentials
+	for id, token := range c.App.API.Auth.Tokens {
+		if token.Token == "" {
+			apiErrors = append(apiErrors, fmt.Sprintf("API token must be specified! Token ID %v", id))
+		}
+		if len(token.Scopes) == 0 {
+			c.App.API.Auth.Tokens[id].Scopes = []string{"read"}
+		}
+		if result := validateAPIScopes(token.Scopes); result != "" {
+			apiErrors = append(apiErrors, fmt.Sprintf("%s Token ID %v", result, id))
+		}
+	}
+	for id, user := range c.App.API.Auth.Users {
+		if user.Username == "" || user.Password == "" {
+			apiErrors = append(apiErrors, fmt.Sprintf("API username & password must be specified! User ID %v", id))
+		}
+		if len(user.Scopes) == 0 {
+			c.App.API.Auth.Users[id].Scopes = []string{"read"}
+		}
+		if result := validateAPIScopes(user.Scopes); result != "" {
+			apiErrors = append(apiErrors, fmt.Sprintf("%s User ID %v", result, id))
+		}
+	}
+
+	// Check hosts manual alerts may download images from
+	for _, host := range c.App.API.AllowedImageHosts {
+		if strings.Contains(host, "/") {
+			if _, _, err := net.ParseCIDR(host); err != nil {
+				apiErrors = append(apiErrors, "Invalid allowed image host CIDR range: "+host)
+			}
+		} else if host == "" || (strings.ContainsAny(host, ":@") && net.ParseIP(host) == nil) {
+			apiErrors = append(apiErrors, "Invalid allowed image host, must be a host name, IP address or CIDR range: "+host)
+		}
+	}
 
 	return apiErrors
 }
 
+// validateAPIScopes checks that only known API scopes are granted
+func validateAPIScopes(scopes []string) string {
+	for _, scope := range scopes {
+		if !slices.Contains([]string{"read", "control", "config"}, scope) {
+			return fmt.Sprintf("Invalid API scope: %s! Must be read, control or config.", scope)
+		}
+	}
+	return ""
+}
+
 func (c *Config) validateInternal() {
 	// Set defaults
 	if c.App.Internal.HTTP.Timeout == 0 {
@@ -245,6 +221,58 @@ func (c *Config) validateInternal() {
 	util.HTTPTimeout = c.App.Internal.HTTP.Timeout
 }
 
+func (c *Config) validateQueue() []string {
+	var queueErrors []string
+	// Set defaults
+	if c.App.Storage.Path == "" {
+		c.App.Storage.Path = "./data/frigate-notify.db"
+	}
+	if c.App.Queue.MaxAttempts == 0 {
+		c.App.Queue.MaxAttempts = 10
+	}
+	if c.App.Queue.InitialDelay == 0 {
+		c.App.Queue.InitialDelay = 5
+	}
+	if c.App.Queue.MaxDelay == 0 {
+		c.App.Queue.MaxDelay = 300
+	}
+	if c.App.Delivery.Workers == 0 {
+		c.App.Delivery.Workers = 10
+	}
+	if c.App.Delivery.DrainTimeout == 0 {
+		c.App.Delivery.DrainTimeout = 30
+	}
+	if c.App.History.Retention == 0 {
+		c.App.History.Retention = 7
+	}
+
+	if c.App.Queue.MaxAttempts < 0 {
+		queueErrors = append(queueErrors, "Queue max_attempts must be greater than 0")
+	}
+	if c.App.Queue.InitialDelay < 0 || c.App.Queue.MaxDelay < 0 {
+		queueErrors = append(queueErrors, "Queue retry delays must be greater than 0")
+	}
+	if c.App.Queue.MaxDelay < c.App.Queue.InitialDelay {
+		queueErrors = append(queueErrors, "Queue max_delay must be greater than or equal to initial_delay")
+	}
+	if c.App.Delivery.Workers < 0 {
+		queueErrors = append(queueErrors, "Delivery workers must be greater than 0")
+	}
+	if c.App.Delivery.DrainTimeout < 0 {
+		queueErrors = append(queueErrors, "Delivery drain_timeout must be greater than 0")
+	}
+	if c.App.History.Retention < 0 {
+		queueErrors = append(queueErrors, "History retention must be greater than 0")
+	}
+	log.Debug().
+		Bool("enabled", c.App.Queue.Enabled).
+		Str("path", c.App.Storage.Path).
+		Int("workers", c.App.Delivery.Workers).
+		Msg("Notification queue settings")
+
+	return queueErrors
+}
+
 func (c *Config) validateFrigatePolling() []string {
 	var pollingErrors []string
 	webapi := c.Frigate.WebAPI.Enabled
@@ -263,6 +291,9 @@ func (c *Config) validateFrigatePolling() []string {
 	}
 	if mqtt {
 		log.Debug().Msgf("Event polling method: MQTT")
+		if c.Frigate.MQTT.Failover {
+			log.Debug().Msgf("Event polling failover: Web API, every %v seconds", c.Frigate.WebAPI.Interval)
+		}
 	}
 
 	// Warn on test mode being enabled
@@ -333,7 +364,7 @@ func (c *Config) validateFrigateServer() []string {
 	}
 
 	// Check HTTP header template syntax
-	if msg := validateTemplate("Frigate HTTP Headers", c.Alerts.General.Title); msg != "" {
+	if msg := ValidateTemplate("Frigate HTTP Headers", c.Alerts.General.Title); msg != "" {
 		connectivityErrors = append(connectivityErrors, msg)
 	}
 
@@ -342,9 +373,9 @@ func (c *Config) validateFrigateServer() []string {
 	current_attempt := 1
 	var version int
 	for current_attempt < max_attempts {
-		version, err = util.GetFrigateVersion(c.Frigate.Headers)
+		version, err = util.GetFrigateVersion(context.Background(), c.Frigate.Headers)
 		if err != nil {
-			Internal.Status.Frigate.API = "unreachable"
+			State.SetFrigateAPI("unreachable")
 			log.Warn().
 				Err(err).
 				Int("attempt", current_attempt).
@@ -354,12 +385,12 @@ func (c *Config) validateFrigateServer() []string {
 			time.Sleep(time.Duration(interval) * time.Second)
 			current_attempt += 1
 		} else {
-			Internal.FrigateVersion = version
+			State.SetFrigateVersion(version)
 			break
 		}
 	}
 	if current_attempt == max_attempts {
-		Internal.Status.Frigate.API = "unreachable"
+		State.SetFrigateAPI("unreachable")
 		log.Error().
 			Err(err).
 			Msgf("Max attempts reached - Cannot reach Frigate server at %v", url)
@@ -367,8 +398,8 @@ func (c *Config) validateFrigateServer() []string {
 	}
 
 	log.Info().Msgf("Successfully connected to %v", url)
-	Internal.Status.Frigate.API = "ok"
-	log.Debug().Msgf("Frigate server version: %v", Internal.FrigateVersion)
+	State.SetFrigateAPI("ok")
+	log.Debug().Msgf("Frigate server version: %v", State.FrigateVersion())
 	return connectivityErrors
 }
 
@@ -392,6 +423,54 @@ func (c *Config) validateMQTT() []string {
 		configErrors = append(configErrors, "MQTT user provided, but no password")
 	}
 
+	if c.Frigate.MQTT.QoS < 0 || c.Frigate.MQTT.QoS > 2 {
+		configErrors = append(configErrors, "MQTT QoS must be 0, 1 or 2")
+	}
+
+	// Check connection retry settings
+	if c.Frigate.MQTT.Retry.Attempts < 0 {
+		configErrors = append(configErrors, "MQTT retry attempts must be 0 or greater")
+	}
+	if c.Frigate.MQTT.Retry.Interval <= 0 {
+		c.Frigate.MQTT.Retry.Interval = 10
+	}
+	if c.Frigate.MQTT.Retry.MaxInterval <= 0 {
+		c.Frigate.MQTT.Retry.MaxInterval = 300
+	}
+	if c.Frigate.MQTT.Retry.MaxInterval < c.Frigate.MQTT.Retry.Interval {
+		c.Frigate.MQTT.Retry.MaxInterval = c.Frigate.MQTT.Retry.Interval
+	}
+
+	// Check transport & TLS settings
+	c.Frigate.MQTT.Protocol = strings.ToLower(c.Frigate.MQTT.Protocol)
+	if c.Frigate.MQTT.Protocol == "" {
+		c.Frigate.MQTT.Protocol = "tcp"
+	}
+	if !slices.Contains([]string{"tcp", "ssl", "ws", "wss"}, c.Frigate.MQTT.Protocol) {
+		configErrors = append(configErrors, "MQTT protocol must be 'tcp', 'ssl', 'ws' or 'wss'")
+	}
+	if c.Frigate.MQTT.Path != "" && !strings.HasPrefix(c.Frigate.MQTT.Protocol, "ws") {
+		log.Warn().Msg("MQTT path is only used for websocket connections")
+	}
+	mqttTLS := c.Frigate.MQTT.TLS
+	if mqttTLS != (models.MQTTTLS{}) && c.Frigate.MQTT.Protocol != "ssl" && c.Frigate.MQTT.Protocol != "wss" {
+		log.Warn().Msg("MQTT TLS settings are only used with 'ssl' or 'wss' protocol")
+	}
+	if mqttTLS.CA != "" {
+		if ca, err := os.ReadFile(mqttTLS.CA); err != nil {
+			configErrors = append(configErrors, "Unable to load MQTT CA certificate: "+err.Error())
+		} else if !x509.NewCertPool().AppendCertsFromPEM(ca) {
+			configErrors = append(configErrors, "Unable to load MQTT CA certificate: no valid PEM certificates found")
+		}
+	}
+	if (mqttTLS.Cert == "") != (mqttTLS.Key == "") {
+		configErrors = append(configErrors, "MQTT client certificate & key files must both be specified")
+	} else if mqttTLS.Cert != "" {
+		if _, err := tls.LoadX509KeyPair(mqttTLS.Cert, mqttTLS.Key); err != nil {
+			configErrors = append(configErrors, "Unable to load MQTT client certificate: "+err.Error())
+		}
+	}
+
 	return configErrors
 }
 
@@ -459,7 +538,7 @@ func (c *Config) validateAlertGeneral() []string {
 	log.Debug().Msgf("Notify on Detections: %v", c.Alerts.General.NotifyDetections)
 
 	// Check title template syntax
-	if msg := validateTemplate("Alert Title", c.Alerts.General.Title); msg != "" {
+	if msg := ValidateTemplate("Alert Title", c.Alerts.General.Title); msg != "" {
 		alertErrors = append(alertErrors, msg)
 	}
 	if c.Alerts.General.MaxSnapRetry == 0 {
@@ -564,318 +643,85 @@ func (c *Config) validateLabelFiltering() []string {
 	return labelErrors
 }
 
-func (c *Config) validateAppriseAPI(id int) []string {
-	var appriseapiErrors []string
-	log.Debug().Msgf("Alerting enabled for Apprise API profile ID %v", id)
-	if c.Alerts.AppriseAPI[id].Server == "" {
-		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("No Apprise API server specified! Profile ID %v", id))
+func (c *Config) validateCooldown() []string {
+	var cooldownErrors []string
+	if c.Alerts.Cooldown.Duration < 0 {
+		cooldownErrors = append(cooldownErrors, "Cooldown duration must be 0 or greater")
 	}
-	if c.Alerts.AppriseAPI[id].Token == "" && len(c.Alerts.AppriseAPI[id].URLs) == 0 {
-		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("No Apprise API token or notification URLs specified! Profile ID %v", id))
-	}
-	if c.Alerts.AppriseAPI[id].Token != "" && len(c.Alerts.AppriseAPI[id].URLs) != 0 {
-		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("Only Apprise API token or notification URLs may be configured, not both! Profile ID %v", id))
-	}
-	if c.Alerts.AppriseAPI[id].Token != "" && len(c.Alerts.AppriseAPI[id].Tags) == 0 {
-		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("If using Apprise API token, tags must also be configured! Profile ID %v", id))
-	}
-
-	// Check if Apprise API server URL contains protocol, assume HTTP if not specified
-	if !strings.Contains(c.Alerts.AppriseAPI[id].Server, "http://") && !strings.Contains(c.Alerts.AppriseAPI[id].Server, "https://") {
-		log.Debug().Msgf("No protocol specified on Apprise API Server. Assuming http://. If this is incorrect, please adjust the config file. Profile ID %v", id)
-		c.Alerts.AppriseAPI[id].Server = fmt.Sprintf("http://%s", c.Alerts.AppriseAPI[id].Server)
-	}
-	// Check template syntax
-	if msg := validateTemplate("Apprise API", c.Alerts.AppriseAPI[id].Template); msg != "" {
-		appriseapiErrors = append(appriseapiErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return appriseapiErrors
-}
-
-func (c *Config) validateDiscord(id int) []string {
-	var discordErrors []string
-	log.Debug().Msgf("Alerting enabled for Discord profile ID %v", id)
-	if c.Alerts.Discord[id].Webhook == "" {
-		discordErrors = append(discordErrors, fmt.Sprintf("No Discord webhook specified! Profile ID %v", id))
-	}
-	// Check template syntax
-	if msg := validateTemplate("Discord", c.Alerts.Discord[id].Template); msg != "" {
-		discordErrors = append(discordErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return discordErrors
-}
-
-func (c *Config) validateGotify(id int) []string {
-	var gotifyErrors []string
-	log.Debug().Msgf("Alerting enabled for Gotify profile ID %v", id)
-	if c.Alerts.Gotify[id].Server == "" {
-		gotifyErrors = append(gotifyErrors, fmt.Sprintf("No Gotify server specified! Profile ID %v", id))
-	}
-	if c.Alerts.Gotify[id].Token == "" {
-		gotifyErrors = append(gotifyErrors, fmt.Sprintf("No Gotify token specified! Profile ID %v", id))
-	}
-	// Check if Gotify server URL contains protocol, assume HTTP if not specified
-	if !strings.Contains(c.Alerts.Gotify[id].Server, "http://") && !strings.Contains(c.Alerts.Gotify[id].Server, "https://") {
-		log.Debug().Msgf("No protocol specified on Gotify Server. Assuming http://. If this is incorrect, please adjust the config file. Profile ID %v", id)
-		c.Alerts.Gotify[id].Server = fmt.Sprintf("http://%s", c.Alerts.Gotify[id].Server)
-	}
-	// Check template syntax
-	if msg := validateTemplate("Gotify", c.Alerts.Gotify[id].Template); msg != "" {
-		gotifyErrors = append(gotifyErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return gotifyErrors
-}
-
-func (c *Config) validateMatrix(id int) []string {
-	var matrixErrors []string
-	log.Debug().Msgf("Alerting enabled for Matrix profile ID %v", id)
-	if c.Alerts.Matrix[id].Server == "" {
-		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix homeserver specified! Profile ID %v", id))
-	}
-	// Check username / auth token
-	if c.Alerts.Matrix[id].Username == "" {
-		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix username specified! Profile ID %v", id))
-	}
-	if c.Alerts.Matrix[id].Password == "" {
-		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix password specified! Profile ID %v", id))
-	}
-	if c.Alerts.Matrix[id].RoomID == "" {
-		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix room ID specified! Profile ID %v", id))
-	}
-	// Check template syntax
-	if msg := validateTemplate("Matrix", c.Alerts.Matrix[id].Template); msg != "" {
-		matrixErrors = append(matrixErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return matrixErrors
-}
-
-func (c *Config) validateMattermost(id int) []string {
-	var mattermostErrors []string
-	log.Debug().Msgf("Alerting enabled for Mattermost profile ID %v", id)
-	if c.Alerts.Mattermost[id].Webhook == "" {
-		mattermostErrors = append(mattermostErrors, fmt.Sprintf("No Mattermost webhook specified! Profile ID %v", id))
-	}
-	// Set default priority if not specified
-	if c.Alerts.Mattermost[id].Priority == "" {
-		c.Alerts.Mattermost[id].Priority = "standard"
-	}
-	// Check valid priority if set
-	validPriorities := []string{"standard", "important", "urgent"}
-	if !slices.Contains(validPriorities, strings.ToLower(c.Alerts.Mattermost[id].Priority)) {
-		mattermostErrors = append(mattermostErrors, fmt.Sprintf("Invalid priority for Mattermost (valid: standard, urgent, or important). Profile ID %v", id))
-	}
-	// Check template syntax
-	if msg := validateTemplate("Mattermost", c.Alerts.Mattermost[id].Template); msg != "" {
-		mattermostErrors = append(mattermostErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return mattermostErrors
-}
-
-func (c *Config) validateNtfy(id int) []string {
-	var ntfyErrors []string
-	log.Debug().Msgf("Alerting enabled for Ntfy profile ID %v", id)
-	if c.Alerts.Ntfy[id].Server == "" {
-		ntfyErrors = append(ntfyErrors, fmt.Sprintf("No Ntfy server specified! Profile ID %v", id))
-	}
-	if c.Alerts.Ntfy[id].Topic == "" {
-		ntfyErrors = append(ntfyErrors, fmt.Sprintf("No Ntfy topic specified! Profile ID %v", id))
-	}
-	// Check template syntax
-	if msg := validateTemplate("Ntfy", c.Alerts.Ntfy[id].Template); msg != "" {
-		ntfyErrors = append(ntfyErrors, msg+fmt.Sprintf("Profile ID %v", id))
-	}
-
-	// Check HTTP header template syntax
-	if msg := validateTemplate("Ntfy HTTP Headers", c.Alerts.General.Title); msg != "" {
-		ntfyErrors = append(ntfyErrors, msg+fmt.Sprintf("Profile ID %v", id))
-	}
-
-	return ntfyErrors
-}
-
-func (c *Config) validatePushover(id int) []string {
-	var pushoverErrors []string
-	log.Debug().Msgf("Alerting enabled for Pushover profile ID %v", id)
-	if c.Alerts.Pushover[id].Token == "" {
-		pushoverErrors = append(pushoverErrors, fmt.Sprintf("No Pushover API token specified! Profile ID %v", id))
-	}
-	if c.Alerts.Pushover[id].Userkey == "" {
-		pushoverErrors = append(pushoverErrors, fmt.Sprintf("No Pushover user key specified! Profile ID %v", id))
-	}
-	if c.Alerts.Pushover[id].Priority < -2 || c.Alerts.Pushover[id].Priority > 2 {
-		pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover priority must be between -2 and 2! Profile ID %v", id))
-	}
-	// Priority 2 is emergency, needs a retry interval & expiration set
-	if c.Alerts.Pushover[id].Priority == 2 {
-		if c.Alerts.Pushover[id].Retry == 0 || c.Alerts.Pushover[id].Expire == 0 {
-			pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover retry interval & expiration must be set with priority 2! Profile ID %v", id))
+	for camera, duration := range c.Alerts.Cooldown.Cameras {
+		if duration < 0 {
+			cooldownErrors = append(cooldownErrors, fmt.Sprintf("Cooldown duration for camera %v must be 0 or greater", camera))
 		}
-		if c.Alerts.Pushover[id].Retry < 30 {
-			pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover retry cannot be less than 30 seconds! Profile ID %v", id))
-		}
-	}
-	if c.Alerts.Pushover[id].TTL < 0 {
-		pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover TTL cannot be negative! Profile ID %v", id))
-	}
-
-	// Check template syntax
-	if msg := validateTemplate("Pushover", c.Alerts.Pushover[id].Template); msg != "" {
-		pushoverErrors = append(pushoverErrors, msg+fmt.Sprintf("Profile ID %v", id))
-	}
-	return pushoverErrors
-}
-
-func (c *Config) validateSignal(id int) []string {
-	var signalErrors []string
-	log.Debug().Msgf("Alerting enabled for Signal profile ID %v", id)
-	if c.Alerts.Signal[id].Server == "" {
-		signalErrors = append(signalErrors, fmt.Sprintf("No Signal server specified! Profile ID %v", id))
-	}
-	if c.Alerts.Signal[id].Account == "" {
-		signalErrors = append(signalErrors, fmt.Sprintf("No Signal account specified! Profile ID %v", id))
-	}
-	// Check if Signal server URL contains protocol, assume HTTP if not specified
-	if !strings.Contains(c.Alerts.Signal[id].Server, "http://") && !strings.Contains(c.Alerts.Signal[id].Server, "https://") {
-		log.Debug().Msgf("No protocol specified on Signal Server. Assuming http://. If this is incorrect, please adjust the config file. Profile ID %v", id)
-		c.Alerts.Signal[id].Server = fmt.Sprintf("http://%s", c.Alerts.Signal[id].Server)
-	}
-	// Check recipients list
-	if len(c.Alerts.Signal[id].Recipients) == 0 {
-		log.Debug().Msgf("No message recipients configured for Signal. Profile ID %v", id)
-	}
-	// Check template syntax
-	if msg := validateTemplate("Signal", c.Alerts.Signal[id].Template); msg != "" {
-		signalErrors = append(signalErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return signalErrors
-}
-
-func (c *Config) validateSMTP(id int) []string {
-	var smtpErrors []string
-	authTypes := []string{"plain", "plain-noenc", "login", "login-noenc", "noauth", "cram-md5", "xoauth2", "scram-sha-1", "scram-sha-1-plus", "scram-sha-256", "scram-sha-256-plus", "autodiscover"}
-	validThreading := []string{"day", "camera", "zone"}
-	log.Debug().Msgf("Alerting enabled for SMTP profile ID %v", id)
-	if c.Alerts.SMTP[id].Server == "" {
-		smtpErrors = append(smtpErrors, fmt.Sprintf("No SMTP server specified! Profile ID %v", id))
-	}
-	if c.Alerts.SMTP[id].Recipient == "" {
-		smtpErrors = append(smtpErrors, fmt.Sprintf("No SMTP recipients specified! Profile ID %v", id))
-	}
-	if c.Alerts.SMTP[id].AuthType == "" {
-		c.Alerts.SMTP[id].AuthType = "plain"
-	}
-	if !slices.Contains(authTypes, strings.ToLower(c.Alerts.SMTP[id].AuthType)) {
-		smtpErrors = append(smtpErrors, fmt.Sprintf("Invalid SMTP Authentication type. Profile ID %v", id))
-	}
-	if c.Alerts.SMTP[id].User != "" && c.Alerts.SMTP[id].Password == "" {
-		smtpErrors = append(smtpErrors, fmt.Sprintf("SMTP username in config, but no password provided! Profile ID %v", id))
-	}
-	if c.Alerts.SMTP[id].Port == 0 {
-		c.Alerts.SMTP[id].Port = 25
 	}
-	if c.Alerts.SMTP[id].Thread == "" {
-		c.Alerts.SMTP[id].Thread = "day"
-	}
-	if !slices.Contains(validThreading, c.Alerts.SMTP[id].Thread) {
-		smtpErrors = append(smtpErrors, fmt.Sprintf("SMTP threading must be `day` or `camera`. Profile ID %v", id))
-	}
-	// Copy `user` to `from` if `from` not explicitly configured
-	if c.Alerts.SMTP[id].From == "" && c.Alerts.SMTP[id].User != "" {
-		c.Alerts.SMTP[id].From = c.Alerts.SMTP[id].User
-	}
-	// Check template syntax
-	if msg := validateTemplate("SMTP", c.Alerts.SMTP[id].Template); msg != "" {
-		smtpErrors = append(smtpErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	for label, duration := range c.Alerts.Cooldown.Labels {
+		if duration < 0 {
+			cooldownErrors = append(cooldownErrors, fmt.Sprintf("Cooldown duration for label %v must be 0 or greater", label))
+		}
 	}
+	log.Debug().
+		Int("duration", c.Alerts.Cooldown.Duration).
+		Interface("cameras", c.Alerts.Cooldown.Cameras).
+		Interface("labels", c.Alerts.Cooldown.Labels).
+		Msg("Alert cooldown settings")
 
-	return smtpErrors
-}
-
-func (c *Config) validateTelegram(id int) []string {
-	var telegramErrors []string
-	log.Debug().Msgf("Alerting enabled for Telegram profile ID %v", id)
-	if c.Alerts.Telegram[id].ChatID == 0 {
-		telegramErrors = append(telegramErrors, fmt.Sprintf("No Telegram Chat ID specified! Profile ID %v", id))
-	}
-	if c.Alerts.Telegram[id].Token == "" {
-		telegramErrors = append(telegramErrors, fmt.Sprintf("No Telegram bot token specified! Profile ID %v", id))
-	}
-	// Check template syntax
-	if msg := validateTemplate("Telegram", c.Alerts.Telegram[id].Template); msg != "" {
-		telegramErrors = append(telegramErrors, msg+fmt.Sprintf(" Profile ID %v", id))
-	}
-	return telegramErrors
+	return cooldownErrors
 }
 
-func (c *Config) validateWebhook(id int) []string {
-	var webhookErrors []string
-	log.Debug().Msgf("Alerting enabled for Webhook profile ID %v", id)
-	if c.Alerts.Webhook[id].Server == "" {
-		webhookErrors = append(webhookErrors, fmt.Sprintf("No Webhook server specified! Profile ID %v", id))
-	}
-	// Check HTTP header template syntax
-	if msg := validateTemplate("Webhook HTTP Headers", c.Alerts.General.Title); msg != "" {
-		webhookErrors = append(webhookErrors, msg+fmt.Sprintf("Profile ID %v", id))
+func (c *Config) validateFallbacks() []string {
+	var fallbackErrors []string
+	validated := make(map[string]bool)
+	for _, n := range Notifiers() {
+		for id, p := range n.Profiles(c) {
+			profile := p.Common()
+			if !profile.Enabled || profile.Fallback.Provider == "" {
+				continue
+			}
+			// Follow chain of fallbacks to check each exists & there are no loops
+			visited := map[string]bool{fmt.Sprintf("%s/%d", n.Name(), id): true}
+			current, currentID := n, id
+			next := profile
+			for next.Fallback.Provider != "" {
+				fallback, fallbackID, ok := GetFallback(c, next)
+				if !ok {
+					fallbackErrors = append(fallbackErrors, fmt.Sprintf("Fallback for %s not found: %s %s! Profile ID %v", current.Name(), next.Fallback.Provider, next.Fallback.Profile, currentID))
+					break
+				}
+				key := fmt.Sprintf("%s/%d", fallback.Name(), fallbackID)
+				if visited[key] {
+					fallbackErrors = append(fallbackErrors, fmt.Sprintf("Fallback for %s creates a loop! Profile ID %v", n.Name(), id))
+					break
+				}
+				visited[key] = true
+
+				// Fallback profiles may be disabled so they only receive failed alerts, so need to be validated here
+				nextProfile := fallback.Profiles(c)[fallbackID]
+				next = nextProfile.Common()
+				if !next.Enabled && !validated[key] {
+					validated[key] = true
+					if results := fallback.Validate(c, fallbackID, nextProfile); len(results) > 0 {
+						fallbackErrors = append(fallbackErrors, results...)
+					}
+				}
+				log.Debug().
+					Str("provider", current.Name()).
+					Int("provider_id", currentID).
+					Str("fallback", fallback.Name()).
+					Int("fallback_id", fallbackID).
+					Msg("Notification fallback configured")
+				current, currentID = fallback, fallbackID
+			}
+		}
 	}
-
-	return webhookErrors
+	return fallbackErrors
 }
 
 func (c *Config) validateAlertingEnabled() string {
 	// Check to ensure at least one alert provider is configured
-	for _, profile := range c.Alerts.AppriseAPI {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Discord {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Gotify {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Matrix {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Mattermost {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Ntfy {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Pushover {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Signal {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.SMTP {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Telegram {
-		if profile.Enabled {
-			return ""
-		}
-	}
-	for _, profile := range c.Alerts.Webhook {
-		if profile.Enabled {
-			return ""
+	for _, n := range Notifiers() {
+		for _, profile := range n.Profiles(c) {
+			if profile.Common().Enabled {
+				return ""
+			}
 		}
 	}
 
@@ -894,7 +740,8 @@ func (c *Config) validateAppMonitoring() []string {
 	return monitoringErrors
 }
 
-func validateTemplate(provider, customTemplate string) string {
+// ValidateTemplate checks syntax of a custom message template
+func ValidateTemplate(provider, customTemplate string) string {
 	var templateError string
 	_, err := template.New("").Parse(customTemplate)
 	if err != nil {
@@ -11,7 +11,7 @@ func TestValidateAppMode(t *testing.T) {
 
 	// Check good config
 	config.App.Mode = "reviews"
-	Internal.FrigateVersion = 14
+	State.SetFrigateVersion(14)
 	result := config.validateAppMode()
 	expected := 0
 	if len(result) != expected {
@@ -27,7 +27,7 @@ func TestValidateAppMode(t *testing.T) {
 
 	// Check incompatible version
 	config.App.Mode = "reviews"
-	Internal.FrigateVersion = 13
+	State.SetFrigateVersion(13)
 	result = config.validateAppMode()
 	expected = 1
 	if len(result) != expected {
@@ -62,7 +62,92 @@ func TestValidateAPI(t *testing.T) {
 	if len(result) != expected {
 		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
 	}
+	config.App.API.Port = 8080
+
+	// Validate default address & token scope set
+	config.App.API.Address = ""
+	config.App.API.Auth.Tokens = []models.APIToken{{Token: "abc"}}
+	config.validateAPI()
+	if config.App.API.Address != "0.0.0.0" {
+		t.Errorf("Expected: 0.0.0.0, Got: %v", config.App.API.Address)
+	}
+	if len(config.App.API.Auth.Tokens[0].Scopes) != 1 || config.App.API.Auth.Tokens[0].Scopes[0] != "read" {
+		t.Errorf("Expected: [read], Got: %v", config.App.API.Auth.Tokens[0].Scopes)
+	}
 
+	// Check bad address, credentials & TLS settings
+	config.App.API.Address = "not an address"
+	config.App.API.Auth.Tokens = []models.APIToken{{Token: "abc", Scopes: []string{"admin"}}}
+	config.App.API.Auth.Users = []models.APIUser{{Username: "admin"}}
+	config.App.API.TLS = models.APITLS{Enabled: true}
+	result = config.validateAPI()
+	expected = 4
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Check allowed image hosts
+	config = Config{App: models.App{}}
+	config.App.API.AllowedImageHosts = []string{"cameras.local", "192.0.2.10", "2001:db8::1", "192.0.2.0/24"}
+	result = config.validateAPI()
+	expected = 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+	config.App.API.AllowedImageHosts = []string{"", "192.0.2.0/33", "http://cameras.local"}
+	result = config.validateAPI()
+	expected = 3
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
+
+func TestValidateQueue(t *testing.T) {
+	config := Config{App: models.App{}}
+
+	// Validate defaults set
+	config.validateQueue()
+	if config.App.Queue.MaxAttempts != 10 || config.App.Queue.InitialDelay != 5 || config.App.Queue.MaxDelay != 300 {
+		t.Errorf("Expected: 10/5/300, Got: %v/%v/%v", config.App.Queue.MaxAttempts, config.App.Queue.InitialDelay, config.App.Queue.MaxDelay)
+	}
+	if config.App.Storage.Path == "" {
+		t.Error("Expected: default storage path, Got: empty")
+	}
+	if config.App.Delivery.Workers != 10 || config.App.Delivery.DrainTimeout != 30 {
+		t.Errorf("Expected: 10/30, Got: %v/%v", config.App.Delivery.Workers, config.App.Delivery.DrainTimeout)
+	}
+	if config.App.History.Retention != 7 {
+		t.Errorf("Expected: 7, Got: %v", config.App.History.Retention)
+	}
+
+	// Check good config
+	config.App.Queue.InitialDelay = 10
+	config.App.Queue.MaxDelay = 60
+	result := config.validateQueue()
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Check bad config
+	config.App.Queue.MaxDelay = 5
+	result = config.validateQueue()
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+	config.App.Delivery.Workers = -1
+	result = config.validateQueue()
+	expected = 2
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+	config.App.History.Retention = -1
+	result = config.validateQueue()
+	expected = 3
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
 }
 
 func TestValidateFrigatePolling(t *testing.T) {
@@ -123,6 +208,28 @@ func TestValidateMQTT(t *testing.T) {
 	if len(result) != expected {
 		t.Errorf("Expected: err, Got: %v", result)
 	}
+	config.Frigate.MQTT.Password = "testddd"
+
+	// Test default protocol set
+	if config.Frigate.MQTT.Protocol != "tcp" {
+		t.Errorf("Expected: tcp, Got: %v", config.Frigate.MQTT.Protocol)
+	}
+
+	// Test default retry intervals set
+	if config.Frigate.MQTT.Retry.Interval != 10 || config.Frigate.MQTT.Retry.MaxInterval != 300 {
+		t.Errorf("Expected: 10s to 300s, Got: %+v", config.Frigate.MQTT.Retry)
+	}
+
+	// Test bad protocol, missing CA & client cert without key
+	config.Frigate.MQTT.Protocol = "http"
+	config.Frigate.MQTT.TLS = models.MQTTTLS{CA: "/nonexistent/ca.crt", Cert: "/nonexistent/client.crt"}
+	config.Frigate.MQTT.QoS = 3
+	config.Frigate.MQTT.Retry.Attempts = -1
+	result = config.validateMQTT()
+	expected = 5
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
 }
 
 func TestValidateQuietHours(t *testing.T) {
@@ -175,267 +282,31 @@ func TestValidateAlertGeneral(t *testing.T) {
 	}
 }
 
-func TestValidateDiscord(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Discord = make([]models.Discord, 1)
-
-	// Test valid config
-	config.Alerts.Discord[0].Webhook = "https://something.test"
-	result := config.validateDiscord(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing webhook config
-	config.Alerts.Discord[0].Webhook = ""
-	result = config.validateDiscord(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-}
-
-func TestValidateGotify(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Gotify = make([]models.Gotify, 1)
-
-	// Test valid config
-	config.Alerts.Gotify[0].Server = "https://something.test"
-	config.Alerts.Gotify[0].Token = "abcdefg"
-	result := config.validateGotify(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing server config
-	config.Alerts.Gotify[0].Server = ""
-	result = config.validateGotify(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing token config
-	config.Alerts.Gotify[0].Token = ""
-	result = config.validateGotify(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-}
-
-func TestValidateMattermost(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Mattermost = make([]models.Mattermost, 1)
-
-	// Test valid config
-	config.Alerts.Mattermost[0].Webhook = "https://webhook.test"
-	result := config.validateMattermost(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing server config
-	config.Alerts.Mattermost[0].Webhook = ""
-	result = config.validateMattermost(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-}
-
-func TestValidateNtfy(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Ntfy = make([]models.Ntfy, 1)
-
-	// Test valid config
-	config.Alerts.Ntfy[0].Server = "https://ntfy.test"
-	config.Alerts.Ntfy[0].Topic = "frigate"
-	result := config.validateNtfy(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing server config
-	config.Alerts.Ntfy[0].Server = ""
-	result = config.validateNtfy(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing topic config
-	config.Alerts.Ntfy[0].Topic = ""
-	result = config.validateNtfy(0)
-	expected = 2
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-}
-
-func TestValidatePushover(t *testing.T) {
+func TestValidateCooldown(t *testing.T) {
 	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Pushover = make([]models.Pushover, 1)
-
-	// Test valid config
-	config.Alerts.Pushover[0].Token = "abcd"
-	config.Alerts.Pushover[0].Userkey = "abcd"
-	config.Alerts.Pushover[0].Priority = 1
-	result := config.validatePushover(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing token
-	config.Alerts.Pushover[0].Token = ""
-	result = config.validatePushover(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
 
-	// Test missing Userkey
-	config.Alerts.Pushover[0].Userkey = ""
-	result = config.validatePushover(0)
-	expected = 2
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test priority 2 missing retry / expiration config
-	config.Alerts.Pushover[0].Priority = 2
-	result = config.validatePushover(0)
-	expected = 4
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test priority 2 with low retry interval
-	config.Alerts.Pushover[0].Retry = 2
-	config.Alerts.Pushover[0].Expire = 10
-	result = config.validatePushover(0)
-	expected = 3
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test negative TTL
-	config.Alerts.Pushover[0].TTL = -2
-	result = config.validatePushover(0)
-	expected = 4
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-}
-
-func TestValidateSMTP(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.SMTP = make([]models.SMTP, 1)
-
-	// Test valid config
-	config.Alerts.SMTP[0].Server = "192.0.2.10"
-	config.Alerts.SMTP[0].Recipient = "someone@none.test"
-	config.Alerts.SMTP[0].User = "someuser"
-	config.Alerts.SMTP[0].Password = "abcd"
-	result := config.validateSMTP(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Check Default port set
-	if config.Alerts.SMTP[0].Port != 25 {
-		t.Errorf("Expected: port 25 , Got: %v", config.Alerts.SMTP[0].Port)
-	}
-
-	// Check SMTP From is copied
-	if config.Alerts.SMTP[0].User != config.Alerts.SMTP[0].From {
-		t.Errorf("Expected: %v, Got: %v", config.Alerts.SMTP[0].User, config.Alerts.SMTP[0].From)
-	}
-
-	// Test missing server
-	config.Alerts.SMTP[0].Server = ""
-	result = config.validateSMTP(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing recipient
-	config.Alerts.SMTP[0].Recipient = ""
-	result = config.validateSMTP(0)
-	expected = 2
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test invalid auth config
-	config.Alerts.SMTP[0].Password = ""
-	result = config.validateSMTP(0)
-	expected = 3
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-}
-
-func TestValidateTelegram(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Telegram = make([]models.Telegram, 1)
-
-	// Test valid config
-	config.Alerts.Telegram[0].ChatID = 1234
-	config.Alerts.Telegram[0].Token = "abcd"
-	result := config.validateTelegram(0)
+	// Check good config
+	config.Alerts.Cooldown.Duration = 60
+	config.Alerts.Cooldown.Cameras = map[string]int{"front_door": 0}
+	config.Alerts.Cooldown.Labels = map[string]int{"car": 600}
+	result := config.validateCooldown()
 	expected := 0
 	if len(result) != expected {
 		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
 	}
 
-	// Test missing Chat ID
-	config.Alerts.Telegram[0].ChatID = 0
-	result = config.validateTelegram(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing Token
-	config.Alerts.Telegram[0].Token = ""
-	result = config.validateTelegram(0)
+	// Check bad config
+	config.Alerts.Cooldown.Duration = -1
+	config.Alerts.Cooldown.Labels["person"] = -5
+	result = config.validateCooldown()
 	expected = 2
 	if len(result) != expected {
 		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
 	}
 }
 
-func TestValidateWebhook(t *testing.T) {
-	config := Config{Alerts: models.Alerts{}}
-	config.Alerts.Webhook = make([]models.Webhook, 1)
-
-	// Test valid config
-	config.Alerts.Webhook[0].Server = "https://webhook.test"
-	result := config.validateWebhook(0)
-	expected := 0
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-	// Test missing server config
-	config.Alerts.Webhook[0].Server = ""
-	result = config.validateWebhook(0)
-	expected = 1
-	if len(result) != expected {
-		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
-	}
-
-}
-
 func TestValidateAlertingEnabled(t *testing.T) {
+	registerTestNotifier()
 	config := Config{Alerts: models.Alerts{}}
 	config.Alerts.Discord = make([]models.Discord, 1)
 
@@ -483,15 +354,49 @@ func TestValidateppMonitoring(t *testing.T) {
 func TestValidateTemplate(t *testing.T) {
 
 	// Test valid template
-	result := validateTemplate("discord", "{{ .Camera }} detected {{ .Label }}")
+	result := ValidateTemplate("discord", "{{ .Camera }} detected {{ .Label }}")
 	expected := ""
 	if result != expected {
 		t.Errorf("Expected: '', Got: %v", result)
 	}
 
 	// Test invalid template
-	result = validateTemplate("discord", "{{ Camera }} detected {{ .Label }}")
+	result = ValidateTemplate("discord", "{{ Camera }} detected {{ .Label }}")
 	if result == "" {
 		t.Errorf("Expected: error message, Got: %v", result)
 	}
 }
+
+func TestValidateFallbacks(t *testing.T) {
+	registerTestNotifier()
+	config := Config{Alerts: models.Alerts{}}
+	config.Alerts.Discord = make([]models.Discord, 2)
+	config.Alerts.Discord[0].Enabled = true
+	config.Alerts.Discord[0].Name = "primary"
+	config.Alerts.Discord[1].Name = "backup"
+
+	// Check good config
+	config.Alerts.Discord[0].Fallback = models.Fallback{Provider: "test", Profile: "backup"}
+	result := config.validateFallbacks()
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Check missing fallback
+	config.Alerts.Discord[0].Fallback.Profile = "missing"
+	result = config.validateFallbacks()
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Check fallback loop
+	config.Alerts.Discord[0].Fallback.Profile = "backup"
+	config.Alerts.Discord[1].Fallback = models.Fallback{Provider: "test", Profile: "primary"}
+	result = config.validateFallbacks()
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
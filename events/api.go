@@ -1,30 +1,50 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/0x2142/frigate-notify/models"
 	"github.com/0x2142/frigate-notify/util"
 )
 
 // LastQueryTime tracks the timestamp of the last event seen
 var LastQueryTime float64 = float64(time.Now().Unix())
+var lastQueryLock sync.Mutex
+
+// setLastQueryTime updates timestamp of the last event seen, if newer
+func setLastQueryTime(t float64) {
+	lastQueryLock.Lock()
+	defer lastQueryLock.Unlock()
+	if t > LastQueryTime {
+		LastQueryTime = t
+	}
+}
+
+// getLastQueryTime returns timestamp of the last event seen
+func getLastQueryTime() float64 {
+	lastQueryLock.Lock()
+	defer lastQueryLock.Unlock()
+	return LastQueryTime
+}
 
-func QueryAPI() {
-	appmode := strings.ToLower(config.ConfigData.App.Mode)
+func QueryAPI(ctx context.Context) {
+	appmode := strings.ToLower(config.Current().App.Mode)
 	var params string
-	if config.ConfigData.Frigate.WebAPI.TestMode {
+	if config.Current().Frigate.WebAPI.TestMode {
 		// For testing, pull 1 event immediately
 		params = "?include_thumbnails=0&limit=1"
 	} else {
 		// Check for any events after last query time
-		params = "?include_thumbnails=0&after=" + strconv.FormatFloat(LastQueryTime, 'f', 6, 64)
+		params = "?include_thumbnails=0&after=" + strconv.FormatFloat(getLastQueryTime(), 'f', 6, 64)
 	}
 
 	var uri string
@@ -34,20 +54,21 @@ func QueryAPI() {
 		uri = "/api/events"
 	}
 
-	url := config.ConfigData.Frigate.Server + uri + params
+	url := config.Current().Frigate.Server + uri + params
 	log.Debug().Msgf("Checking for new %s...", appmode)
 
 	// Query API for reviews or events
-	response, err := util.HTTPGet(url, config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
+	response, err := util.HTTPGet(ctx, url, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
 	if err != nil {
-		config.Internal.Status.Health = "frigate webapi unreachable"
-		config.Internal.Status.Frigate.API = "unreachable"
+		config.State.SetHealth("frigate webapi unreachable")
+		config.State.SetFrigateAPI("unreachable")
 		log.Error().
 			Err(err).
 			Msgf("Cannot get %s from %s", appmode, url)
+		return
 	}
-	config.Internal.Status.Health = "ok"
-	config.Internal.Status.Frigate.API = "ok"
+	config.State.SetHealth("ok")
+	config.State.SetFrigateAPI("ok")
 
 	switch appmode {
 	case "reviews":
@@ -57,12 +78,14 @@ func QueryAPI() {
 
 		for _, review := range reviews {
 			// Update last event check time with most recent timestamp
-			if review.StartTime > LastQueryTime {
-				LastQueryTime = review.StartTime
-			}
+			setLastQueryTime(review.StartTime)
 			if isStale("review", review.StartTime, review.ID) {
 				return
 			}
+			if !firstSeen(review.ID) {
+				continue
+			}
+			reviewReceived(review, metrics.SourceWebAPI)
 			processReview(review)
 		}
 	case "events":
@@ -73,12 +96,14 @@ func QueryAPI() {
 			// Copy zones to CurrentZones, which is used for filters
 			event.CurrentZones = event.Zones
 			// Update last event check time with most recent timestamp
-			if event.StartTime > LastQueryTime {
-				LastQueryTime = event.StartTime
-			}
+			setLastQueryTime(event.StartTime)
 			if isStale("event", event.StartTime, event.ID) {
 				return
 			}
+			if !firstSeen(event.ID) {
+				continue
+			}
+			eventReceived(event, metrics.SourceWebAPI)
 			processEvent(event)
 		}
 	}
@@ -131,18 +156,18 @@ func waitforLPR(event *models.Event) models.Event {
 						Int("current_attempts", current).
 						Msg("Re-checking event details")
 
-					url := config.ConfigData.Frigate.Server + "/api/events/" + event.ID
-					response, err := util.HTTPGet(url, config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
+					url := config.Current().Frigate.Server + "/api/events/" + event.ID
+					response, err := util.HTTPGet(context.Background(), url, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
 					if err != nil {
-						config.Internal.Status.Health = "frigate webapi unreachable"
-						config.Internal.Status.Frigate.API = "unreachable"
+						config.State.SetHealth("frigate webapi unreachable")
+						config.State.SetFrigateAPI("unreachable")
 						log.Error().
 							Err(err).
 							Msgf("Cannot get event from %s", url)
 						return *event
 					}
-					config.Internal.Status.Health = "ok"
-					config.Internal.Status.Frigate.API = "ok"
+					config.State.SetHealth("ok")
+					config.State.SetFrigateAPI("ok")
 
 					json.Unmarshal([]byte(response), &event)
 
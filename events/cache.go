@@ -104,6 +104,20 @@ func zoneAlreadyAlerted(event models.Event) bool {
 	return true
 }
 
+// zonesAlerted checks if all zones in event have already generated an alert, without updating the cache
+func zonesAlerted(event models.Event) bool {
+	alreadyAlerted, ok := zoneCache.Get(event.ID)
+	if !ok {
+		return false
+	}
+	for _, zone := range event.CurrentZones {
+		if !slices.Contains(alreadyAlerted, zone) {
+			return false
+		}
+	}
+	return true
+}
+
 // Remove zone alert cache for event ID
 func delZoneAlerted(event models.Event) {
 	zoneCache.Delete(event.ID)
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/util"
+)
+
+var cooldowns = util.NewCooldown()
+
+// Reason recorded in notification history when an event is dropped due to cooldown
+const cooldownReason = "Cooldown period for camera & label"
+
+// cooldownWindow returns the configured cooldown duration for a camera & label pair
+func cooldownWindow(camera, label string) time.Duration {
+	settings := config.Current().Alerts.Cooldown
+	window := settings.Duration
+	cameraWindow, cameraSet := settings.Cameras[camera]
+	labelWindow, labelSet := settings.Labels[label]
+	// Camera & label overrides take precedence over global duration, with the longest applying if both are set
+	switch {
+	case cameraSet && labelSet:
+		window = max(cameraWindow, labelWindow)
+	case cameraSet:
+		window = cameraWindow
+	case labelSet:
+		window = labelWindow
+	}
+	return time.Duration(window) * time.Second
+}
+
+// inCooldown checks if a notification was recently sent for the same camera & label as these events
+func inCooldown(events []models.Event) bool {
+	windows := make(map[string]time.Duration)
+	var ids []string
+	for _, event := range events {
+		label := event.Label
+		if label == "" {
+			label = event.Extra.Audio
+		}
+		windows[event.Camera+"/"+label] = cooldownWindow(event.Camera, label)
+		if event.ID != "" {
+			ids = append(ids, event.ID)
+		}
+	}
+	log.Trace().
+		Interface("cooldowns", windows).
+		Strs("event_ids", ids).
+		Msg("Check cooldown")
+
+	if cooldowns.Allow(windows, ids) {
+		return false
+	}
+	log.Info().
+		Str("event_id", events[0].ID).
+		Str("camera", events[0].Camera).
+		Str("label", events[0].Label).
+		Msg("Event dropped - Cooldown period for camera & label")
+	return true
+}
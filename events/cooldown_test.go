@@ -0,0 +1,66 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/util"
+)
+
+func TestCooldownWindow(t *testing.T) {
+	defer config.Update(func(c *config.Config) {
+		c.Alerts.Cooldown = models.Cooldown{
+			Duration: 60,
+			Cameras:  map[string]int{"backyard": 0, "driveway": 120},
+			Labels:   map[string]int{"car": 600},
+		}
+	})()
+
+	tests := []struct {
+		camera, label string
+		expected      time.Duration
+	}{
+		{"front_door", "person", 60 * time.Second},
+		{"backyard", "person", 0},
+		{"driveway", "person", 120 * time.Second},
+		{"front_door", "car", 600 * time.Second},
+		{"driveway", "car", 600 * time.Second},
+	}
+	for _, test := range tests {
+		if result := cooldownWindow(test.camera, test.label); result != test.expected {
+			t.Errorf("Camera: %v, Label: %v, Expected: %v, Got: %v", test.camera, test.label, test.expected, result)
+		}
+	}
+}
+
+func TestInCooldown(t *testing.T) {
+	defer config.Update(func(c *config.Config) { c.Alerts.Cooldown = models.Cooldown{Duration: 60} })()
+	cooldowns = util.NewCooldown()
+
+	first := models.Event{ID: "event-1", Camera: "front_door", Label: "person"}
+	second := models.Event{ID: "event-2", Camera: "front_door", Label: "person"}
+	other := models.Event{ID: "event-3", Camera: "front_door", Label: "car"}
+
+	// First event should notify & start cooldown
+	if inCooldown([]models.Event{first}) {
+		t.Error("Expected: first event allowed")
+	}
+	// Updates to the same event are not suppressed
+	if inCooldown([]models.Event{first}) {
+		t.Error("Expected: same event allowed")
+	}
+	// New event for same camera & label is suppressed
+	if !inCooldown([]models.Event{second}) {
+		t.Error("Expected: second event suppressed")
+	}
+	// Different label is not suppressed
+	if inCooldown([]models.Event{other}) {
+		t.Error("Expected: different label allowed")
+	}
+	// Review with at least one label not in cooldown is not suppressed
+	if inCooldown([]models.Event{second, {ID: "event-4", Camera: "front_door", Label: "dog"}}) {
+		t.Error("Expected: review with new label allowed")
+	}
+}
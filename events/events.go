@@ -1,24 +1,27 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"time"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/0x2142/frigate-notify/models"
 	"github.com/0x2142/frigate-notify/notifier"
+	"github.com/0x2142/frigate-notify/stream"
 	"github.com/0x2142/frigate-notify/util"
 	"github.com/rs/zerolog/log"
 )
 
 // processEvent handles preparing event for alerting
 func processEvent(event models.Event) {
-	if config.ConfigData.Alerts.General.RecheckDelay != 0 {
+	if config.Current().Alerts.General.RecheckDelay != 0 {
 		event = recheckEvent(event)
 	}
 
-	config.Internal.Status.LastEvent = time.Now()
+	config.State.SetLastEvent(time.Now())
 	// For events collected via API, top-level top_score value is no longer used
 	// So need to replace it with data.top_score value
 	if event.TopScore == 0 {
@@ -38,12 +41,23 @@ func processEvent(event models.Event) {
 		Msgf("Event start time: %s", eventTime)
 
 	// Wait for license plate data before notifying, if set
-	if config.ConfigData.Alerts.LicensePlate.Enabled {
+	if config.Current().Alerts.LicensePlate.Enabled {
 		waitforLPR(&event)
 	}
 
 	// Check that event passes configured filters
-	if !checkEventFilters(event) {
+	if ok, reason := checkEventFilters(event); !ok {
+		eventDropped([]models.Event{event}, reason)
+		// Edit previously sent notifications if event details have changed, unless notifications are muted
+		if !isMuted(reason) {
+			notifier.UpdateAlert([]models.Event{event})
+		}
+		return
+	}
+
+	// Check if camera & label recently notified
+	if inCooldown([]models.Event{event}) {
+		eventDropped([]models.Event{event}, cooldownReason)
 		return
 	}
 
@@ -51,8 +65,34 @@ func processEvent(event models.Event) {
 	notifier.SendAlert([]models.Event{event})
 }
 
+// processEventEnd sends a follow-up notification for an ended event, if an alert was sent for it
+func processEventEnd(event models.Event) {
+	if !config.Current().Alerts.General.NotifyEnd || !notifier.AlertSent(event.ID) {
+		return
+	}
+	if reason := mutedReason(event); reason != "" {
+		log.Info().
+			Str("event_id", event.ID).
+			Msg("Follow-up skipped - " + reason)
+		return
+	}
+	if event.TopScore == 0 {
+		event.TopScore = event.Data.TopScore
+	}
+	// Object may have left all zones, so list every zone entered during the event
+	event.CurrentZones = event.EnteredZones
+
+	notifier.SendFollowUp([]models.Event{event})
+}
+
+// eventReceived records a new event from Frigate in metrics & the live stream
+func eventReceived(event models.Event, source string) {
+	metrics.EventsReceived.WithLabelValues(event.Camera, event.Label, source).Inc()
+	stream.EventReceived(event, source)
+}
+
 func recheckEvent(event models.Event) models.Event {
-	delay := config.ConfigData.Alerts.General.RecheckDelay
+	delay := config.Current().Alerts.General.RecheckDelay
 	log.Debug().
 		Str("event_id", event.ID).
 		Int("recheck_delay", delay).
@@ -63,18 +103,18 @@ func recheckEvent(event models.Event) models.Event {
 		Int("recheck_delay", delay).
 		Msg("Re-checking event details")
 
-	url := config.ConfigData.Frigate.Server + "/api/events/" + event.ID
-	response, err := util.HTTPGet(url, config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
+	url := config.Current().Frigate.Server + "/api/events/" + event.ID
+	response, err := util.HTTPGet(context.Background(), url, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
 	if err != nil {
-		config.Internal.Status.Health = "frigate webapi unreachable"
-		config.Internal.Status.Frigate.API = "unreachable"
+		config.State.SetHealth("frigate webapi unreachable")
+		config.State.SetFrigateAPI("unreachable")
 		log.Error().
 			Err(err).
 			Msgf("Cannot get event from %s", url)
 		return event
 	}
-	config.Internal.Status.Health = "ok"
-	config.Internal.Status.Frigate.API = "ok"
+	config.State.SetHealth("ok")
+	config.State.SetFrigateAPI("ok")
 
 	json.Unmarshal([]byte(response), &event)
 	return event
@@ -0,0 +1,178 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/notifier"
+	"github.com/0x2142/frigate-notify/util"
+)
+
+// ErrNotFound is returned when an event or review does not exist in Frigate
+var ErrNotFound = errors.New("not found in Frigate")
+
+// Explain fetches a Frigate event or review & runs it through all filters without sending notifications.
+// Zone cache is checked, but not updated
+func Explain(ctx context.Context, id string, kind string) (models.Explanation, error) {
+	result := models.Explanation{ID: id, Type: kind, Events: []models.EventExplanation{}}
+
+	var events []models.Event
+	if kind == "review" {
+		var review models.Review
+		if err := fetchFrigate(ctx, "/api/review/"+id, &review); err != nil {
+			return result, err
+		}
+		var err error
+		events, result.Steps, err = explainReview(ctx, review)
+		if err != nil {
+			return result, err
+		}
+	} else {
+		var event models.Event
+		if err := fetchFrigate(ctx, "/api/events/"+id, &event); err != nil {
+			return result, err
+		}
+		normalizeEvent(&event)
+		events = append(events, event)
+	}
+
+	result.Notify = len(events) > 0
+	for _, step := range result.Steps {
+		result.Notify = result.Notify && step.Passed
+	}
+	for i := range events {
+		// Wait for license plate data before checking filters, if set
+		if config.Current().Alerts.LicensePlate.Enabled {
+			waitforLPR(&events[i])
+		}
+		event := events[i]
+		explanation := models.EventExplanation{
+			EventID:      event.ID,
+			Camera:       event.Camera,
+			Label:        event.Label,
+			SubLabel:     event.SubLabel,
+			LicensePlate: event.Data.RecognizedLicensePlate,
+			Score:        event.TopScore,
+			Zones:        event.CurrentZones,
+			Steps:        explainEventFilters(event),
+		}
+		// Audio-only reviews have no detection event
+		if event.ID == "" {
+			explanation.Label = event.Extra.Audio
+			explanation.Steps = nil
+		}
+		for _, step := range explanation.Steps {
+			result.Notify = result.Notify && step.Passed
+		}
+		result.Events = append(result.Events, explanation)
+	}
+
+	result.Providers = notifier.ExplainAlertFilters(events)
+	providerNotify := false
+	for _, provider := range result.Providers {
+		providerNotify = providerNotify || provider.Notify
+	}
+	result.Notify = result.Notify && providerNotify
+
+	return result, nil
+}
+
+// explainReview checks review-level filters & collects detection events under a review
+func explainReview(ctx context.Context, review models.Review) ([]models.Event, []models.FilterStep, error) {
+	var steps []models.FilterStep
+
+	step := models.FilterStep{Filter: "severity", Passed: true}
+	if !config.Current().Alerts.General.NotifyDetections && review.Severity == "detection" {
+		step.Passed = false
+		step.Reason = "Detection only, not alert"
+	}
+	steps = append(steps, step)
+
+	// Audio-only reviews have no detection events, so assemble info via review item
+	if len(review.Data.Detections) == 0 && len(review.Data.Audio) != 0 {
+		step := models.FilterStep{Filter: "audio_only", Passed: true}
+		if config.Current().Alerts.General.AudioOnly != "allow" {
+			step.Passed = false
+			step.Reason = "Audio only"
+		}
+		steps = append(steps, step)
+
+		var audioEvent models.Event
+		audioEvent.StartTime = review.StartTime
+		audioEvent.Extra.Audio = strings.Join(review.Data.Audio, ",")
+		audioEvent.Camera = review.Camera
+		return []models.Event{audioEvent}, steps, nil
+	}
+
+	var detections []models.Event
+	for _, id := range review.Data.Detections {
+		var detection models.Event
+		if err := fetchFrigate(ctx, "/api/events/"+id, &detection); err != nil {
+			return nil, steps, err
+		}
+		if detection.TopScore == 0 {
+			detection.TopScore = detection.Data.TopScore
+		}
+		detection.Extra.ReviewID = review.ID
+		detection.CurrentZones = detection.Zones
+		detections = append(detections, detection)
+	}
+	return detections, steps, nil
+}
+
+// FetchEvents retrieves a Frigate event, or all detection events under a review, ready to be sent as a notification
+func FetchEvents(ctx context.Context, id string, kind string) ([]models.Event, error) {
+	if kind == "review" {
+		var review models.Review
+		if err := fetchFrigate(ctx, "/api/review/"+id, &review); err != nil {
+			return nil, err
+		}
+		events, _, err := explainReview(ctx, review)
+		return events, err
+	}
+	var event models.Event
+	if err := fetchFrigate(ctx, "/api/events/"+id, &event); err != nil {
+		return nil, err
+	}
+	normalizeEvent(&event)
+	return []models.Event{event}, nil
+}
+
+// LatestEvent retrieves the most recent event from Frigate
+func LatestEvent(ctx context.Context) ([]models.Event, error) {
+	var events []models.Event
+	if err := fetchFrigate(ctx, "/api/events?include_thumbnails=0&limit=1", &events); err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+	normalizeEvent(&events[0])
+	return events, nil
+}
+
+// normalizeEvent fills in fields that are only set on MQTT events, using values from the Frigate API
+func normalizeEvent(event *models.Event) {
+	if event.TopScore == 0 {
+		event.TopScore = event.Data.TopScore
+	}
+	if len(event.CurrentZones) == 0 {
+		event.CurrentZones = event.Zones
+	}
+}
+
+// fetchFrigate retrieves an item from the Frigate API
+func fetchFrigate(ctx context.Context, uri string, item interface{}) error {
+	response, err := util.HTTPGet(ctx, config.Current().Frigate.Server+uri, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
+	if err != nil {
+		if err.Error() == "404" {
+			return ErrNotFound
+		}
+		return err
+	}
+	return json.Unmarshal(response, item)
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestExplainEventFilters(t *testing.T) {
+	// Setup
+	InitZoneCache()
+	defer CloseZoneCache()
+	config.State.SetNotificationsEnabled(true)
+	defer config.State.SetNotificationsEnabled(false)
+	defer config.Update(func(c *config.Config) { c.Alerts.Zones.Block = []string{"street"} })()
+	event := models.Event{ID: "test-event-id", HasSnapshot: true, CurrentZones: []string{"street"}}
+
+	// Check all filters run & failed filters report reason
+	steps := explainEventFilters(event)
+	if len(steps) != len(eventFilters(false)) {
+		t.Errorf("Expected: %v steps, Got: %v", len(eventFilters(false)), len(steps))
+	}
+	var failed []models.FilterStep
+	for _, step := range steps {
+		if !step.Passed {
+			failed = append(failed, step)
+		}
+	}
+	if len(failed) != 1 || failed[0].Filter != "zones" || failed[0].Reason != "Zone block list" {
+		t.Errorf("Expected: dropped by zone block list, Got: %v", failed)
+	}
+
+	// Check zone cache not updated
+	if getCachebyID(event.ID) != nil {
+		t.Error("Expected: zone cache not updated")
+	}
+	if ok, _ := checkEventFilters(event); ok {
+		t.Error("Expected: event dropped")
+	}
+	if getCachebyID(event.ID) == nil {
+		t.Error("Expected: zone cache updated")
+	}
+}
+
+func TestMutedReason(t *testing.T) {
+	event := models.Event{ID: "test-event-id", Camera: "front_door", Label: "person"}
+	config.State.SetNotificationsEnabled(true)
+	defer config.State.SetNotificationsEnabled(false)
+
+	if reason := mutedReason(event); reason != "" {
+		t.Errorf("Expected: not muted, Got: %v", reason)
+	}
+
+	// Follow-ups are muted during quiet hours, even though other filters passed when the alert was sent
+	restore := config.Update(func(c *config.Config) {
+		c.Alerts.Quiet.Start = time.Now().Add(-time.Hour).Format("15:04")
+		c.Alerts.Quiet.End = time.Now().Add(time.Hour).Format("15:04")
+	})
+	if reason := mutedReason(event); reason != quietReason {
+		t.Errorf("Expected: %v, Got: %v", quietReason, reason)
+	}
+	restore()
+
+	config.State.SetNotificationsEnabled(false)
+	if reason := mutedReason(event); reason != disabledReason {
+		t.Errorf("Expected: %v, Got: %v", disabledReason, reason)
+	}
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/util"
+)
+
+// How long to remember received event & review IDs
+const seenTTL = time.Hour
+
+var (
+	failoverLock   sync.Mutex
+	failoverCancel context.CancelFunc
+	failoverDone   chan struct{}
+
+	// IDs of new events & reviews already received, so items received via both MQTT & Frigate API are only processed once
+	seenLock sync.Mutex
+	seen     = make(map[string]time.Time)
+)
+
+// startFailover begins polling Frigate API for events while MQTT is unavailable, if failover is enabled.
+// Does nothing if already polling
+func startFailover() {
+	if !config.Current().Frigate.MQTT.Failover {
+		return
+	}
+	failoverLock.Lock()
+	defer failoverLock.Unlock()
+	if failoverCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	failoverCancel = cancel
+	failoverDone = done
+	log.Warn().Msg("MQTT unavailable, polling Frigate API for events until reconnected")
+	go func() {
+		defer close(done)
+		pollFailover(ctx)
+	}()
+}
+
+// stopFailover stops polling Frigate API & waits for any in-progress poll to finish
+func stopFailover() {
+	failoverLock.Lock()
+	cancel, done := failoverCancel, failoverDone
+	failoverCancel, failoverDone = nil, nil
+	failoverLock.Unlock()
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	log.Info().Msg("Stopped polling Frigate API for events")
+}
+
+// pollFailover queries Frigate API for new events at the configured web API interval, until cancelled
+func pollFailover(ctx context.Context) {
+	interval := time.Duration(config.Current().Frigate.WebAPI.Interval) * time.Second
+	for ctx.Err() == nil {
+		QueryAPI(ctx)
+		// Report MQTT as still unavailable, even though events are being received
+		if ctx.Err() == nil && config.State.FrigateAPI() == "ok" {
+			config.State.SetHealth("frigate mqtt unreachable, polling webapi")
+		}
+		util.Wait(ctx, interval)
+	}
+}
+
+// firstSeen records ID of a new event or review & returns false if it was already received
+func firstSeen(id string) bool {
+	seenLock.Lock()
+	defer seenLock.Unlock()
+
+	now := time.Now()
+	for seenID, t := range seen {
+		if now.Sub(t) > seenTTL {
+			delete(seen, seenID)
+		}
+	}
+	if _, ok := seen[id]; ok {
+		log.Debug().
+			Str("id", id).
+			Msg("Already received, skipping duplicate")
+		return false
+	}
+	seen[id] = now
+	return true
+}
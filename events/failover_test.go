@@ -0,0 +1,74 @@
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+)
+
+func TestFirstSeen(t *testing.T) {
+	if !firstSeen("first-seen-test") {
+		t.Error("Expected: new ID not seen")
+	}
+	if firstSeen("first-seen-test") {
+		t.Error("Expected: duplicate ID already seen")
+	}
+
+	// Check expired IDs are removed
+	seenLock.Lock()
+	seen["first-seen-test"] = time.Now().Add(-2 * seenTTL)
+	seenLock.Unlock()
+	if !firstSeen("first-seen-test") {
+		t.Error("Expected: expired ID not seen")
+	}
+}
+
+func TestFailover(t *testing.T) {
+	var polls atomic.Int32
+	var after atomic.Value
+	frigate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		after.Store(r.URL.Query().Get("after"))
+		w.Write([]byte(`[]`))
+	}))
+	defer frigate.Close()
+	defer config.Update(func(c *config.Config) {
+		c.Frigate.Server = frigate.URL
+		c.Frigate.WebAPI.Interval = 60
+		c.App.Mode = "events"
+	})()
+
+	// Check failover not started unless enabled
+	startFailover()
+	if failoverCancel != nil {
+		t.Fatal("Expected: failover disabled")
+	}
+
+	// Check polling starts from last event seen via MQTT
+	defer config.Update(func(c *config.Config) { c.Frigate.MQTT.Failover = true })()
+	setLastQueryTime(float64(time.Now().Unix()))
+	startFailover()
+	startFailover()
+	deadline := time.Now().Add(5 * time.Second)
+	for polls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stopFailover()
+	if polls.Load() != 1 {
+		t.Errorf("Expected: 1 poll, Got: %v", polls.Load())
+	}
+	if after.Load() != strconv.FormatFloat(getLastQueryTime(), 'f', 6, 64) {
+		t.Errorf("Expected: poll after %v, Got: %v", getLastQueryTime(), after.Load())
+	}
+	if config.State.Health() != "frigate mqtt unreachable, polling webapi" {
+		t.Errorf("Expected: degraded health, Got: %v", config.State.Health())
+	}
+
+	// Check stopping again does nothing
+	stopFailover()
+}
@@ -8,128 +8,190 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/snooze"
+	"github.com/0x2142/frigate-notify/stream"
 )
 
-// checkEventFilters processes incoming event through configured filters to determine if it should generate a notification
-func checkEventFilters(event models.Event) bool {
-	// Check if audio event
-	if event.Data.Type == "audio" && config.ConfigData.Alerts.General.AudioOnly == "drop" {
-		log.Info().Msg("Event dropped - Audio only.")
-		return false
-	}
-
-	// Check if notifications are currently disabled
-	if !config.Internal.Status.Notifications.Enabled {
-		log.Info().Msg("Event dropped - Notifications currently disabled.")
-		return false
-	}
+// Reasons events are dropped while notifications are muted. Previously sent alerts are not edited for these
+const (
+	disabledReason = "Notifications currently disabled"
+	snoozedReason  = "Snoozed"
+	quietReason    = "Quiet hours"
+)
 
-	// Skip excluded cameras
-	if slices.Contains(config.ConfigData.Frigate.Cameras.Exclude, event.Camera) {
-		log.Info().
-			Str("event_id", event.ID).
-			Str("camera", event.Camera).
-			Msg("Event dropped - Camera Excluded")
-		return false
-	}
+// eventFilter is a single check applied to incoming events to determine if they should generate a notification
+type eventFilter struct {
+	name  string
+	check func(event models.Event) (bool, string)
+}
 
-	// Drop event if no snapshot or clip is available - Event is likely being filtered on Frigate side.
-	// For example, if a camera has `required_zones` set - then there may not be any clip or snap until
-	// object moves into required zone
-	if !event.HasClip && !event.HasSnapshot {
-		log.Info().
-			Str("event_id", event.ID).
-			Msg("Event dropped - No snapshot or clip available")
-		return false
+// eventFilters returns all event checks, in the order they are applied.
+// If update is false, the zone cache is only checked & not updated with new zones
+func eventFilters(update bool) []eventFilter {
+	return []eventFilter{
+		{"audio_only", func(event models.Event) (bool, string) {
+			// Check if audio event
+			if event.Data.Type == "audio" && config.Current().Alerts.General.AudioOnly == "drop" {
+				return false, "Audio only"
+			}
+			return true, ""
+		}},
+		{"notifications_enabled", func(event models.Event) (bool, string) {
+			// Check if notifications are currently disabled
+			if !config.State.NotificationsEnabled() {
+				return false, disabledReason
+			}
+			return true, ""
+		}},
+		{"snooze", func(event models.Event) (bool, string) {
+			// Check if camera or label is snoozed via API
+			if snooze.Event(event.Camera, event.Label) {
+				return false, snoozedReason
+			}
+			return true, ""
+		}},
+		{"camera_exclude", func(event models.Event) (bool, string) {
+			// Skip excluded cameras
+			if slices.Contains(config.Current().Frigate.Cameras.Exclude, event.Camera) {
+				return false, "Camera excluded"
+			}
+			return true, ""
+		}},
+		{"snapshot_or_clip", func(event models.Event) (bool, string) {
+			// Drop event if no snapshot or clip is available - Event is likely being filtered on Frigate side.
+			// For example, if a camera has `required_zones` set - then there may not be any clip or snap until
+			// object moves into required zone
+			if !event.HasClip && !event.HasSnapshot {
+				return false, "No snapshot or clip available"
+			}
+			return true, ""
+		}},
+		{"notify_once", func(event models.Event) (bool, string) {
+			// Check if notify_once is set & we already notified on this event
+			if config.Current().Alerts.General.NotifyOnce && getCachebyID(event.ID) != nil {
+				return false, "Already notified & notify_once is set"
+			}
+			return true, ""
+		}},
+		{"zone_cache", func(event models.Event) (bool, string) {
+			// Check if already notified on zones
+			alerted := zonesAlerted(event)
+			if !update {
+				if alerted {
+					return false, "Already notified on this zone"
+				}
+				return true, ""
+			}
+			if zoneAlreadyAlerted(event) {
+				return false, "Already notified on this zone"
+			}
+			log.Debug().
+				Str("event_id", event.ID).
+				Str("camera", event.Camera).
+				Str("label", event.Label).
+				Str("zones", strings.Join(event.CurrentZones, ",")).
+				Msg("Object entered new zone")
+			return true, ""
+		}},
+		{"snapshot", func(event models.Event) (bool, string) {
+			// Drop event if no snapshot & skip_nosnap is true
+			if !event.HasSnapshot && strings.ToLower(config.Current().Alerts.General.NoSnap) == "drop" {
+				return false, "No snapshot available"
+			}
+			return true, ""
+		}},
+		{"quiet_hours", func(event models.Event) (bool, string) {
+			if isQuietHours() {
+				return false, quietReason
+			}
+			return true, ""
+		}},
+		{"zones", func(event models.Event) (bool, string) {
+			return isAllowedZone(event.ID, event.CurrentZones)
+		}},
+		{"labels", func(event models.Event) (bool, string) {
+			return isAllowedLabel(event.ID, event.Label, "label")
+		}},
+		{"min_score", func(event models.Event) (bool, string) {
+			return aboveMinScore(event.ID, event.TopScore)
+		}},
+		{"sublabels", func(event models.Event) (bool, string) {
+			return isAllowedLabel(event.ID, event.SubLabel, "sublabel")
+		}},
+		{"license_plate", func(event models.Event) (bool, string) {
+			return isAllowedLabel(event.ID, event.Data.RecognizedLicensePlate, "license_plate")
+		}},
 	}
+}
 
-	// Check if notify_once is set & we already notified on this event
-	if config.ConfigData.Alerts.General.NotifyOnce {
-		// Check if cache already contains event ID
-		if getCachebyID(event.ID) != nil {
+// checkEventFilters processes incoming event through configured filters to determine if it should generate a notification.
+// If not, the reason the event was dropped is also returned
+func checkEventFilters(event models.Event) (bool, string) {
+	for _, filter := range eventFilters(true) {
+		if ok, reason := filter.check(event); !ok {
 			log.Info().
 				Str("event_id", event.ID).
-				Msg("Event dropped - Already notified & notify_once is set")
-			return false
+				Str("camera", event.Camera).
+				Str("label", event.Label).
+				Str("zones", strings.Join(event.CurrentZones, ",")).
+				Msg("Event dropped - " + reason)
+			return false, reason
 		}
 	}
+	return true, ""
+}
 
-	// Check if already notified on zones
-	if zoneAlreadyAlerted(event) {
-		log.Info().
-			Str("event_id", event.ID).
-			Str("camera", event.Camera).
-			Str("label", event.Label).
-			Str("zones", strings.Join(event.CurrentZones, ",")).
-			Msg("Event dropped - Already notified on this zone")
-		return false
-	} else {
-		log.Debug().
-			Str("event_id", event.ID).
-			Str("camera", event.Camera).
-			Str("label", event.Label).
-			Str("zones", strings.Join(event.CurrentZones, ",")).
-			Msg("Object entered new zone")
-	}
-
-	// Drop event if no snapshot & skip_nosnap is true
-	if !event.HasSnapshot && strings.ToLower(config.ConfigData.Alerts.General.NoSnap) == "drop" {
-		log.Info().
-			Str("event_id", event.ID).
-			Msg("Event dropped - No snapshot available")
-		return false
-	}
-
-	// Check quiet hours
-	if isQuietHours() {
-		log.Info().
-			Str("event_id", event.ID).
-			Msg("Event dropped - Quiet hours.")
-		return false
-	}
-
-	// Check Zone filter
-	if !isAllowedZone(event.ID, event.CurrentZones) {
-		return false
-	}
-
-	// Check Label filter
-	if !isAllowedLabel(event.ID, event.Label, "label") {
-		return false
-	}
-
-	// Check label score
-	if !aboveMinScore(event.ID, event.TopScore) {
-		return false
-	}
+// isMuted returns whether an event was dropped because notifications are disabled, snoozed, or in quiet hours
+func isMuted(reason string) bool {
+	return reason == disabledReason || reason == snoozedReason || reason == quietReason
+}
 
-	// Check Sublabel filter
-	if len(event.SubLabel) == 0 {
-		if !isAllowedLabel(event.ID, "", "sublabel") {
-			return false
+// mutedReason returns why notifications for an event are currently muted, ex: notifications are disabled, snoozed or in quiet hours.
+// Returns an empty string if not muted
+func mutedReason(event models.Event) string {
+	for _, filter := range eventFilters(false) {
+		if filter.name != "notifications_enabled" && filter.name != "snooze" && filter.name != "quiet_hours" {
+			continue
 		}
-	} else {
-		if !isAllowedLabel(event.ID, event.SubLabel, "sublabel") {
-			return false
+		if ok, reason := filter.check(event); !ok {
+			return reason
 		}
-
 	}
+	return ""
+}
 
-	// Check license plate filterd
-	if !isAllowedLabel(event.ID, event.Data.RecognizedLicensePlate, "license_plate") {
-		return false
+// explainEventFilters runs every filter against an event, without updating the zone cache, & returns the result of each
+func explainEventFilters(event models.Event) []models.FilterStep {
+	var steps []models.FilterStep
+	for _, filter := range eventFilters(false) {
+		ok, reason := filter.check(event)
+		steps = append(steps, models.FilterStep{Filter: filter.name, Passed: ok, Reason: reason})
 	}
+	return steps
+}
+
+// eventDropped records events that will not generate a notification in history, metrics & the live stream
+func eventDropped(events []models.Event, reason string) {
+	history.Filtered(events, reason)
+	metrics.EventsDropped.WithLabelValues(reason).Inc()
+	stream.Filtered(events, reason)
+}
 
-	// Default
-	return true
+// reviewDropped records a review that will not generate a notification in history, metrics & the live stream
+func reviewDropped(review models.Review, reason string) {
+	history.FilteredReview(review, reason)
+	metrics.EventsDropped.WithLabelValues(reason).Inc()
+	stream.FilteredReview(review, reason)
 }
 
 // isQuietHours checks to see if current event time is within window for supressing notifications
 func isQuietHours() bool {
 	currentTime, _ := time.Parse("15:04:05", time.Now().Format("15:04:05"))
-	start, _ := time.Parse("15:04", config.ConfigData.Alerts.Quiet.Start)
-	end, _ := time.Parse("15:04", config.ConfigData.Alerts.Quiet.End)
+	start, _ := time.Parse("15:04", config.Current().Alerts.Quiet.Start)
+	end, _ := time.Parse("15:04", config.Current().Alerts.Quiet.End)
 	log.Trace().
 		Time("current_time", currentTime).
 		Time("quiet_start", start).
@@ -149,59 +211,47 @@ func isQuietHours() bool {
 }
 
 // isAllowedZone verifies whether a zone should be allowed to generate a notification
-func isAllowedZone(id string, zones []string) bool {
+func isAllowedZone(id string, zones []string) (bool, string) {
 	log.Trace().
 		Str("event_id", id).
 		Strs("zones", zones).
-		Str("allow_unzoned", config.ConfigData.Alerts.Zones.Unzoned).
-		Strs("blocked", config.ConfigData.Alerts.Zones.Block).
-		Strs("allowed", config.ConfigData.Alerts.Zones.Allow).
+		Str("allow_unzoned", config.Current().Alerts.Zones.Unzoned).
+		Strs("blocked", config.Current().Alerts.Zones.Block).
+		Strs("allowed", config.Current().Alerts.Zones.Allow).
 		Msg("Check allowed zone")
 	// By default, send events without a zone unless specified otherwise
-	if strings.ToLower(config.ConfigData.Alerts.Zones.Unzoned) == "drop" && len(zones) == 0 {
-		log.Info().
-			Str("event_id", id).
-			Str("zones", strings.Join(zones, ",")).
-			Msg("Event dropped - Outside of zone.")
-		return false
+	if strings.ToLower(config.Current().Alerts.Zones.Unzoned) == "drop" && len(zones) == 0 {
+		return false, "Outside of zone"
 	} else if len(zones) == 0 {
-		return true
+		return true, ""
 	}
 	// Check zone block list
 	for _, zone := range zones {
-		if slices.Contains(config.ConfigData.Alerts.Zones.Block, zone) {
-			log.Info().
-				Str("event_id", id).
-				Str("zones", strings.Join(zones, ",")).
-				Msg("Event dropped - Zone block list.")
-			return false
+		if slices.Contains(config.Current().Alerts.Zones.Block, zone) {
+			return false, "Zone block list"
 		}
 	}
 	// If no allow list, all events are permitted
-	if len(config.ConfigData.Alerts.Zones.Allow) == 0 {
-		return true
+	if len(config.Current().Alerts.Zones.Allow) == 0 {
+		return true, ""
 	}
 	// Check zone allow list
 	for _, zone := range zones {
-		if slices.Contains(config.ConfigData.Alerts.Zones.Allow, zone) {
-			return true
+		if slices.Contains(config.Current().Alerts.Zones.Allow, zone) {
+			return true, ""
 		}
 	}
 	// Default drop event
-	log.Info().
-		Str("event_id", id).
-		Str("zones", strings.Join(zones, ",")).
-		Msg("Event dropped - Not on zone allow list.")
-	return false
+	return false, "Not on zone allow list"
 }
 
 // isAllowedLabel verifies whether a label, sublabel, or license plate should be allowed to generate a notification
-func isAllowedLabel(id string, label string, kind string) bool {
+func isAllowedLabel(id string, label string, kind string) (bool, string) {
 	var blocked []string
 	var allowed []string
 	if kind == "label" {
-		blocked = config.ConfigData.Alerts.Labels.Block
-		allowed = config.ConfigData.Alerts.Labels.Allow
+		blocked = config.Current().Alerts.Labels.Block
+		allowed = config.Current().Alerts.Labels.Allow
 		log.Trace().
 			Str("event_id", id).
 			Str("label", label).
@@ -210,8 +260,8 @@ func isAllowedLabel(id string, label string, kind string) bool {
 			Msg("Check allowed label")
 	}
 	if kind == "sublabel" {
-		blocked = config.ConfigData.Alerts.SubLabels.Block
-		allowed = config.ConfigData.Alerts.SubLabels.Allow
+		blocked = config.Current().Alerts.SubLabels.Block
+		allowed = config.Current().Alerts.SubLabels.Allow
 		log.Trace().
 			Str("event_id", id).
 			Str("label", label).
@@ -220,8 +270,8 @@ func isAllowedLabel(id string, label string, kind string) bool {
 			Msg("Check allowed sublabel")
 	}
 	if kind == "license_plate" {
-		blocked = config.ConfigData.Alerts.LicensePlate.Block
-		allowed = config.ConfigData.Alerts.LicensePlate.Allow
+		blocked = config.Current().Alerts.LicensePlate.Block
+		allowed = config.Current().Alerts.LicensePlate.Allow
 		log.Trace().
 			Str("event_id", id).
 			Str("license_plate", label).
@@ -232,32 +282,24 @@ func isAllowedLabel(id string, label string, kind string) bool {
 
 	// Check block list
 	if slices.Contains(blocked, label) {
-		log.Info().
-			Str("event_id", id).
-			Str(kind, label).
-			Msgf("Event dropped - %s block list.", kind)
-		return false
+		return false, kind + " block list"
 	}
 	// If no allow list, all events are permitted
 	if len(allowed) == 0 {
-		return true
+		return true, ""
 	}
 	// Check allow list
 	if slices.Contains(allowed, label) {
-		return true
+		return true, ""
 	}
 
 	// Default drop event
-	log.Info().
-		Str("event_id", id).
-		Str(kind, label).
-		Msgf("Event dropped - Not on %s allow list.", kind)
-	return false
+	return false, "Not on " + kind + " allow list"
 }
 
 // aboveMinScore checks if label score is above configured minimum
-func aboveMinScore(id string, score float64) bool {
-	minScore := config.ConfigData.Alerts.Labels.MinScore
+func aboveMinScore(id string, score float64) (bool, string) {
+	minScore := config.Current().Alerts.Labels.MinScore
 	score = score * 100
 	log.Trace().
 		Str("event_id", id).
@@ -265,12 +307,7 @@ func aboveMinScore(id string, score float64) bool {
 		Float64("min_score", minScore).
 		Msg("Check minimum score")
 	if score >= minScore {
-		return true
-	} else {
-		log.Info().
-			Str("event_id", id).
-			Float64("score", score).
-			Msg("Event dropped - Does not meet minimum label score.")
-		return false
+		return true, ""
 	}
+	return false, "Does not meet minimum label score"
 }
@@ -1,102 +1,255 @@
 package events
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/util"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
-var mqtt_topic string
-var client mqtt.Client
+var (
+	mqttLock sync.Mutex
+	client   mqtt.Client
+	// Stops background connection attempts when MQTT is disconnected
+	mqttCancel context.CancelFunc
+)
+
+// SubscribeMQTT starts connecting to MQTT server in the background & subscribes to Frigate topic once connected.
+// If MQTT is unreachable, the app keeps running & connection attempts are retried as configured.
+// Returns an error without connecting if TLS settings can't be loaded
+func SubscribeMQTT() error {
+	mqttLock.Lock()
+	defer mqttLock.Unlock()
 
-// SubscribeMQTT establishes subscription to MQTT server & listens for messages
-func SubscribeMQTT() {
-	config.Internal.Status.Health = "frigate mqtt connecting"
-	config.Internal.Status.Frigate.MQTT = "connecting"
-	mqtt_topic = fmt.Sprintf("%s/%s", config.ConfigData.Frigate.MQTT.TopicPrefix, strings.ToLower(config.ConfigData.App.Mode))
+	config.State.SetHealth("frigate mqtt connecting")
+	config.State.SetFrigateMQTT("connecting")
+	mqttConfig := config.Current().Frigate.MQTT
+	topic := fmt.Sprintf("%s/%s", mqttConfig.TopicPrefix, strings.ToLower(config.Current().App.Mode))
 	// MQTT client configuration
-	mqttServer := fmt.Sprintf("tcp://%s:%d", config.ConfigData.Frigate.MQTT.Server, config.ConfigData.Frigate.MQTT.Port)
+	mqttServer := mqttBroker(mqttConfig)
 	opts := mqtt.NewClientOptions()
 	opts.AddBroker(mqttServer)
-	opts.SetClientID(config.ConfigData.Frigate.MQTT.ClientID)
+	if mqttConfig.Protocol == "ssl" || mqttConfig.Protocol == "wss" {
+		tlsConfig, err := mqttTLSConfig(mqttConfig.TLS)
+		if err != nil {
+			config.State.SetHealth("frigate mqtt tls error")
+			config.State.SetFrigateMQTT("tls error")
+			startFailover()
+			return fmt.Errorf("unable to load MQTT TLS settings: %w", err)
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	opts.SetClientID(mqttConfig.ClientID)
+	opts.SetCleanSession(mqttConfig.CleanSession)
 	opts.SetAutoReconnect(true)
+	opts.SetMaxReconnectInterval(time.Duration(mqttConfig.Retry.MaxInterval) * time.Second)
 	opts.SetConnectionLostHandler(connectionLostHandler)
-	opts.SetOnConnectHandler(connectHandler)
-	if config.ConfigData.Frigate.MQTT.Username != "" && config.ConfigData.Frigate.MQTT.Password != "" {
-		opts.SetUsername(config.ConfigData.Frigate.MQTT.Username)
-		opts.SetPassword(config.ConfigData.Frigate.MQTT.Password)
+	opts.SetReconnectingHandler(reconnectingHandler)
+	opts.SetOnConnectHandler(func(c mqtt.Client) {
+		connectHandler(ctx, c, topic, byte(mqttConfig.QoS), mqttConfig.Retry)
+	})
+	if mqttConfig.Username != "" && mqttConfig.Password != "" {
+		opts.SetUsername(mqttConfig.Username)
+		opts.SetPassword(mqttConfig.Password)
 	}
 
 	log.Trace().
 		Str("server", mqttServer).
-		Str("client_id", config.ConfigData.Frigate.MQTT.ClientID).
-		Str("username", config.ConfigData.Frigate.MQTT.Username).
+		Str("client_id", mqttConfig.ClientID).
+		Str("username", mqttConfig.Username).
 		Str("password", "--secret removed--").
-		Str("topic", mqtt_topic).
+		Str("topic", topic).
+		Str("ca", mqttConfig.TLS.CA).
+		Str("client_cert", mqttConfig.TLS.Cert).
+		Bool("ignoressl", mqttConfig.TLS.Insecure).
+		Int("qos", mqttConfig.QoS).
+		Bool("clean_session", mqttConfig.CleanSession).
+		Int("retry_attempts", mqttConfig.Retry.Attempts).
 		Bool("auto_reconnect", true).
 		Msg("Init MQTT connection")
 
-	var subscribed = false
-	var retry = 0
-	for !subscribed {
-		config.Internal.Status.Health = "frigate mqtt unable to connect"
-		config.Internal.Status.Frigate.MQTT = "unreachable"
-		if retry >= 3 {
-			log.Fatal().Msgf("Max retries exceeded. Failed to establish MQTT session to %s", config.ConfigData.Frigate.MQTT.Server)
+	client = mqtt.NewClient(opts)
+	mqttCancel = cancel
+	go connectMQTT(ctx, client, mqttServer, mqttConfig.Retry)
+	return nil
+}
+
+// connectMQTT attempts initial connection to MQTT server until successful, cancelled, or max attempts are reached.
+// Once connected, the MQTT client handles reconnecting automatically
+func connectMQTT(ctx context.Context, c mqtt.Client, server string, retry models.MQTTRetry) {
+	for attempt := 1; ; attempt++ {
+		token := c.Connect()
+		select {
+		case <-token.Done():
+		case <-ctx.Done():
+			return
+		}
+		if token.Error() == nil {
+			// Drop connection if MQTT was disconnected while connecting
+			if ctx.Err() != nil {
+				c.Disconnect(0)
+			}
+			return
+		}
+
+		config.State.SetHealth("frigate mqtt unreachable")
+		config.State.SetFrigateMQTT("unreachable")
+		startFailover()
+		if retry.Attempts > 0 && attempt >= retry.Attempts {
+			log.Error().
+				Err(token.Error()).
+				Str("server", server).
+				Msg("Max retries exceeded. Failed to establish MQTT session, no events will be received via MQTT")
+			return
 		}
-		// Connect to MQTT broker
-		client = mqtt.NewClient(opts)
-
-		if token := client.Connect(); token.Wait() && token.Error() != nil {
-			retry += 1
-			config.Internal.Status.Health = "frigate mqtt unable to connect"
-			config.Internal.Status.Frigate.MQTT = "unreachable"
-
-			log.Warn().Msgf("Could not connect to MQTT at %v: %v", config.ConfigData.Frigate.MQTT.Server, token.Error())
-			log.Warn().Msgf("Retrying in 10 seconds. Attempt %v of 3.", retry)
-			time.Sleep(10 * time.Second)
-			continue
+		delay := retryDelay(retry, attempt)
+		log.Warn().
+			Err(token.Error()).
+			Str("server", server).
+			Int("attempt", attempt).
+			Msgf("Could not connect to MQTT, retrying in %v", delay)
+		if !util.Wait(ctx, delay) {
+			return
 		}
-		return
 	}
 }
 
-// disconnectMQTT simply disconnects the MQTT client
+// retryDelay returns time to wait after a failed attempt, doubling from retry interval up to max interval
+func retryDelay(retry models.MQTTRetry, attempt int) time.Duration {
+	delay := time.Duration(retry.Interval) * time.Second
+	maxDelay := time.Duration(retry.MaxInterval) * time.Second
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// mqttBroker returns MQTT server URL for the configured transport protocol
+func mqttBroker(mqttConfig models.MQTT) string {
+	broker := url.URL{
+		Scheme: mqttConfig.Protocol,
+		Host:   net.JoinHostPort(mqttConfig.Server, strconv.Itoa(mqttConfig.Port)),
+	}
+	if broker.Scheme == "" {
+		broker.Scheme = "tcp"
+	}
+	if broker.Scheme == "ws" || broker.Scheme == "wss" {
+		broker.Path = mqttConfig.Path
+	}
+	return broker.String()
+}
+
+// mqttTLSConfig loads custom CA & client certificate for MQTT TLS connections.
+// Returns an error if any configured certificate can't be loaded
+func mqttTLSConfig(tlsSettings models.MQTTTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: tlsSettings.Insecure}
+	if tlsSettings.CA != "" {
+		ca, err := os.ReadFile(tlsSettings.CA)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid PEM certificates found in %s", tlsSettings.CA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if tlsSettings.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(tlsSettings.Cert, tlsSettings.Key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// DisconnectMQTT stops any pending connection attempts & disconnects the MQTT client
 func DisconnectMQTT() {
+	mqttLock.Lock()
+	defer mqttLock.Unlock()
+
 	log.Info().Msg("Ending MQTT session")
-	client.Disconnect(300)
+	stopFailover()
+	if mqttCancel != nil {
+		mqttCancel()
+		mqttCancel = nil
+	}
+	if client != nil {
+		client.Disconnect(300)
+		client = nil
+	}
 	log.Info().Msg("MQTT disconnected")
 }
 
 // connectionLostHandler logs error message on MQTT connection loss
 func connectionLostHandler(c mqtt.Client, err error) {
-	config.Internal.Status.Health = "frigate mqtt connection lost"
-	config.Internal.Status.Frigate.MQTT = "unreachable"
+	config.State.SetHealth("frigate mqtt connection lost")
+	config.State.SetFrigateMQTT("unreachable")
 	log.Error().
 		Err(err).
 		Msg("Lost connection to MQTT broker")
+	startFailover()
+}
+
+// reconnectingHandler logs message on each attempt to reconnect after MQTT connection loss
+func reconnectingHandler(c mqtt.Client, opts *mqtt.ClientOptions) {
+	config.State.SetFrigateMQTT("reconnecting")
+	log.Info().Msg("Reconnecting to MQTT...")
 }
 
-// connectHandler logs message on MQTT connection
-func connectHandler(client mqtt.Client) {
+// connectHandler subscribes to Frigate topic each time MQTT is connected or reconnected
+func connectHandler(ctx context.Context, c mqtt.Client, topic string, qos byte, retry models.MQTTRetry) {
 	log.Info().Msg("Connected to MQTT.")
-	config.Internal.Status.Health = "ok"
-	config.Internal.Status.Frigate.MQTT = "ok"
-	if subscription := client.Subscribe(mqtt_topic, 0, handleMQTTMsg); subscription.Wait() && subscription.Error() != nil {
-		config.Internal.Status.Health = "frigate mqtt unable to subscribe"
-		config.Internal.Status.Frigate.MQTT = "unreachable"
-		log.Error().Msgf("Failed to subscribe to topic: %s", mqtt_topic)
-		time.Sleep(10 * time.Second)
-	}
+	// Subscribe in background, since MQTT client does not process messages until connect handler returns
+	go subscribeMQTT(ctx, c, topic, qos, retry)
+}
+
+// subscribeMQTT subscribes to Frigate topic, retrying until successful, cancelled, or MQTT connection is lost
+func subscribeMQTT(ctx context.Context, c mqtt.Client, topic string, qos byte, retry models.MQTTRetry) {
+	for attempt := 1; c.IsConnectionOpen(); attempt++ {
+		token := c.Subscribe(topic, qos, handleMQTTMsg)
+		token.Wait()
+		err := token.Error()
+		if sub, ok := token.(*mqtt.SubscribeToken); ok && err == nil && sub.Result()[topic] == 0x80 {
+			err = fmt.Errorf("subscription rejected by MQTT broker")
+		}
+		if err == nil {
+			stopFailover()
+			config.State.SetHealth("ok")
+			config.State.SetFrigateMQTT("ok")
+			log.Info().Msgf("Subscribed to MQTT topic: %s", topic)
+			return
+		}
 
-	log.Info().Msgf("Subscribed to MQTT topic: %s", mqtt_topic)
+		config.State.SetHealth("frigate mqtt unable to subscribe")
+		config.State.SetFrigateMQTT("unable to subscribe")
+		startFailover()
+		delay := retryDelay(retry, attempt)
+		log.Error().
+			Err(err).
+			Msgf("Failed to subscribe to topic: %s, retrying in %v", topic, delay)
+		if !util.Wait(ctx, delay) {
+			return
+		}
+	}
 }
 
 // handleMQTTMsg processes incoming MQTT messages depending on topic
@@ -118,6 +271,11 @@ func handleMQTTMsg(client mqtt.Client, msg mqtt.Message) {
 			log.Debug().
 				Str("review_id", review.After.ID).
 				Msg("New review received")
+			setLastQueryTime(review.After.StartTime)
+			if !firstSeen(review.After.ID) {
+				return
+			}
+			reviewReceived(review.After.Review, metrics.SourceMQTT)
 			processReview(review.After.Review)
 		case "update":
 			log.Debug().
@@ -135,6 +293,7 @@ func handleMQTTMsg(client mqtt.Client, msg mqtt.Message) {
 					CurrentZones: review.After.Data.Zones,
 				})
 			}
+			processReviewEnd(review.After.Review)
 		}
 	case "events":
 		var event models.MQTTEvent
@@ -145,6 +304,11 @@ func handleMQTTMsg(client mqtt.Client, msg mqtt.Message) {
 			log.Info().
 				Str("event_id", event.After.ID).
 				Msg("New event received")
+			setLastQueryTime(event.After.StartTime)
+			if !firstSeen(event.After.ID) {
+				return
+			}
+			eventReceived(event.After.Event, metrics.SourceMQTT)
 			processEvent(event.After.Event)
 		case "update":
 			log.Info().
@@ -156,6 +320,7 @@ func handleMQTTMsg(client mqtt.Client, msg mqtt.Message) {
 				Str("event_id", event.After.ID).
 				Msg("Event ended")
 			delZoneAlerted(event.After.Event)
+			processEventEnd(event.After.Event)
 		}
 
 	}
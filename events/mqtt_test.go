@@ -0,0 +1,194 @@
+package events
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestMQTTBroker(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   models.MQTT
+		expected string
+	}{
+		{"default protocol", models.MQTT{Server: "mqtt.test", Port: 1883}, "tcp://mqtt.test:1883"},
+		{"ssl", models.MQTT{Server: "mqtt.test", Port: 8883, Protocol: "ssl", Path: "/ignored"}, "ssl://mqtt.test:8883"},
+		{"websocket path", models.MQTT{Server: "mqtt.test", Port: 443, Protocol: "wss", Path: "/mqtt"}, "wss://mqtt.test:443/mqtt"},
+		{"ipv6", models.MQTT{Server: "2001:db8::10", Port: 9001, Protocol: "ws"}, "ws://[2001:db8::10]:9001"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := mqttBroker(tc.config)
+			if result != tc.expected {
+				t.Errorf("Expected: %s, Got: %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestMQTTTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := writeTestCert(t, dir)
+
+	tlsConfig, err := mqttTLSConfig(models.MQTTTLS{CA: cert, Cert: cert, Key: key, Insecure: true})
+	if err != nil {
+		t.Fatal("Expected no error, Got: ", err)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 || !tlsConfig.InsecureSkipVerify {
+		t.Errorf("Expected CA, client certificate & ignoressl set, Got: %+v", tlsConfig)
+	}
+
+	// Check invalid CA
+	if _, err := mqttTLSConfig(models.MQTTTLS{CA: key}); err == nil {
+		t.Error("Expected error for invalid CA certificate")
+	}
+	if _, err := mqttTLSConfig(models.MQTTTLS{Cert: cert, Key: filepath.Join(dir, "missing.key")}); err == nil {
+		t.Error("Expected error for missing client key")
+	}
+}
+
+func TestSubscribeMQTTInvalidTLS(t *testing.T) {
+	dir := t.TempDir()
+	_, key := writeTestCert(t, dir)
+	restore := config.Update(func(c *config.Config) {
+		c.Frigate.MQTT = models.MQTT{Server: "127.0.0.1", Port: 1, Protocol: "ssl", TLS: models.MQTTTLS{CA: key}}
+	})
+	defer restore()
+
+	// Check MQTT is not connected with partial TLS settings
+	if err := SubscribeMQTT(); err == nil {
+		t.Error("Expected error for invalid CA certificate")
+	}
+	if client != nil {
+		t.Error("Expected no MQTT client")
+	}
+	if config.State.FrigateMQTT() != "tls error" {
+		t.Errorf("Expected: tls error, Got: %v", config.State.FrigateMQTT())
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	retry := models.MQTTRetry{Interval: 10, MaxInterval: 60}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 60 * time.Second, 60 * time.Second}
+	for i, delay := range expected {
+		result := retryDelay(retry, i+1)
+		if result != delay {
+			t.Errorf("Attempt %v - Expected: %v, Got: %v", i+1, delay, result)
+		}
+	}
+}
+
+func TestConnectMQTT(t *testing.T) {
+	opts := mqtt.NewClientOptions().AddBroker("tcp://127.0.0.1:1")
+	opts.SetConnectTimeout(time.Second)
+	c := mqtt.NewClient(opts)
+
+	// Check app keeps running after max attempts
+	connectMQTT(context.Background(), c, "tcp://127.0.0.1:1", models.MQTTRetry{Attempts: 1, Interval: 1, MaxInterval: 1})
+	if config.State.FrigateMQTT() != "unreachable" {
+		t.Errorf("Expected: unreachable, Got: %v", config.State.FrigateMQTT())
+	}
+
+	// Check retrying forever stops when cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		connectMQTT(ctx, c, "tcp://127.0.0.1:1", models.MQTTRetry{Interval: 60, MaxInterval: 60})
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected connection attempts to stop when cancelled")
+	}
+}
+
+// failedSubscribeClient is a connected MQTT client where subscribing always fails
+type failedSubscribeClient struct {
+	mqtt.Client
+}
+
+func (failedSubscribeClient) IsConnectionOpen() bool { return true }
+
+func (failedSubscribeClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return failedToken{}
+}
+
+// failedToken is a completed MQTT token with an error
+type failedToken struct{}
+
+func (failedToken) Wait() bool                     { return true }
+func (failedToken) WaitTimeout(time.Duration) bool { return true }
+func (failedToken) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+func (failedToken) Error() error { return errors.New("not authorized") }
+
+func TestSubscribeMQTT(t *testing.T) {
+	// Check retrying stops when cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		subscribeMQTT(ctx, failedSubscribeClient{}, "frigate/events", 0, models.MQTTRetry{Interval: 60, MaxInterval: 60})
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected subscribe attempts to stop when cancelled")
+	}
+	if config.State.FrigateMQTT() != "unable to subscribe" {
+		t.Errorf("Expected: unable to subscribe, Got: %v", config.State.FrigateMQTT())
+	}
+}
+
+// writeTestCert generates a self-signed certificate & key, returning their file paths
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "test.crt")
+	keyFile := filepath.Join(dir, "test.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return certFile, keyFile
+}
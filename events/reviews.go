@@ -1,25 +1,30 @@
 package events
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/0x2142/frigate-notify/models"
 	"github.com/0x2142/frigate-notify/notifier"
+	"github.com/0x2142/frigate-notify/stream"
 	"github.com/0x2142/frigate-notify/util"
 	"github.com/rs/zerolog/log"
 )
 
 // processReview handles querying detections under a review & preparing for sending an alert
 func processReview(review models.Review) {
-	if config.ConfigData.Alerts.General.RecheckDelay != 0 {
+	if config.Current().Alerts.General.RecheckDelay != 0 {
 		review = recheckReview(review)
 	}
 
-	config.Internal.Status.LastEvent = time.Now()
+	config.State.SetLastEvent(time.Now())
 
 	// Convert to human-readable timestamp
 	reviewTime := time.Unix(int64(review.StartTime), 0)
@@ -36,41 +41,51 @@ func processReview(review models.Review) {
 		Str("review_id", review.ID).
 		Msgf("Review start time: %s", reviewTime)
 
-	if !config.ConfigData.Alerts.General.NotifyDetections && review.Severity == "detection" {
+	if !config.Current().Alerts.General.NotifyDetections && review.Severity == "detection" {
 		log.Info().
 			Str("review_id", review.ID).
 			Msg("Review dropped - Event is detection only, not alert")
+		reviewDropped(review, "Detection only, not alert")
 		return
 	}
 
 	// Check if audio-only event
 	if len(review.Data.Detections) == 0 && len(review.Data.Audio) != 0 {
-		if config.ConfigData.Alerts.General.AudioOnly == "allow" {
+		if config.Current().Alerts.General.AudioOnly == "allow" {
 			// Assemble some info via Review item, since there is no detection event to look up
 			var audioEvent models.Event
 			audioEvent.StartTime = review.StartTime
 			audioEvent.Extra.Audio = strings.Join(review.Data.Audio, ",")
 			audioEvent.Camera = review.Camera
-			audioEvent.Extra.ReviewLink = config.ConfigData.Frigate.PublicURL + "/review?id=" + review.ID
+			audioEvent.Extra.ReviewLink = config.Current().Frigate.PublicURL + "/review?id=" + review.ID
+			audioEvent.Extra.ReviewID = review.ID
+			if inCooldown([]models.Event{audioEvent}) {
+				reviewDropped(review, cooldownReason)
+				return
+			}
 			notifier.SendAlert([]models.Event{audioEvent})
 			return
 		} else {
 			log.Info().
 				Str("review_id", review.ID).
 				Msg("Review dropped - Audio only event")
+			reviewDropped(review, "Audio only")
 			return
 		}
 	}
 
+	// Check if notifications were already sent for this review, which may need to be updated
+	updating := config.Current().Alerts.General.UpdateMessages && notifier.AlertSent(review.ID)
+
 	// Retrieve detailed detection information
-	reviewFiltered := false
-	var detections []models.Event
+	var filterReason string
+	var detections, allDetections []models.Event
 	for _, id := range review.Data.Detections {
-		url := fmt.Sprintf("%s/api/events/%s", config.ConfigData.Frigate.Server, id)
+		url := fmt.Sprintf("%s/api/events/%s", config.Current().Frigate.Server, id)
 
-		response, err := util.HTTPGet(url, config.ConfigData.Frigate.Insecure, "")
+		response, err := util.HTTPGet(context.Background(), url, config.Current().Frigate.Insecure, "")
 		if err != nil {
-			config.Internal.Status.Frigate.API = "unreachable"
+			config.State.SetFrigateAPI("unreachable")
 			log.Error().
 				Err(err).
 				Str("review_id", review.ID).
@@ -78,7 +93,7 @@ func processReview(review models.Review) {
 				Msgf("Unable to retrieve detection information")
 			continue
 		}
-		config.Internal.Status.Frigate.API = "ok"
+		config.State.SetFrigateAPI("ok")
 
 		var detection models.Event
 		json.Unmarshal(response, &detection)
@@ -90,36 +105,65 @@ func processReview(review models.Review) {
 		}
 
 		// Wait for license plate data before notifying, if set
-		if config.ConfigData.Alerts.LicensePlate.Enabled {
+		if config.Current().Alerts.LicensePlate.Enabled {
 			waitforLPR(&detection)
 		}
 
-		// Check that event passes configured filters
+		// Add special link to review page
+		detection.Extra.ReviewLink = config.Current().Frigate.PublicURL + "/review?id=" + review.ID
+		detection.Extra.ReviewID = review.ID
 		detection.CurrentZones = detection.Zones
-		if !checkEventFilters(detection) {
-			reviewFiltered = true
-			break
+		allDetections = append(allDetections, detection)
+
+		// Once a detection is filtered, remaining detections are only collected to update previous notification.
+		// Filters are skipped, since they update the zone cache
+		if filterReason != "" {
+			continue
 		}
 
-		// Add special link to review page
-		detection.Extra.ReviewLink = config.ConfigData.Frigate.PublicURL + "/review?id=" + review.ID
+		// Check that event passes configured filters
+		if ok, reason := checkEventFilters(detection); !ok {
+			filterReason = reason
+			// Keep collecting details of remaining detections if previous notification may be updated
+			if !updating {
+				break
+			}
+			continue
+		}
 
 		detections = append(detections, detection)
 	}
 
+	// Edit previously sent notifications if review details have changed
+	if updating && (len(detections) == 0 || filterReason != "") {
+		reviewDropped(review, cmp.Or(filterReason, "No events eligible for notification"))
+		if !isMuted(filterReason) {
+			notifier.UpdateAlert(allDetections)
+		}
+		return
+	}
+
 	// Check to make sure at least 1 detection passed filters
 	if len(detections) == 0 {
 		log.Info().
 			Str("review_id", review.ID).
 			Msgf("Review dropped - No events eligible for notification")
+		reviewDropped(review, cmp.Or(filterReason, "No events eligible for notification"))
 		return
 	}
 
 	// If any detection would be filtered, skip notifying on this review
-	if reviewFiltered {
+	if filterReason != "" {
 		log.Info().
 			Str("review_id", review.ID).
 			Msgf("Review dropped - One or more detections are filtered")
+		reviewDropped(review, filterReason)
+		return
+	}
+
+	// Check if cameras & labels recently notified
+	if inCooldown(detections) {
+		reviewDropped(review, cooldownReason)
 		return
 	}
 
@@ -127,8 +171,78 @@ func processReview(review models.Review) {
 	notifier.SendAlert(detections)
 }
 
+// processReviewEnd sends a follow-up notification for an ended review, if an alert was sent for it
+func processReviewEnd(review models.Review) {
+	if !config.Current().Alerts.General.NotifyEnd || !notifier.AlertSent(review.ID) {
+		return
+	}
+
+	var detections []models.Event
+	for _, id := range review.Data.Detections {
+		url := fmt.Sprintf("%s/api/events/%s", config.Current().Frigate.Server, id)
+
+		response, err := util.HTTPGet(context.Background(), url, config.Current().Frigate.Insecure, "")
+		if err != nil {
+			config.State.SetFrigateAPI("unreachable")
+			log.Error().
+				Err(err).
+				Str("review_id", review.ID).
+				Str("detection_id", id).
+				Msgf("Unable to retrieve detection information")
+			continue
+		}
+		config.State.SetFrigateAPI("ok")
+
+		var detection models.Event
+		json.Unmarshal(response, &detection)
+		if detection.TopScore == 0 {
+			detection.TopScore = detection.Data.TopScore
+		}
+		if detection.EndTime == nil {
+			detection.EndTime = review.EndTime
+		}
+		detection.Extra.ReviewLink = config.Current().Frigate.PublicURL + "/review?id=" + review.ID
+		detection.Extra.ReviewID = review.ID
+		detection.CurrentZones = detection.Zones
+		detections = append(detections, detection)
+	}
+
+	// Audio-only reviews have no detection events, so assemble info via review item
+	if len(detections) == 0 {
+		var audioEvent models.Event
+		audioEvent.StartTime = review.StartTime
+		audioEvent.EndTime = review.EndTime
+		audioEvent.Extra.Audio = strings.Join(review.Data.Audio, ",")
+		audioEvent.Camera = review.Camera
+		audioEvent.Extra.ReviewLink = config.Current().Frigate.PublicURL + "/review?id=" + review.ID
+		audioEvent.Extra.ReviewID = review.ID
+		detections = append(detections, audioEvent)
+	}
+
+	for _, detection := range detections {
+		if reason := mutedReason(detection); reason != "" {
+			log.Info().
+				Str("review_id", review.ID).
+				Msg("Follow-up skipped - " + reason)
+			return
+		}
+	}
+
+	notifier.SendFollowUp(detections)
+}
+
+// reviewReceived records a new review from Frigate in the live stream & in metrics, once for each detected label
+func reviewReceived(review models.Review, source string) {
+	labels := append(slices.Clone(review.Data.Objects), review.Data.Audio...)
+	slices.Sort(labels)
+	for _, label := range slices.Compact(labels) {
+		metrics.EventsReceived.WithLabelValues(review.Camera, label, source).Inc()
+	}
+	stream.ReviewReceived(review, source)
+}
+
 func recheckReview(review models.Review) models.Review {
-	delay := config.ConfigData.Alerts.General.RecheckDelay
+	delay := config.Current().Alerts.General.RecheckDelay
 	log.Debug().
 		Str("review_id", review.ID).
 		Int("recheck_delay", delay).
@@ -139,18 +253,18 @@ func recheckReview(review models.Review) models.Review {
 		Int("recheck_delay", delay).
 		Msg("Re-checking review details")
 
-	url := config.ConfigData.Frigate.Server + "/api/review/" + review.ID
-	response, err := util.HTTPGet(url, config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
+	url := config.Current().Frigate.Server + "/api/review/" + review.ID
+	response, err := util.HTTPGet(context.Background(), url, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
 	if err != nil {
-		config.Internal.Status.Health = "frigate webapi unreachable"
-		config.Internal.Status.Frigate.API = "unreachable"
+		config.State.SetHealth("frigate webapi unreachable")
+		config.State.SetFrigateAPI("unreachable")
 		log.Error().
 			Err(err).
 			Msgf("Cannot get event from %s", url)
 		return review
 	}
-	config.Internal.Status.Health = "ok"
-	config.Internal.Status.Frigate.API = "ok"
+	config.State.SetHealth("ok")
+	config.State.SetFrigateAPI("ok")
 
 	json.Unmarshal([]byte(response), &review)
 	return review
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+func TestProcessReviewUpdateFiltered(t *testing.T) {
+	// Setup
+	InitZoneCache()
+	defer CloseZoneCache()
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+	frigate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/events/filtered-detection":
+			w.Write([]byte(`{"id": "filtered-detection", "camera": "front_door", "label": "person", "zones": ["street"], "has_snapshot": true}`))
+		case "/api/events/other-detection":
+			w.Write([]byte(`{"id": "other-detection", "camera": "front_door", "label": "person", "zones": ["driveway"], "has_snapshot": true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer frigate.Close()
+	config.State.SetNotificationsEnabled(true)
+	defer config.Update(func(c *config.Config) {
+		c.Frigate.Server = frigate.URL
+		c.Alerts.General.UpdateMessages = true
+		c.Alerts.Zones.Block = []string{"street"}
+	})()
+
+	// Mark review as already notified, so details are collected to update it
+	storage.Put("messages", "review-update-test", map[string]string{})
+
+	review := models.Review{ID: "review-update-test", Camera: "front_door", Severity: "alert"}
+	review.Data.Detections = []string{"filtered-detection", "other-detection"}
+	processReview(review)
+
+	// Check detections after a filtered detection do not update zone cache
+	if getCachebyID("other-detection") != nil {
+		t.Errorf("Expected: zone cache not updated, Got: %v", getCachebyID("other-detection"))
+	}
+}
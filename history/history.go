@@ -0,0 +1,333 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+const bucket = "history"
+
+// Overall result of processing an event or review
+const (
+	OutcomeFiltered = "filtered"
+	OutcomePending  = "pending"
+	OutcomeSent     = "sent"
+	OutcomePartial  = "partial"
+	OutcomeFailed   = "failed"
+)
+
+// Result of sending an alert via a single notification provider profile
+const (
+	StatusFiltered = "filtered"
+	StatusCooldown = "cooldown"
+	StatusDigest   = "digest"
+	StatusPending  = "pending"
+	StatusSent     = "sent"
+	StatusFailed   = "failed"
+	StatusDryRun   = "dry_run"
+)
+
+// Interval between removing history items older than configured retention
+const pruneInterval = time.Hour
+
+// Maximum number of history items returned in a single page
+const MaxPageSize = 500
+
+// Query filters & pages through notification history
+type Query struct {
+	Camera   string
+	Label    string
+	Provider string
+	Outcome  string
+	Page     int
+	PageSize int
+}
+
+var (
+	lock    sync.Mutex
+	errStop = errors.New("stop")
+
+	pruneLock sync.Mutex
+	// App context pruning runs under, set by Start
+	pruneCtx context.Context
+	// Stops pruning, or nil if not running
+	pruneCancel context.CancelFunc
+	// Closed once pruning has stopped
+	pruneDone chan struct{}
+)
+
+// Start periodically removes history items older than configured retention while history is enabled, until ctx is cancelled.
+// Refresh starts or stops pruning after config is reloaded
+func Start(ctx context.Context) {
+	pruneLock.Lock()
+	pruneCtx = ctx
+	pruneLock.Unlock()
+	Refresh()
+}
+
+// Refresh starts pruning if history was enabled by a config reload, or stops it if history was disabled
+func Refresh() {
+	pruneLock.Lock()
+	defer pruneLock.Unlock()
+
+	switch {
+	case pruneCtx == nil:
+		return
+	case enabled() && pruneCancel == nil:
+		log.Debug().
+			Int("items", storage.Count(bucket)).
+			Int("retention", config.Current().App.History.Retention).
+			Msg("Starting notification history")
+		var ctx context.Context
+		ctx, pruneCancel = context.WithCancel(pruneCtx)
+		pruneDone = make(chan struct{})
+		go runPrune(ctx, pruneDone)
+	case !enabled() && pruneCancel != nil:
+		log.Debug().Msg("Stopping notification history")
+		// Wait for any prune in progress to finish
+		pruneCancel()
+		<-pruneDone
+		pruneCancel = nil
+	}
+}
+
+// runPrune removes expired history items every pruneInterval, until ctx is cancelled. Closes done when stopped
+func runPrune(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		prune(time.Now().AddDate(0, 0, -config.Current().App.History.Retention))
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// enabled returns whether notification history should be recorded
+func enabled() bool {
+	return config.Current().App.History.Enabled && storage.Ready()
+}
+
+// Record saves a new history item for events about to be sent to notification providers & returns its ID
+func Record(events []models.Event) string {
+	item := newItem(events)
+	item.Outcome = OutcomePending
+	return add(item)
+}
+
+// Filtered saves a new history item for events that were dropped by a filter
+func Filtered(events []models.Event, reason string) {
+	item := newItem(events)
+	item.Outcome = OutcomeFiltered
+	item.Reason = reason
+	add(item)
+}
+
+// FilteredReview saves a new history item for a review that was dropped before its detections were checked
+func FilteredReview(review models.Review, reason string) {
+	item := models.HistoryItem{
+		ReviewID: review.ID,
+		Camera:   review.Camera,
+		Labels:   append(slices.Clone(review.Data.Objects), review.Data.Audio...),
+		Zones:    review.Data.Zones,
+		Outcome:  OutcomeFiltered,
+		Reason:   reason,
+	}
+	add(item)
+}
+
+// SetProvider records the result of sending an alert via a single provider profile & updates the overall outcome
+func SetProvider(id, provider string, index int, status, detail string) {
+	if id == "" || !enabled() {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	var item models.HistoryItem
+	ok, err := storage.Get(bucket, id, &item)
+	if err != nil || !ok {
+		// Item may have been removed by retention
+		return
+	}
+	result := models.HistoryProvider{Provider: provider, ProfileID: index, Status: status, Detail: detail, Time: time.Now()}
+	i := slices.IndexFunc(item.Providers, func(p models.HistoryProvider) bool {
+		return p.Provider == provider && p.ProfileID == index
+	})
+	if i >= 0 {
+		item.Providers[i] = result
+	} else {
+		item.Providers = append(item.Providers, result)
+	}
+	item.Outcome = outcome(item.Providers)
+
+	if err := storage.Put(bucket, id, item); err != nil {
+		log.Warn().
+			Str("history_id", id).
+			Err(err).
+			Msg("Unable to update notification history")
+	}
+}
+
+// Items returns one page of history items matching query, newest first, along with the total number of matches
+func Items(q Query) ([]models.HistoryItem, int, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 || q.PageSize > MaxPageSize {
+		q.PageSize = MaxPageSize
+	}
+	start := (q.Page - 1) * q.PageSize
+
+	items := []models.HistoryItem{}
+	var total int
+	err := storage.ForEachReverse(bucket, func(key string, value []byte) error {
+		var item models.HistoryItem
+		if err := json.Unmarshal(value, &item); err != nil {
+			return nil
+		}
+		if !q.matches(item) {
+			return nil
+		}
+		if total >= start && len(items) < q.PageSize {
+			items = append(items, item)
+		}
+		total++
+		return nil
+	})
+	return items, total, err
+}
+
+// matches returns whether a history item meets all query filters
+func (q Query) matches(item models.HistoryItem) bool {
+	if q.Camera != "" && item.Camera != q.Camera {
+		return false
+	}
+	if q.Label != "" && !slices.Contains(item.Labels, q.Label) {
+		return false
+	}
+	if q.Outcome != "" && item.Outcome != q.Outcome {
+		return false
+	}
+	if q.Provider != "" && !slices.ContainsFunc(item.Providers, func(p models.HistoryProvider) bool {
+		return p.Provider == q.Provider
+	}) {
+		return false
+	}
+	return true
+}
+
+// newItem collects details of events for a history item
+func newItem(events []models.Event) models.HistoryItem {
+	var item models.HistoryItem
+	if len(events) == 0 {
+		return item
+	}
+	item.EventID = events[0].ID
+	item.ReviewID = events[0].Extra.ReviewID
+	item.Camera = events[0].Camera
+	for _, event := range events {
+		label := event.Label
+		if label == "" {
+			label = event.Extra.Audio
+		}
+		if label != "" && !slices.Contains(item.Labels, label) {
+			item.Labels = append(item.Labels, label)
+		}
+		for _, zone := range event.CurrentZones {
+			if !slices.Contains(item.Zones, zone) {
+				item.Zones = append(item.Zones, zone)
+			}
+		}
+	}
+	return item
+}
+
+// add stores a new history item & returns its ID
+func add(item models.HistoryItem) string {
+	if !enabled() {
+		return ""
+	}
+	id, err := storage.NextID(bucket)
+	if err == nil {
+		item.ID = id
+		item.Time = time.Now()
+		err = storage.Put(bucket, id, item)
+	}
+	if err != nil {
+		log.Warn().
+			Str("event_id", item.EventID).
+			Err(err).
+			Msg("Unable to save notification history")
+		return ""
+	}
+	return id
+}
+
+// outcome returns the overall result based on each provider result. Alerts that were sent via some providers
+// but failed for others are partial, so failures are not hidden
+func outcome(providers []models.HistoryProvider) string {
+	var sent, pending, failed bool
+	for _, p := range providers {
+		switch p.Status {
+		case StatusSent, StatusDryRun, StatusDigest:
+			sent = true
+		case StatusPending:
+			pending = true
+		case StatusFailed:
+			failed = true
+		}
+	}
+	switch {
+	case pending:
+		return OutcomePending
+	case sent && failed:
+		return OutcomePartial
+	case sent:
+		return OutcomeSent
+	case failed:
+		return OutcomeFailed
+	}
+	return OutcomeFiltered
+}
+
+// prune removes history items recorded before cutoff
+func prune(cutoff time.Time) {
+	var expired []string
+	storage.ForEach(bucket, func(key string, value []byte) error {
+		var item models.HistoryItem
+		if err := json.Unmarshal(value, &item); err == nil && item.Time.After(cutoff) {
+			// Items are stored in order, so all remaining items are newer
+			return errStop
+		}
+		expired = append(expired, key)
+		return nil
+	})
+	if len(expired) == 0 {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if err := storage.Delete(bucket, expired...); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Unable to remove expired notification history")
+		return
+	}
+	log.Debug().
+		Int("removed", len(expired)).
+		Msg("Removed expired notification history")
+}
@@ -0,0 +1,118 @@
+package history
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+func TestHistory(t *testing.T) {
+	// Setup
+	if err := storage.Open(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Unable to open data store: %v", err)
+	}
+	defer storage.Close()
+	defer config.Update(func(c *config.Config) { c.App.History.Enabled = true })()
+
+	Filtered([]models.Event{{ID: "event-1", Camera: "front_door", Label: "person"}}, "Quiet hours")
+	id := Record([]models.Event{{ID: "event-2", Camera: "back_yard", Label: "dog"}, {ID: "event-3", Camera: "back_yard", Label: "cat"}})
+	SetProvider(id, "discord", 0, StatusFiltered, "Camera not on filter list")
+	SetProvider(id, "signal", 0, StatusPending, "")
+
+	// Check outcome is updated with each provider result
+	items, _, _ := Items(Query{Provider: "signal"})
+	if len(items) != 1 || items[0].Outcome != OutcomePending {
+		t.Fatalf("Expected: 1 pending item, Got: %v", items)
+	}
+	SetProvider(id, "signal", 0, StatusFailed, "timeout")
+	items, _, _ = Items(Query{Provider: "signal"})
+	if items[0].Outcome != OutcomeFailed || len(items[0].Providers) != 2 {
+		t.Errorf("Expected: failed with 2 providers, Got: %v", items[0])
+	}
+	SetProvider(id, "signal", 0, StatusSent, "")
+	items, _, _ = Items(Query{Outcome: OutcomeSent})
+	if len(items) != 1 || items[0].EventID != "event-2" {
+		t.Errorf("Expected: event-2 sent, Got: %v", items)
+	}
+
+	// Check failure via one provider is not hidden by another that succeeded
+	SetProvider(id, "ntfy", 0, StatusFailed, "timeout")
+	items, _, _ = Items(Query{Outcome: OutcomePartial})
+	if len(items) != 1 || items[0].EventID != "event-2" {
+		t.Errorf("Expected: event-2 partially sent, Got: %v", items)
+	}
+	SetProvider(id, "ntfy", 0, StatusSent, "")
+
+	// Check filters & paging
+	tests := []struct {
+		query    Query
+		expected int
+		total    int
+	}{
+		{Query{}, 2, 2},
+		{Query{Camera: "front_door"}, 1, 1},
+		{Query{Label: "cat"}, 1, 1},
+		{Query{Outcome: OutcomeFiltered}, 1, 1},
+		{Query{Provider: "telegram"}, 0, 0},
+		{Query{PageSize: 1}, 1, 2},
+		{Query{PageSize: 1, Page: 3}, 0, 2},
+	}
+	for i, test := range tests {
+		items, total, err := Items(test.query)
+		if err != nil || len(items) != test.expected || total != test.total {
+			t.Errorf("Test %d - Expected: %v of %v items, Got: %v of %v (%v)", i, test.expected, test.total, len(items), total, err)
+		}
+	}
+
+	// Check newest items returned first
+	items, _, _ = Items(Query{PageSize: 1, Page: 2})
+	if len(items) != 1 || items[0].EventID != "event-1" {
+		t.Errorf("Expected: event-1, Got: %v", items)
+	}
+
+	// Check expired items removed
+	prune(time.Now().Add(time.Hour))
+	if storage.Count(bucket) != 0 {
+		t.Errorf("Expected: 0 items, Got: %v", storage.Count(bucket))
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	// Setup
+	if err := storage.Open(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Unable to open data store: %v", err)
+	}
+	defer storage.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	running := func() bool {
+		pruneLock.Lock()
+		defer pruneLock.Unlock()
+		return pruneCancel != nil
+	}
+
+	// Pruning is not started while history is disabled
+	restore := config.Update(func(c *config.Config) { c.App.History.Enabled = false })
+	Start(ctx)
+	if running() {
+		t.Error("Expected: pruning not started")
+	}
+
+	// Pruning starts & stops as history is enabled & disabled by reloads
+	config.Update(func(c *config.Config) { c.App.History.Enabled = true })
+	Refresh()
+	if !running() {
+		t.Error("Expected: pruning started")
+	}
+	restore()
+	Refresh()
+	if running() {
+		t.Error("Expected: pruning stopped")
+	}
+}
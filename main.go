@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"flag"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"os/signal"
 	debuginfo "runtime/debug"
 	"strings"
+	"syscall"
 	"time"
 	_ "time/tzdata"
 
@@ -18,21 +20,28 @@ import (
 	"github.com/0x2142/frigate-notify/api"
 	"github.com/0x2142/frigate-notify/config"
 	"github.com/0x2142/frigate-notify/events"
+	"github.com/0x2142/frigate-notify/history"
 	"github.com/0x2142/frigate-notify/notifier"
+	"github.com/0x2142/frigate-notify/snooze"
+	"github.com/0x2142/frigate-notify/storage"
 	"github.com/0x2142/frigate-notify/util"
 )
 
 var debug, debugenv bool
 var jsonlog, jsonlogenv bool
 var nocolor, nocolorenv bool
+var dryrun bool
 var configFile string
 var logLevel string
 
 //go:embed templates/*
 var NotifTemplates embed.FS
 
+//go:embed web/*
+var WebUI embed.FS
+
 func main() {
-	config.Internal.Status.Health = "starting"
+	config.State.SetHealth("starting")
 
 	// Parse flags
 	flag.StringVar(&configFile, "c", "", "Configuration file location (default \"./config.yml\")")
@@ -40,6 +49,7 @@ func main() {
 	flag.StringVar(&logLevel, "loglevel", "", "Set logging level")
 	flag.BoolVar(&jsonlog, "jsonlog", false, "Enable JSON logging")
 	flag.BoolVar(&nocolor, "nocolor", false, "Disable color on console logging")
+	flag.BoolVar(&dryrun, "dryrun", false, "Log notifications instead of sending them")
 	flag.Parse()
 
 	// Set up logging
@@ -105,25 +115,51 @@ func main() {
 
 	// Load & validate config
 	config.ConfigFile = configFile
+	config.DryRun = dryrun
 	config.Load()
 
+	if config.IsDryRun() {
+		log.Warn().Msg("Dry run mode enabled, notifications will be logged instead of sent")
+	}
+
 	notifier.TemplateFiles = NotifTemplates
 
+	// Stop on interrupt or when container is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Open local data store & start notification queue
+	if err := storage.Open(config.Current().App.Storage.Path); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Unable to open local data store, alerts will be sent without queueing")
+	} else {
+		defer storage.Close()
+		notifier.StartQueue()
+		history.Start(ctx)
+	}
+	snooze.Load()
+	notifier.StartDigests()
+
+	// Start alert delivery workers & wait for pending alerts on shutdown
+	notifier.StartWorkers()
+	defer notifier.Shutdown(time.Duration(config.Current().App.Delivery.DrainTimeout) * time.Second)
+
 	// Set up monitor
-	if config.ConfigData.Monitor.Enabled {
+	if config.Current().Monitor.Enabled {
 		log.Debug().Msg("App monitoring enabled.")
 		go func() {
-			for {
-				_, err := util.HTTPGet(config.ConfigData.Monitor.URL, config.ConfigData.Monitor.Insecure, "")
+			for ctx.Err() == nil {
+				_, err := util.HTTPGet(ctx, config.Current().Monitor.URL, config.Current().Monitor.Insecure, "")
 				if err != nil {
-					config.Internal.Status.Monitor = err.Error()
+					config.State.SetMonitor(err.Error())
 					log.Warn().
 						Err(err).
 						Msg("Unable to reach polling monitoring URL")
 				}
-				config.Internal.Status.Monitor = "ok"
+				config.State.SetMonitor("ok")
 				log.Debug().Msg("Completed monitoring check-in.")
-				time.Sleep(time.Duration(config.ConfigData.Monitor.Interval) * time.Second)
+				util.Wait(ctx, time.Duration(config.Current().Monitor.Interval)*time.Second)
 			}
 		}()
 	}
@@ -133,37 +169,43 @@ func main() {
 	defer events.CloseZoneCache()
 
 	// Start API server if enabled
-	if config.ConfigData.App.API.Enabled {
+	if config.Current().App.API.Enabled {
+		api.WebFiles = WebUI
 		err := api.RunAPIServer()
 		if err != nil {
-			config.Internal.Status.API = err.Error()
+			config.State.SetAPI(err.Error())
 			log.Error().Err(err).Msg("Failed to start API server")
 		} else {
-			config.Internal.Status.API = "ok"
-			log.Info().Msgf("API server ready on :%v", config.ConfigData.App.API.Port)
+			config.State.SetAPI("ok")
+			log.Info().Msgf("API server ready on %v:%v", config.Current().App.API.Address, config.Current().App.API.Port)
 		}
 	}
 
 	// Loop & watch for events
-	if config.ConfigData.Frigate.WebAPI.Enabled {
+	if config.Current().Frigate.WebAPI.Enabled {
 		log.Info().Msg("App ready!")
-		config.Internal.Status.Health = "ok"
-		for {
-			events.QueryAPI()
-			time.Sleep(time.Duration(config.ConfigData.Frigate.WebAPI.Interval) * time.Second)
+		config.State.SetHealth("ok")
+		for ctx.Err() == nil {
+			events.QueryAPI(ctx)
+			util.Wait(ctx, time.Duration(config.Current().Frigate.WebAPI.Interval)*time.Second)
 		}
 	}
 
 	// Connect MQTT
-	if config.ConfigData.Frigate.MQTT.Enabled {
+	if config.Current().Frigate.MQTT.Enabled {
 		log.Debug().Msg("Connecting to MQTT Server...")
-		events.SubscribeMQTT()
+		// Health is updated once connected, app keeps running if MQTT is unreachable
+		if err := events.SubscribeMQTT(); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Unable to connect to MQTT, no events will be received via MQTT")
+		}
 		defer events.DisconnectMQTT()
 		log.Info().Msg("App ready!")
-		config.Internal.Status.Health = "ok"
-		sig := make(chan os.Signal, 1)
-		signal.Notify(sig, os.Interrupt)
-		<-sig
+		<-ctx.Done()
 	}
 
+	log.Info().Msg("Shutting down...")
+	config.State.SetHealth("shutting down")
+
 }
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/0x2142/frigate-notify/config"
+)
+
+const namespace = "frigate_notify"
+
+// Source of incoming events
+const (
+	SourceMQTT   = "mqtt"
+	SourceWebAPI = "webapi"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	// EventsReceived counts new events & reviews received from Frigate
+	EventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_received_total",
+		Help:      "Number of new events or reviews received from Frigate",
+	}, []string{"camera", "label", "source"})
+
+	// EventsDropped counts events & reviews dropped by app-level filters
+	EventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_dropped_total",
+		Help:      "Number of events or reviews dropped by filters, by reason",
+	}, []string{"reason"})
+
+	// AlertsDropped counts alerts dropped by provider-level filters
+	AlertsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_dropped_total",
+		Help:      "Number of alerts dropped by notification provider filters, by reason",
+	}, []string{"provider", "provider_id", "reason"})
+
+	// Notifications counts attempts to send alerts via each provider profile
+	Notifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Number of notifications sent via each notification provider profile, by result",
+	}, []string{"provider", "provider_id", "result"})
+
+	// NotificationDuration tracks time taken to send alerts via each provider
+	NotificationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "notification_duration_seconds",
+		Help:      "Time taken to send a notification",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"provider"})
+
+	// SnapshotDuration tracks time taken to download snapshots from Frigate, including retries
+	SnapshotDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "snapshot_duration_seconds",
+		Help:      "Time taken to download a snapshot from Frigate, including retries",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	// SnapshotRetries counts retries while waiting for a snapshot to become available
+	SnapshotRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "snapshot_retries_total",
+		Help:      "Number of retries while waiting for a snapshot to be available",
+	})
+
+	// Snapshots counts snapshot downloads from Frigate
+	Snapshots = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "snapshots_total",
+		Help:      "Number of snapshots downloaded from Frigate, by result",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		EventsReceived,
+		EventsDropped,
+		AlertsDropped,
+		Notifications,
+		NotificationDuration,
+		SnapshotDuration,
+		SnapshotRetries,
+		Snapshots,
+		// Connection state is read from app status when scraped
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "frigate_api_up",
+			Help:      "Whether the Frigate API is reachable (1) or not (0)",
+		}, func() float64 { return up(config.State.FrigateAPI()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "frigate_mqtt_up",
+			Help:      "Whether the Frigate MQTT broker is connected (1) or not (0)",
+		}, func() float64 { return up(config.State.FrigateMQTT()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "notifications_enabled",
+			Help:      "Whether notifications are currently enabled (1) or not (0)",
+		}, func() float64 {
+			if config.State.NotificationsEnabled() {
+				return 1
+			}
+			return 0
+		}),
+	)
+}
+
+// Handler returns HTTP handler to serve metrics in Prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// up converts a connection status to a gauge value
+func up(status string) float64 {
+	if status == "ok" {
+		return 1
+	}
+	return 0
+}
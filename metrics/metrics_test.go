@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+)
+
+func TestHandler(t *testing.T) {
+	EventsReceived.WithLabelValues("front_door", "person", SourceMQTT).Inc()
+	Notifications.WithLabelValues("discord", "0", "sent").Inc()
+	config.State.SetFrigateMQTT("ok")
+	defer config.State.SetFrigateMQTT("")
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200, got %v", rec.Code)
+	}
+
+	body := rec.Body.String()
+	expected := []string{
+		`frigate_notify_events_received_total{camera="front_door",label="person",source="mqtt"} 1`,
+		`frigate_notify_notifications_total{provider="discord",provider_id="0",result="sent"} 1`,
+		`frigate_notify_frigate_mqtt_up 1`,
+		`frigate_notify_frigate_api_up 0`,
+	}
+	for _, metric := range expected {
+		if !strings.Contains(body, metric) {
+			t.Errorf("Expected metric: %v", metric)
+		}
+	}
+}
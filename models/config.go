@@ -2,13 +2,66 @@ package models
 
 type App struct {
 	Mode     string   `koanf:"mode" json:"mode" enum:"events,reviews" doc:"Type of polling method used when connecting to Frigate" default:"reviews"`
+	DryRun   bool     `koanf:"dry_run" json:"dry_run,omitempty" enum:"true,false" doc:"Process events & render notifications, but log them instead of sending" default:"false"`
 	API      API      `koanf:"api" json:"api" doc:"Frigate-Notify API settings"`
+	Storage  Storage  `koanf:"storage" json:"storage,omitempty" doc:"Frigate-Notify local data storage settings"`
+	Queue    Queue    `koanf:"queue" json:"queue,omitempty" doc:"Outbound notification queue settings"`
+	Delivery Delivery `koanf:"delivery" json:"delivery,omitempty" doc:"Outbound notification delivery settings"`
+	History  History  `koanf:"history" json:"history,omitempty" doc:"Notification history settings"`
 	Internal Internal `koanf:"internal" json:"internal,omitempty" hidden:"true" doc:"Internal settings that alter the behavior of Frigate-Notify"`
 }
 
 type API struct {
-	Enabled bool `koanf:"enabled" json:"enabled" doc:"Enable Frigate-Notify API server" enum:"true,false" default:"false"`
-	Port    int  `koanf:"port" json:"port,omitempty" doc:"API server port" minimum:"1" maximum:"65535" default:"8000"`
+	Enabled           bool     `koanf:"enabled" json:"enabled" doc:"Enable Frigate-Notify API server" enum:"true,false" default:"false"`
+	Address           string   `koanf:"address" json:"address,omitempty" example:"127.0.0.1" doc:"API server listen address" default:"0.0.0.0"`
+	Port              int      `koanf:"port" json:"port,omitempty" doc:"API server port" minimum:"1" maximum:"65535" default:"8000"`
+	TLS               APITLS   `koanf:"tls" json:"tls,omitempty" doc:"Serve API via HTTPS"`
+	Auth              APIAuth  `koanf:"auth" json:"auth,omitempty" doc:"API authentication settings"`
+	AllowedImageHosts []string `koanf:"allowed_image_hosts" json:"allowed_image_hosts,omitempty" example:"cameras.local" doc:"Host names, IP addresses or CIDR ranges that manual alerts may download image_url from, in addition to the Frigate server"`
+}
+
+type APITLS struct {
+	Enabled bool   `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Enable HTTPS for API server" default:"false"`
+	Cert    string `koanf:"cert" json:"cert,omitempty" example:"/certs/frigate-notify.crt" doc:"Path to TLS certificate file" default:""`
+	Key     string `koanf:"key" json:"key,omitempty" example:"/certs/frigate-notify.key" doc:"Path to TLS private key file" default:""`
+}
+
+type APIAuth struct {
+	Tokens []APIToken `koanf:"tokens" json:"tokens,omitempty" doc:"Bearer tokens allowed to access the API"`
+	Users  []APIUser  `koanf:"users" json:"users,omitempty" doc:"Basic auth users allowed to access the API"`
+}
+
+type APIToken struct {
+	Name   string   `koanf:"name" json:"name,omitempty" doc:"Name used to identify token in logs" default:""`
+	Token  string   `koanf:"token" json:"token" secret:"true" doc:"Bearer token" required:"true"`
+	Scopes []string `koanf:"scopes" json:"scopes,omitempty" enum:"read,control,config" doc:"API access granted to this token, defaults to read"`
+}
+
+type APIUser struct {
+	Username string   `koanf:"username" json:"username" doc:"Basic auth username" required:"true"`
+	Password string   `koanf:"password" json:"password" secret:"true" doc:"Basic auth password" required:"true"`
+	Scopes   []string `koanf:"scopes" json:"scopes,omitempty" enum:"read,control,config" doc:"API access granted to this user, defaults to read"`
+}
+
+type Storage struct {
+	Path string `koanf:"path" json:"path,omitempty" doc:"Location of local data store" default:"./data/frigate-notify.db"`
+}
+
+type Queue struct {
+	Enabled      bool `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Queue outbound notifications on disk & retry on failure" default:"false"`
+	MaxAttempts  int  `koanf:"max_attempts" json:"max_attempts,omitempty" doc:"Max attempts to send a notification before moving it to the dead letter queue" minimum:"1" maximum:"10000" default:"10"`
+	InitialDelay int  `koanf:"initial_delay" json:"initial_delay,omitempty" doc:"Seconds to wait before first retry, doubled after each failed attempt" minimum:"1" maximum:"86400" default:"5"`
+	MaxDelay     int  `koanf:"max_delay" json:"max_delay,omitempty" doc:"Maximum seconds to wait between retries" minimum:"1" maximum:"86400" default:"300"`
+}
+
+type Delivery struct {
+	Workers      int `koanf:"workers" json:"workers,omitempty" doc:"Maximum number of notifications sent at the same time" minimum:"1" maximum:"1000" default:"10"`
+	DrainTimeout int `koanf:"drain_timeout" json:"drain_timeout,omitempty" doc:"Seconds to wait for pending notifications to be sent when shutting down" minimum:"1" maximum:"3600" default:"30"`
+}
+
+type History struct {
+	Enabled   bool `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Record processed events & notification results in local data store" default:"false"`
+	Retention int  `koanf:"retention" json:"retention,omitempty" doc:"Days to keep notification history" minimum:"1" maximum:"3650" default:"7"`
 }
 
 type Internal struct {
@@ -25,8 +78,8 @@ type Frigate struct {
 	Insecure     bool                `koanf:"ignoressl" json:"ignoressl,omitempty" enum:"true,false" doc:"Ignore TLS/SSL errors" default:"false"`
 	PublicURL    string              `koanf:"public_url" json:"public_url,omitempty" example:"https://frigate.test" doc:"Public/External-reachable URL for Frigate" default:""`
 	Username     string              `koanf:"username" json:"username,omitempty" doc:"Frigate username" default:""`
-	Password     string              `koanf:"password" json:"password,omitempty" doc:"Frigate password" default:""`
-	Headers      []map[string]string `koanf:"headers" json:"headers,omitempty" doc:"HTTP headers to include with requests to Frigate"`
+	Password     string              `koanf:"password" json:"password,omitempty" secret:"true" doc:"Frigate password" default:""`
+	Headers      []map[string]string `koanf:"headers" json:"headers,omitempty" secret:"true" doc:"HTTP headers to include with requests to Frigate"`
 	StartupCheck StartupCheck        `koanf:"startup_check" json:"startup_check,omitempty" doc:"Check connectivity to Frigate at app startup"`
 	WebAPI       WebAPI              `koanf:"webapi" json:"webapi,omitempty" doc:"Event collection via Frigate API"`
 	MQTT         MQTT                `koanf:"mqtt" json:"mqtt,omitempty" doc:"Event collection via MQTT"`
@@ -45,13 +98,33 @@ type WebAPI struct {
 }
 
 type MQTT struct {
-	Enabled     bool   `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Enable event collection via MQTT" default:"false"`
-	Server      string `koanf:"server" json:"server,omitempty" doc:"MQTT server address" default:""`
-	Port        int    `koanf:"port" json:"port,omitempty" doc:"MQTT port" minimum:"1" maximum:"65535" default:"1883"`
-	ClientID    string `koanf:"clientid" json:"clientid,omitempty" doc:"MQTT client ID" default:"frigate-notify"`
-	Username    string `koanf:"username" json:"username,omitempty" doc:"MQTT username" default:""`
-	Password    string `koanf:"password" json:"password,omitempty" doc:"MQTT password" default:""`
-	TopicPrefix string `koanf:"topic_prefix" json:"topic_prefix,omitempty" doc:"MQTT topic prefix" default:"frigate"`
+	Enabled      bool      `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Enable event collection via MQTT" default:"false"`
+	Server       string    `koanf:"server" json:"server,omitempty" doc:"MQTT server address" default:""`
+	Port         int       `koanf:"port" json:"port,omitempty" doc:"MQTT port" minimum:"1" maximum:"65535" default:"1883"`
+	ClientID     string    `koanf:"clientid" json:"clientid,omitempty" doc:"MQTT client ID" default:"frigate-notify"`
+	Username     string    `koanf:"username" json:"username,omitempty" doc:"MQTT username" default:""`
+	Password     string    `koanf:"password" json:"password,omitempty" secret:"true" doc:"MQTT password" default:""`
+	TopicPrefix  string    `koanf:"topic_prefix" json:"topic_prefix,omitempty" doc:"MQTT topic prefix" default:"frigate"`
+	Protocol     string    `koanf:"protocol" json:"protocol,omitempty" enum:"tcp,ssl,ws,wss" doc:"MQTT transport protocol" default:"tcp"`
+	Path         string    `koanf:"path" json:"path,omitempty" example:"/mqtt" doc:"URL path for websocket connections" default:""`
+	TLS          MQTTTLS   `koanf:"tls" json:"tls,omitempty" doc:"TLS settings for ssl & wss connections"`
+	QoS          int       `koanf:"qos" json:"qos,omitempty" enum:"0,1,2" doc:"QoS level for MQTT subscription" default:"1"`
+	CleanSession bool      `koanf:"clean_session" json:"clean_session,omitempty" enum:"true,false" doc:"Start a new MQTT session on each connection, instead of resuming subscription & queued messages" default:"false"`
+	Retry        MQTTRetry `koanf:"retry" json:"retry,omitempty" doc:"MQTT connection retry settings"`
+	Failover     bool      `koanf:"failover" json:"failover,omitempty" enum:"true,false" doc:"Poll Frigate API for events while MQTT is unavailable" default:"false"`
+}
+
+type MQTTRetry struct {
+	Attempts    int `koanf:"attempts" json:"attempts,omitempty" doc:"Maximum attempts to connect to MQTT. Set to 0 to retry forever" minimum:"0" default:"0"`
+	Interval    int `koanf:"interval" json:"interval,omitempty" doc:"Seconds to wait before first retry, doubled after each failed attempt" minimum:"1" default:"10"`
+	MaxInterval int `koanf:"max_interval" json:"max_interval,omitempty" doc:"Maximum seconds to wait between connection attempts" minimum:"1" default:"300"`
+}
+
+type MQTTTLS struct {
+	CA       string `koanf:"ca" json:"ca,omitempty" example:"/certs/ca.crt" doc:"Path to CA certificate used to verify MQTT server" default:""`
+	Cert     string `koanf:"cert" json:"cert,omitempty" example:"/certs/frigate-notify.crt" doc:"Path to client certificate file, for mutual TLS" default:""`
+	Key      string `koanf:"key" json:"key,omitempty" example:"/certs/frigate-notify.key" doc:"Path to client private key file, for mutual TLS" default:""`
+	Insecure bool   `koanf:"ignoressl" json:"ignoressl,omitempty" enum:"true,false" doc:"Ignore TLS/SSL errors" default:"false"`
 }
 
 type Cameras struct {
@@ -65,6 +138,7 @@ type Alerts struct {
 	Labels       Labels       `koanf:"labels" json:"labels,omitempty" doc:"Allow/Block labels from alerting"`
 	SubLabels    Labels       `koanf:"sublabels" json:"sublabels,omitempty" doc:"Allow/Block sublabels from alerting"`
 	LicensePlate LicensePlate `koanf:"license_plate" json:"license_plate,omitempty" doc:"License plate recognition settings"`
+	Cooldown     Cooldown     `koanf:"cooldown" json:"cooldown,omitempty" doc:"Suppress repeat notifications for the same camera & label"`
 	AppriseAPI   []AppriseAPI `koanf:"apprise_api" json:"apprise_api,omitempty" doc:"Apprise API notification settings"`
 	Discord      []Discord    `koanf:"discord" json:"discord,omitempty" doc:"Discord notification settings"`
 	Gotify       []Gotify     `koanf:"gotify" json:"gotify,omitempty" doc:"Gotify notification settings"`
@@ -76,8 +150,12 @@ type Alerts struct {
 	SMTP         []SMTP       `koanf:"smtp" json:"smtp,omitempty" doc:"SMTP notification settings"`
 	Telegram     []Telegram   `koanf:"telegram" json:"telegram,omitempty" doc:"Telegram notification settings"`
 	Webhook      []Webhook    `koanf:"webhook" json:"webhook,omitempty" doc:"Webhook notification settings"`
+	Custom       CustomAlerts `koanf:"custom" json:"custom,omitempty" secret:"true" doc:"Settings for notification providers that are not built-in, by provider name"`
 }
 
+// CustomAlerts holds profiles for each notification provider that is not built-in, by provider name
+type CustomAlerts map[string][]map[string]interface{}
+
 type General struct {
 	Title            string `koanf:"title" json:"title,omitempty" doc:"Notification title" default:"Frigate Alert"`
 	TimeFormat       string `koanf:"timeformat" json:"timeformat,omitempty" doc:"Time format used in notifications" default:""`
@@ -91,6 +169,8 @@ type General struct {
 	NotifyDetections bool   `koanf:"notify_detections,omitempty" json:"notify_detections" enum:"true,false" doc:"Enable notifications on detection (For app mode: reviews)" default:"false"`
 	RecheckDelay     int    `koanf:"recheck_delay" json:"recheck_delay,omitempty" default:"0" doc:"Delay before re-checking event details from Frigate"`
 	AudioOnly        string `koanf:"audio_only" json:"audio_only,omitempty" enum:"allow,drop" doc:"Allow/Drop events that only contain audio detections" default:"allow"`
+	UpdateMessages   bool   `koanf:"update_messages" json:"update_messages,omitempty" enum:"true,false" doc:"Edit previously sent notifications when event details change (Discord, Matrix, Telegram)" default:"false"`
+	NotifyEnd        bool   `koanf:"notify_end" json:"notify_end,omitempty" enum:"true,false" doc:"Send follow-up notification with clip when an event or review ends" default:"false"`
 }
 
 type LicensePlate struct {
@@ -116,6 +196,12 @@ type Labels struct {
 	Block    []string `koanf:"block" json:"block,omitempty" doc:"List of labels to always block"`
 }
 
+type Cooldown struct {
+	Duration int            `koanf:"duration" json:"duration,omitempty" minimum:"0" doc:"Seconds to suppress repeat notifications for the same camera & label" default:"0"`
+	Cameras  map[string]int `koanf:"cameras" json:"cameras,omitempty" doc:"Override cooldown duration for specific cameras"`
+	Labels   map[string]int `koanf:"labels" json:"labels,omitempty" doc:"Override cooldown duration for specific labels"`
+}
+
 type AlertFilter struct {
 	Cameras   []string `koanf:"cameras" json:"cameras,omitempty" doc:"List of cameras that will use this alert provider"`
 	Zones     []string `koanf:"zones" json:"zones,omitempty" doc:"List of zones that will use this alert provider"`
@@ -126,30 +212,49 @@ type AlertFilter struct {
 
 type AlertCommon struct {
 	Enabled  bool        `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Enable notifications via this provider" default:"false"`
+	Name     string      `koanf:"name" json:"name,omitempty" doc:"Name of this provider profile, used to reference it as a fallback"`
 	Title    string      `koanf:"title" json:"title,omitempty" doc:"Title for alerts from this provider"`
 	Template string      `koanf:"template" json:"template,omitempty" doc:"Custom message template" default:""`
 	Filters  AlertFilter `koanf:"filters" json:"filters,omitempty" doc:"Filter notifications sent via this provider"`
+	Cooldown int         `koanf:"cooldown" json:"cooldown,omitempty" minimum:"0" doc:"Seconds to suppress repeat notifications via this provider for the same camera & label" default:"0"`
+	Digest   Digest      `koanf:"digest" json:"digest,omitempty" doc:"Send a periodic summary via this provider instead of individual notifications"`
+	Fallback Fallback    `koanf:"fallback" json:"fallback,omitempty" doc:"Send notifications via another provider profile if this provider fails"`
+}
+
+// Common returns alert settings shared by all notification providers
+func (a *AlertCommon) Common() *AlertCommon {
+	return a
+}
+
+type Fallback struct {
+	Provider string `koanf:"provider" json:"provider,omitempty" example:"smtp" doc:"Type of notification provider to use if this provider fails"`
+	Profile  string `koanf:"profile" json:"profile,omitempty" doc:"Name of fallback provider profile. If not set, the first profile of this provider type is used"`
+}
+
+type Digest struct {
+	Enabled  bool `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Collect notifications & send a periodic summary" default:"false"`
+	Interval int  `koanf:"interval" json:"interval,omitempty" minimum:"60" doc:"Seconds to collect notifications before sending a summary" default:"3600"`
 }
 
 type AppriseAPI struct {
 	AlertCommon `koanf:",squash"`
 	Server      string   `koanf:"server" json:"server,omitempty" doc:"Apprise API URL to send alerts" default:""`
-	Token       string   `koanf:"token" json:"token,omitempty" doc:"Apprise API config token"`
+	Token       string   `koanf:"token" json:"token,omitempty" secret:"true" doc:"Apprise API config token"`
 	Tags        []string `koanf:"tags" json:"tags,omitempty" doc:"Notification group tags to receive alert"`
-	URLs        []string `koanf:"urls" json:"urls,omitempty" doc:"Apprise notification URLs to send alerts to"`
+	URLs        []string `koanf:"urls" json:"urls,omitempty" secret:"true" doc:"Apprise notification URLs to send alerts to"`
 	Insecure    bool     `koanf:"ignoressl" json:"ignoressl,omitempty" doc:"Ignore TLS/SSL errors" default:"false"`
 }
 
 type Discord struct {
 	AlertCommon  `koanf:",squash"`
-	Webhook      string `koanf:"webhook" json:"webhook,omitempty" doc:"Discord webhook URL to send alerts" default:""`
+	Webhook      string `koanf:"webhook" json:"webhook,omitempty" secret:"true" doc:"Discord webhook URL to send alerts" default:""`
 	DisableEmbed bool   `koanf:"disable_embed" json:"disable_embed,omitempty" doc:"Disable sending notification as Discord embedded message"`
 }
 
 type Gotify struct {
 	AlertCommon `koanf:",squash"`
-	Server      string `koanf:"server" json:"server,omitempty" doc:"Gotify server URL" default:""`
-	Token       string `koanf:"token" json:"token,omitempty" doc:"Gotify app token" default:""`
+	Server      string `koanf:"server" json:"server,omitempty" secret:"true" doc:"Gotify server URL" default:""`
+	Token       string `koanf:"token" json:"token,omitempty" secret:"true" doc:"Gotify app token" default:""`
 	Insecure    bool   `koanf:"ignoressl" json:"ignoressl,omitempty" doc:"Ignore TLS/SSL errors" default:"false"`
 	Priority    int    `koanf:"priority" json:"priority,omitempty" minimum:"0" maximum:"10" doc:"Gotify message priority" default:"0"`
 }
@@ -158,33 +263,33 @@ type Matrix struct {
 	AlertCommon `koanf:",squash"`
 	Server      string `koanf:"server" json:"server,omitempty" doc:"Matrix Homeserver address" default:""`
 	Username    string `koanf:"username" json:"username,omitempty" doc:"Matrix username" default:""`
-	Password    string `koanf:"password" json:"password,omitempty" doc:"Matrix password" default:""`
+	Password    string `koanf:"password" json:"password,omitempty" secret:"true" doc:"Matrix password" default:""`
 	RoomID      string `koanf:"roomid" json:"roomid,omitempty" doc:"Room ID to send notifications to" default:""`
 	Insecure    bool   `koanf:"ignoressl" json:"ignoressl,omitempty" doc:"Ignore TLS/SSL errors" default:"false"`
 }
 
 type Mattermost struct {
 	AlertCommon `koanf:",squash"`
-	Webhook     string              `koanf:"webhook" json:"webhook,omitempty" doc:"Mattermost webhook URL" default:""`
+	Webhook     string              `koanf:"webhook" json:"webhook,omitempty" secret:"true" doc:"Mattermost webhook URL" default:""`
 	Channel     string              `koanf:"channel" json:"channel,omitempty" doc:"Mattermost channel" default:""`
 	Priority    string              `koanf:"priority" json:"priority,omitempty" enum:"standard,important,urgent" doc:"Mattermost message priority" default:"standard"`
 	Username    string              `koanf:"username" json:"username,omitempty" doc:"Override Mattermost username for messages"`
 	Insecure    bool                `koanf:"ignoressl" json:"ignoressl,omitempty" doc:"Ignore TLS/SSL errors" default:"false"`
-	Headers     []map[string]string `koanf:"headers" json:"headers,omitempty" doc:"HTTP headers to include with Mattermost notifications" `
+	Headers     []map[string]string `koanf:"headers" json:"headers,omitempty" secret:"true" doc:"HTTP headers to include with Mattermost notifications" `
 }
 
 type Ntfy struct {
 	AlertCommon `koanf:",squash"`
-	Server      string              `koanf:"server" json:"server,omitempty" doc:"Ntfy Server address" default:""`
+	Server      string              `koanf:"server" json:"server,omitempty" secret:"true" doc:"Ntfy Server address" default:""`
 	Topic       string              `koanf:"topic" json:"topic,omitempty" doc:"Ntfy topic" default:""`
 	Insecure    bool                `koanf:"ignoressl" json:"ignoressl,omitempty" doc:"Ignore TLS/SSL errors" default:"false"`
-	Headers     []map[string]string `koanf:"headers" json:"headers,omitempty" doc:"HTTP headers to include with Ntfy notifications"`
+	Headers     []map[string]string `koanf:"headers" json:"headers,omitempty" secret:"true" doc:"HTTP headers to include with Ntfy notifications"`
 }
 
 type Pushover struct {
 	AlertCommon `koanf:",squash"`
-	Token       string `koanf:"token" json:"token,omitempty" doc:"Pushover app token" default:""`
-	Userkey     string `koanf:"userkey" json:"userkey,omitempty" doc:"Pushover user key" default:""`
+	Token       string `koanf:"token" json:"token,omitempty" secret:"true" doc:"Pushover app token" default:""`
+	Userkey     string `koanf:"userkey" json:"userkey,omitempty" secret:"true" doc:"Pushover user key" default:""`
 	Devices     string `koanf:"devices" json:"devices,omitempty" doc:"Pushover devices to target for notification" default:""`
 	Sound       string `koanf:"sound" json:"sound,omitempty" doc:"Pushover notification sound" default:"pushover"`
 	Priority    int    `koanf:"priority" json:"priority,omitempty" minimum:"-2" maximum:"2" doc:"Pushover message priority" default:"0"`
@@ -208,7 +313,7 @@ type SMTP struct {
 	TLS         bool   `koanf:"tls" json:"tls,omitempty" enum:"true,false" doc:"Enable/Disable TLS connection" default:"false"`
 	AuthType    string `koanf:"authtype" json:"authtype,omitempty" enum:"plain,plain-noenc,login,login-noenc,noauth,cram-md5,xoauth2,scram-sha-1,scram-sha-1-plus,scram-sha-256,scram-sha-256-plus,autodiscover" doc:"SMTP Authentication type" default:"plain"`
 	User        string `koanf:"user" json:"user,omitempty" doc:"SMTP user for authentication" default:""`
-	Password    string `koanf:"password" json:"password,omitempty" doc:"SMTP password for authentication" default:""`
+	Password    string `koanf:"password" json:"password,omitempty" secret:"true" doc:"SMTP password for authentication" default:""`
 	From        string `koanf:"from" json:"from,omitempty" format:"email" doc:"SMTP sender" default:""`
 	Recipient   string `koanf:"recipient" json:"recipient,omitempty" format:"email" doc:"SMTP recipient" default:""`
 	Thread      string `koanf:"thread" json:"thread" enum:"day,camera" doc:"Specify method of email threading" default:"day"`
@@ -219,23 +324,23 @@ type Telegram struct {
 	AlertCommon     `koanf:",squash"`
 	ChatID          int64  `koanf:"chatid" json:"chatid,omitempty" doc:"Telegram chat ID" default:"0"`
 	MessageThreadID int    `koanf:"message_thread_id" json:"message_thread_id,omitempty" doc:"Send message to thread by ID" default:"0"`
-	Token           string `koanf:"token" json:"token,omitempty" doc:"Telegram bot token" default:""`
+	Token           string `koanf:"token" json:"token,omitempty" secret:"true" doc:"Telegram bot token" default:""`
 	SendClip        bool   `koanf:"send_clip" json:"send_clip,omitempty" doc:"Send event video clip instead of snapshot image" default:"false"`
 }
 
 type Webhook struct {
 	AlertCommon `koanf:",squash"`
-	Server      string              `koanf:"server" json:"server,omitempty" doc:"Webhook address" default:""`
+	Server      string              `koanf:"server" json:"server,omitempty" secret:"true" doc:"Webhook address" default:""`
 	Insecure    bool                `koanf:"ignoressl" json:"insecure,omitempty" doc:"Ignore TLS/SSL errors" default:"false"`
 	Method      string              `koanf:"method" json:"method,omitempty" enum:"GET,POST" doc:"HTTP method for webhook notifications" default:"POST"`
-	Params      []map[string]string `fix:"params" json:"params,omitempty" doc:"URL parameters for webhook notifications"`
-	Headers     []map[string]string `koanf:"headers" json:"headers,omitempty" doc:"HTTP headers for webhook notifications"`
+	Params      []map[string]string `fix:"params" json:"params,omitempty" secret:"true" doc:"URL parameters for webhook notifications"`
+	Headers     []map[string]string `koanf:"headers" json:"headers,omitempty" secret:"true" doc:"HTTP headers for webhook notifications"`
 	Template    interface{}         `koanf:"template" json:"template,omitempty" doc:"Custom message template"`
 }
 
 type Monitor struct {
 	Enabled  bool   `koanf:"enabled" json:"enabled" enum:"true,false" doc:"Enable monitoring via external uptime application" default:"false"`
-	URL      string `koanf:"url" json:"url,omitempty" doc:"Address of monitoring server" default:""`
+	URL      string `koanf:"url" json:"url,omitempty" secret:"true" doc:"Address of monitoring server" default:""`
 	Interval int    `koanf:"interval" json:"interval,omitempty" minimum:"1" maximum:"10000000" doc:"Interval between check-in messages" default:"60"`
 	Insecure bool   `koanf:"ignoressl" json:"ignoressl,omitempty" enum:"true,false" doc:"Ignore TLS/SSL errors" default:"false"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // MQTTEvent stores incoming MQTT payloads from Frigate
 type MQTTEvent struct {
 	Before struct {
@@ -63,6 +65,37 @@ type ExtraFields struct {
 	FrigateMajorVersion int
 	EventLink           string
 	ReviewLink          string
+	ReviewID            string
 	CameraName          string
 	Audio               string
+	Digest              *DigestSummary
+	Ended               bool
+	Duration            string
+	HistoryID           string
+	Test                bool
+	Manual              *ManualAlert
+}
+
+// Title & message for an alert sent via API, rather than generated from a Frigate event
+type ManualAlert struct {
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+}
+
+// Summary of alerts collected for a digest notification
+type DigestSummary struct {
+	Start          time.Time     `json:"start"`
+	End            time.Time     `json:"end"`
+	FormattedStart string        `json:"formatted_start"`
+	FormattedEnd   string        `json:"formatted_end"`
+	Total          int           `json:"total"`
+	Counts         []DigestCount `json:"counts"`
+}
+
+// Number of alerts for a single camera & label within a digest
+type DigestCount struct {
+	Camera     string `json:"camera"`
+	CameraName string `json:"camera_name"`
+	Label      string `json:"label"`
+	Count      int    `json:"count"`
 }
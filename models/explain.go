@@ -0,0 +1,40 @@
+package models
+
+// FilterStep is the result of a single filter check
+type FilterStep struct {
+	Filter string `json:"filter" example:"quiet_hours" doc:"Name of filter"`
+	Passed bool   `json:"passed" doc:"Whether the filter allowed the notification"`
+	Reason string `json:"reason,omitempty" example:"Quiet hours" doc:"Reason the notification would be dropped"`
+}
+
+// ProviderExplanation is the result of each filter for a single notification provider profile
+type ProviderExplanation struct {
+	Provider  string       `json:"provider" example:"discord" doc:"Notification provider"`
+	ProfileID int          `json:"provider_id" example:"0" doc:"Notification provider profile ID"`
+	Name      string       `json:"name,omitempty" doc:"Notification provider profile name"`
+	Enabled   bool         `json:"enabled" doc:"Whether notification provider profile is enabled"`
+	Notify    bool         `json:"notify" doc:"Whether this profile would send a notification"`
+	Steps     []FilterStep `json:"steps" doc:"Result of each provider filter"`
+}
+
+// EventExplanation is the result of each app-level filter for a single event
+type EventExplanation struct {
+	EventID      string       `json:"event_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event ID"`
+	Camera       string       `json:"camera" example:"front_door" doc:"Camera that triggered the event"`
+	Label        string       `json:"label,omitempty" example:"person" doc:"Detected object label"`
+	SubLabel     string       `json:"sub_label,omitempty" doc:"Detected object sublabel"`
+	LicensePlate string       `json:"license_plate,omitempty" doc:"Recognized license plate, after waiting for license plate recognition if enabled"`
+	Score        float64      `json:"score" example:"0.85" doc:"Top score of detected object"`
+	Zones        []string     `json:"zones,omitempty" example:"[\"driveway\"]" doc:"Zones the object was in"`
+	Steps        []FilterStep `json:"steps" doc:"Result of each app-level filter"`
+}
+
+// Explanation describes whether a Frigate event or review would generate a notification & why
+type Explanation struct {
+	ID        string                `json:"id" example:"1700000000.123456-abcdef" doc:"Frigate event or review ID"`
+	Type      string                `json:"type" enum:"event,review" doc:"Whether ID is an event or review"`
+	Notify    bool                  `json:"notify" doc:"Whether a notification would be sent via at least one provider"`
+	Steps     []FilterStep          `json:"steps,omitempty" doc:"Result of review-level checks"`
+	Events    []EventExplanation    `json:"events" doc:"Result of app-level filters for each event"`
+	Providers []ProviderExplanation `json:"providers" doc:"Result of filters for each notification provider profile"`
+}
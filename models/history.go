@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// HistoryItem records how a single processed event or review was handled
+type HistoryItem struct {
+	ID        string            `json:"id" example:"0000000000000001" doc:"History item ID"`
+	Time      time.Time         `json:"time" doc:"Time event was processed"`
+	EventID   string            `json:"event_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event ID"`
+	ReviewID  string            `json:"review_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate review ID"`
+	Camera    string            `json:"camera" example:"front_door" doc:"Camera that triggered the event"`
+	Labels    []string          `json:"labels,omitempty" example:"[\"person\"]" doc:"Detected object labels"`
+	Zones     []string          `json:"zones,omitempty" example:"[\"driveway\"]" doc:"Zones the object was in"`
+	Outcome   string            `json:"outcome" enum:"filtered,pending,sent,partial,failed" doc:"Overall result"`
+	Reason    string            `json:"reason,omitempty" example:"Quiet hours" doc:"Filter that dropped the event, if filtered"`
+	Providers []HistoryProvider `json:"providers,omitempty" doc:"Result for each notification provider profile"`
+}
+
+// HistoryProvider records the result of sending an alert via a single notification provider profile
+type HistoryProvider struct {
+	Provider  string    `json:"provider" example:"discord" doc:"Notification provider"`
+	ProfileID int       `json:"provider_id" example:"0" doc:"Notification provider profile ID"`
+	Status    string    `json:"status" enum:"filtered,cooldown,digest,pending,sent,failed,dry_run" doc:"Delivery result"`
+	Detail    string    `json:"detail,omitempty" doc:"Filter reason or error message"`
+	Time      time.Time `json:"time" doc:"Time of last status change"`
+}
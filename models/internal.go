@@ -1,12 +1,16 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
 
 // Store internal-use only info
 type InternalConfig struct {
-	AppVersion     string
-	FrigateVersion int
-	Status         Status
+	AppVersion string
 }
 
 type Status struct {
@@ -16,27 +20,66 @@ type Status struct {
 	LastEvent        time.Time         `json:"last_event" example:"0001-01-01T00:00:00Z" doc:"Timestamp of last received event from Frigate"`
 	LastNotification time.Time         `json:"last_notification" example:"0001-01-01T00:00:00Z" doc:"Timestamp of last sent notification"`
 	Notifications    Notifiers         `json:"notifications" doc:"Status of notification providers"`
+	NotifTimer       *NotifTimer       `json:"notif_timer,omitempty" doc:"Pending revert of notification state, if changed for a limited time"`
 	Monitor          string            `json:"monitor" example:"ok" doc:"Health of reporting state to external health monitor app"`
 }
 
+type NotifTimer struct {
+	Until            time.Time `json:"until" doc:"Time notification state will revert"`
+	RemainingSeconds int64     `json:"remaining_seconds" example:"3600" doc:"Seconds until notification state will revert"`
+}
+
 type FrigateConnection struct {
 	API  string `json:"api" example:"ok" doc:"Health of connection to Frigate via API"`
 	MQTT string `json:"mqtt" example:"ok" doc:"Health of connection to Frigate via MQTT"`
 }
 
 type Notifiers struct {
-	Enabled    bool             `json:"enabled" example:"true" doc:"State of whether Frigate-Notify is enabled for notifications"`
-	AppriseAPI []NotifierStatus `json:"apprise_api" doc:"Status of Apprise API notifications"`
-	Discord    []NotifierStatus `json:"discord" doc:"Status of Discord notifications"`
-	Gotify     []NotifierStatus `json:"gotify" doc:"Status of Gotify notifications"`
-	Matrix     []NotifierStatus `json:"matrix" doc:"Status of Matrix notifications"`
-	Mattermost []NotifierStatus `json:"mattermost" doc:"Status of Mattermost notifications"`
-	Ntfy       []NotifierStatus `json:"ntfy" doc:"Status of Ntfy notifications"`
-	Pushover   []NotifierStatus `json:"pushover" doc:"Status of Pushover notifications"`
-	Signal     []NotifierStatus `json:"signal" doc:"Status of Signal notifications"`
-	SMTP       []NotifierStatus `json:"smtp" doc:"Status of SMTP notifications"`
-	Telegram   []NotifierStatus `json:"telegram" doc:"Status of Telegram notifications"`
-	Webhook    []NotifierStatus `json:"webhook" doc:"Status of Webhook notifications"`
+	Enabled   bool                        `json:"enabled" example:"true" doc:"State of whether Frigate-Notify is enabled for notifications"`
+	Until     time.Time                   `json:"-"`
+	Providers map[string][]NotifierStatus `json:"-"`
+}
+
+// Remaining returns time left until notification state reverts, or zero if not scheduled
+func (n *Notifiers) Remaining() time.Duration {
+	if n.Until.IsZero() {
+		return 0
+	}
+	return max(time.Until(n.Until), 0)
+}
+
+// Get returns status of a single notification provider profile, or nil if it does not exist
+func (n *Notifiers) Get(provider string, id int) *NotifierStatus {
+	status, ok := n.Providers[provider]
+	if !ok || id < 0 || id >= len(status) {
+		return nil
+	}
+	return &status[id]
+}
+
+// MarshalJSON flattens provider status alongside notification state, ex: {"enabled": true, "discord": [...]}
+func (n Notifiers) MarshalJSON() ([]byte, error) {
+	flat := make(map[string]interface{}, len(n.Providers)+1)
+	for provider, status := range n.Providers {
+		flat[provider] = status
+	}
+	flat["enabled"] = n.Enabled
+	return json.Marshal(flat)
+}
+
+// Schema describes the flattened provider status for API docs
+func (n Notifiers) Schema(r huma.Registry) *huma.Schema {
+	return &huma.Schema{
+		Type: huma.TypeObject,
+		Properties: map[string]*huma.Schema{
+			"enabled": {Type: huma.TypeBoolean, Description: "State of whether Frigate-Notify is enabled for notifications", Examples: []any{true}},
+		},
+		AdditionalProperties: &huma.Schema{
+			Type:        huma.TypeArray,
+			Description: "Status of notification provider profiles",
+			Items:       r.Schema(reflect.TypeOf(NotifierStatus{}), true, "NotifierStatus"),
+		},
+	}
 }
 
 type NotifierStatus struct {
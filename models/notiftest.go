@@ -0,0 +1,24 @@
+package models
+
+// NotifTestTarget selects notification provider profiles to send a test notification to
+type NotifTestTarget struct {
+	Provider  string `json:"provider" example:"discord" doc:"Notification provider" minLength:"1"`
+	ProfileID *int   `json:"provider_id,omitempty" example:"0" doc:"Notification provider profile ID" minimum:"0"`
+	Name      string `json:"name,omitempty" doc:"Notification provider profile name. If neither ID or name are set, all profiles for this provider are used"`
+}
+
+// NotifTestSynthetic describes a generated event used for test notifications, so no Frigate event is needed
+type NotifTestSynthetic struct {
+	Camera string   `json:"camera,omitempty" example:"front_door" doc:"Camera name" default:"test_camera"`
+	Label  string   `json:"label,omitempty" example:"person" doc:"Detected object label" default:"person"`
+	Zones  []string `json:"zones,omitempty" example:"[\"driveway\"]" doc:"Zones the object was in"`
+}
+
+// NotifTestResult is the result of sending a test notification via a single provider profile
+type NotifTestResult struct {
+	Provider  string `json:"provider" example:"discord" doc:"Notification provider"`
+	ProfileID int    `json:"provider_id" example:"0" doc:"Notification provider profile ID"`
+	Name      string `json:"name,omitempty" doc:"Notification provider profile name"`
+	Status    string `json:"status" enum:"sent,failed,dry_run" doc:"Result of sending test notification"`
+	Error     string `json:"error,omitempty" doc:"Error returned by notification provider, if sending failed"`
+}
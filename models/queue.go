@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// QueueItem tracks delivery of a single alert to a single notification provider profile
+type QueueItem struct {
+	ID          string    `json:"id" example:"0000000000000001" doc:"Queue item ID"`
+	Provider    string    `json:"provider" example:"discord" doc:"Notification provider"`
+	ProfileID   int       `json:"provider_id" example:"0" doc:"Notification provider profile ID"`
+	EventID     string    `json:"event_id" example:"1700000000.123456-abcdef" doc:"Frigate event ID"`
+	Camera      string    `json:"camera" example:"front_door" doc:"Camera that triggered the event"`
+	Label       string    `json:"label" example:"person" doc:"Detected object label"`
+	Attempts    int       `json:"attempts" example:"1" doc:"Number of delivery attempts made"`
+	Created     time.Time `json:"created" doc:"Time item was added to the queue"`
+	NextAttempt time.Time `json:"next_attempt" doc:"Time of next delivery attempt"`
+	LastError   string    `json:"last_error,omitempty" doc:"Error from most recent delivery attempt"`
+	FallbackFor string    `json:"fallback_for,omitempty" example:"signal/0" doc:"Provider profile that failed to send this alert, if this is a fallback"`
+}
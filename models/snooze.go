@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Snooze temporarily stops notifications for a camera, label, or notification provider profile
+type Snooze struct {
+	ID        string    `json:"id" example:"0000000000000001" doc:"Snooze ID"`
+	Camera    string    `json:"camera,omitempty" example:"driveway" doc:"Camera to snooze"`
+	Label     string    `json:"label,omitempty" example:"person" doc:"Label to snooze"`
+	Provider  string    `json:"provider,omitempty" example:"discord" doc:"Notification provider to snooze"`
+	ProfileID *int      `json:"provider_id,omitempty" example:"0" doc:"Notification provider profile ID to snooze. If not set, all profiles for provider are snoozed"`
+	Note      string    `json:"note,omitempty" example:"Landscaper" doc:"Reason for snooze"`
+	Created   time.Time `json:"created" doc:"Time snooze was created"`
+	Until     time.Time `json:"until" doc:"Time snooze expires"`
+}
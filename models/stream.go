@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// StreamReceived is sent to live stream clients when a new event or review is received from Frigate
+type StreamReceived struct {
+	Time     time.Time `json:"time" doc:"Time event was received"`
+	EventID  string    `json:"event_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event ID"`
+	ReviewID string    `json:"review_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate review ID"`
+	Camera   string    `json:"camera" example:"front_door" doc:"Camera that triggered the event"`
+	Labels   []string  `json:"labels,omitempty" example:"[\"person\"]" doc:"Detected object labels"`
+	Zones    []string  `json:"zones,omitempty" example:"[\"driveway\"]" doc:"Zones the object was in"`
+	Source   string    `json:"source" enum:"mqtt,webapi" doc:"How the event was received from Frigate"`
+}
+
+// StreamFiltered is sent to live stream clients when an event or review is dropped by a filter
+type StreamFiltered struct {
+	Time     time.Time `json:"time" doc:"Time event was dropped"`
+	EventID  string    `json:"event_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event ID"`
+	ReviewID string    `json:"review_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate review ID"`
+	Camera   string    `json:"camera" example:"front_door" doc:"Camera that triggered the event"`
+	Labels   []string  `json:"labels,omitempty" example:"[\"person\"]" doc:"Detected object labels"`
+	Reason   string    `json:"reason" example:"Quiet hours" doc:"Filter that dropped the event"`
+}
+
+// StreamDelivery is sent to live stream clients when the result of sending an alert via a notification provider profile changes
+type StreamDelivery struct {
+	Time      time.Time `json:"time" doc:"Time of status change"`
+	EventID   string    `json:"event_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate event ID"`
+	ReviewID  string    `json:"review_id,omitempty" example:"1700000000.123456-abcdef" doc:"Frigate review ID"`
+	HistoryID string    `json:"history_id,omitempty" example:"0000000000000001" doc:"Notification history item ID, if history is enabled"`
+	Camera    string    `json:"camera" example:"front_door" doc:"Camera that triggered the event"`
+	Provider  string    `json:"provider" example:"discord" doc:"Notification provider"`
+	ProfileID int       `json:"provider_id" example:"0" doc:"Notification provider profile ID"`
+	Status    string    `json:"status" enum:"filtered,cooldown,digest,pending,sent,failed,dry_run" doc:"Delivery result"`
+	Detail    string    `json:"detail,omitempty" doc:"Filter reason or error message"`
+}
+
+// StreamKeepalive is sent to live stream clients periodically, so idle connections are not closed by proxies
+type StreamKeepalive struct {
+	Time time.Time `json:"time" doc:"Current time"`
+}
@@ -2,12 +2,15 @@ package notifier
 
 import (
 	"bytes"
+	"context"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -17,7 +20,10 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/metrics"
 	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/stream"
 	"github.com/0x2142/frigate-notify/util"
 )
 
@@ -26,151 +32,217 @@ var TemplateFiles embed.FS
 type notifMeta struct {
 	name  string
 	index int
+	// Stable identity of the provider profile, see config.ProfileKey. Only set where provider filters are checked
+	key string
+	// Provider profile that failed to send this alert, ex: "signal/0", if sending via fallback
+	fallbackFor string
+}
+
+// currentProfile returns settings for a single provider profile from the running config, or an error if it was removed by a config reload
+func currentProfile(n config.Notifier, index int) (config.Profile, error) {
+	profiles := n.Profiles(config.Current())
+	if index < 0 || index >= len(profiles) {
+		return nil, fmt.Errorf("notification provider profile %v is no longer configured", index)
+	}
+	return profiles[index], nil
 }
 
 // SendAlert forwards alert information to all enabled alerting methods
 func SendAlert(events []models.Event) {
-	config.Internal.Status.LastNotification = time.Now()
-
-	// Collect snapshot, if available
-	var snapshot io.Reader
-	for _, event := range events {
-		if event.HasSnapshot {
-			snapshot = GetSnapshot(event)
-			break
-		}
-	}
+	config.State.SetLastNotification(time.Now())
 
 	// Set extra event details & get event used for notifications
 	event := setExtras(events)
 
-	// Create copy of snapshot for each alerting method
-	var snap []byte
-	if snapshot != nil {
-		snap, _ = io.ReadAll(snapshot)
-	} else {
+	// Collect snapshot, if available
+	snap := collectSnapshot(appContext(), events)
+	if snap == nil {
 		event.HasSnapshot = false
 	}
 
-	// Send Alerts
-	// Apprise API
-	for id, profile := range config.ConfigData.Alerts.AppriseAPI {
-		if profile.Enabled {
-			provider := notifMeta{name: "apprise_api", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendAppriseAPI(event, bytes.NewReader(snap), provider)
-			}
-		}
+	// Save alert details, so later updates to this event can be detected
+	if trackingEnabled() && alertKey(event) != "" {
+		recordContent(alertKey(event), alertContent(event))
 	}
-	// Discord
-	for id, profile := range config.ConfigData.Alerts.Discord {
-		if profile.Enabled {
-			provider := notifMeta{name: "discord", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendDiscordMessage(event, bytes.NewReader(snap), provider)
+
+	sendToProviders(events, event, snap)
+}
+
+// sendToProviders applies provider filters & sends alert to each enabled provider profile
+func sendToProviders(events []models.Event, event models.Event, snap []byte) string {
+	// Record result of sending to each provider in notification history
+	event.Extra.HistoryID = history.Record(events)
+
+	// Send Alerts
+	for _, n := range config.Notifiers() {
+		for id, p := range n.Profiles(config.Current()) {
+			if profile := p.Common(); profile.Enabled {
+				provider := notifMeta{name: n.Name(), index: id, key: config.ProfileKey(p)}
+				if ok, reason := checkAlertFilters(events, profile.Filters, provider); !ok {
+					setStatus(event, provider.name, provider.index, history.StatusFiltered, reason)
+					metrics.AlertsDropped.WithLabelValues(provider.name, strconv.Itoa(provider.index), reason).Inc()
+					continue
+				}
+				if profile.Digest.Enabled {
+					setStatus(event, provider.name, provider.index, history.StatusDigest, "")
+					addToDigest(event, events, snap, provider)
+				} else if providerInCooldown(events, profile.Cooldown, provider) {
+					setStatus(event, provider.name, provider.index, history.StatusCooldown, "")
+					metrics.AlertsDropped.WithLabelValues(provider.name, strconv.Itoa(provider.index), "Cooldown period").Inc()
+				} else {
+					setStatus(event, provider.name, provider.index, history.StatusPending, "")
+					dispatchAlert(n, event, snap, provider)
+				}
 			}
 		}
 	}
-	// Gotify
-	for id, profile := range config.ConfigData.Alerts.Gotify {
-		if profile.Enabled {
-			provider := notifMeta{name: "gotify", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendGotifyPush(event, provider)
+	return event.Extra.HistoryID
+}
+
+// setStatus records the result of sending an alert via a single provider profile in history & the live stream
+func setStatus(event models.Event, provider string, index int, status, detail string) {
+	history.SetProvider(event.Extra.HistoryID, provider, index, status, detail)
+	stream.Delivery(event, provider, index, status, detail)
+}
+
+// collectSnapshot downloads snapshot for first event that has one available
+func collectSnapshot(ctx context.Context, events []models.Event) []byte {
+	for _, event := range events {
+		if event.HasSnapshot {
+			snapshot := GetSnapshot(ctx, event)
+			if snapshot == nil {
+				return nil
 			}
+			snap, _ := io.ReadAll(snapshot)
+			return snap
 		}
 	}
-	// Matrix
-	for id, profile := range config.ConfigData.Alerts.Matrix {
-		if profile.Enabled {
-			provider := notifMeta{name: "matrix", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendMatrix(event, bytes.NewReader(snap), provider)
-			}
-		}
+	return nil
+}
+
+// dispatchAlert sends alert via the notification queue if enabled, otherwise directly
+func dispatchAlert(n config.Notifier, event models.Event, snapshot []byte, provider notifMeta) {
+	if queueEnabled() {
+		enqueueAlert(n, event, snapshot, provider)
+	} else {
+		deliverAlert(n, event, snapshot, provider)
 	}
-	// Mattermost
-	for id, profile := range config.ConfigData.Alerts.Mattermost {
-		if profile.Enabled {
-			provider := notifMeta{name: "mattermost", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendMattermost(event, provider)
-			}
+}
+
+// deliverAlert sends alert via the next available worker, without retries
+func deliverAlert(n config.Notifier, event models.Event, snapshot []byte, provider notifMeta) {
+	ok := submit(func(ctx context.Context) {
+		if err := sendAlert(ctx, n, event, snapshot, provider); err != nil && ctx.Err() == nil {
+			sendFallback(ctx, event, snapshot, provider)
 		}
+	})
+	if !ok {
+		log.Warn().
+			Str("event_id", event.ID).
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Msg("Alert dropped - Shutting down")
 	}
-	// Ntfy
-	for id, profile := range config.ConfigData.Alerts.Ntfy {
-		if profile.Enabled {
-			provider := notifMeta{name: "ntfy", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendNtfyPush(event, bytes.NewReader(snap), provider)
-			}
+}
+
+// sendAlert delivers alert via a single provider profile & records the result
+func sendAlert(ctx context.Context, n config.Notifier, event models.Event, snapshot []byte, provider notifMeta) error {
+	ctx = withProvider(ctx, provider)
+	profile, err := currentProfile(n, provider.index)
+	action := "sent"
+	start := time.Now()
+	editor, canEdit := n.(config.MessageEditor)
+	replier, canReply := n.(config.MessageReplier)
+	key := alertKey(event)
+	switch {
+	case err != nil:
+	case event.Extra.Ended:
+		// Reply to original alert if supported, otherwise send follow-up as a new message
+		action = "follow-up sent"
+		if messageID := getMessageID(key, provider); canReply && messageID != "" {
+			err = replier.ReplyMessage(ctx, event, bytes.NewReader(snapshot), profile, messageID)
+		} else {
+			err = n.Send(ctx, event, bytes.NewReader(snapshot), profile)
 		}
-	}
-	// Pushover
-	for id, profile := range config.ConfigData.Alerts.Pushover {
-		if profile.Enabled {
-			provider := notifMeta{name: "pushover", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendPushoverMessage(event, bytes.NewReader(snap), provider)
+	case canEdit && trackingEnabled() && key != "":
+		// Edit existing message if one was already sent for this event, otherwise send new message & save ID
+		sent := false
+		if messageID := getMessageID(key, provider); messageID != "" && updatesEnabled() {
+			err = editor.EditMessage(ctx, event, bytes.NewReader(snapshot), profile, messageID)
+			if err == nil {
+				sent = true
+				action = "updated"
+			} else {
+				log.Warn().
+					Str("event_id", event.ID).
+					Str("provider", provider.name).
+					Int("provider_id", provider.index).
+					Err(err).
+					Msg("Unable to update alert, sending new message")
 			}
 		}
-	}
-	// Signal
-	for id, profile := range config.ConfigData.Alerts.Signal {
-		if profile.Enabled {
-			provider := notifMeta{name: "signal", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendSignalMessage(event, bytes.NewReader(snap), provider)
+		if !sent {
+			var messageID string
+			messageID, err = editor.SendMessage(ctx, event, bytes.NewReader(snapshot), profile)
+			if err == nil {
+				saveMessageID(key, provider, messageID)
 			}
 		}
-	}
-	// SMTP
-	for id, profile := range config.ConfigData.Alerts.SMTP {
-		if profile.Enabled {
-			provider := notifMeta{name: "smtp", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendSMTP(event, bytes.NewReader(snap), provider)
-			}
+	default:
+		err = n.Send(ctx, event, bytes.NewReader(snapshot), profile)
+		if err == nil && trackingEnabled() && key != "" {
+			saveMessageID(key, provider, "")
 		}
 	}
-	// Telegram
-	for id, profile := range config.ConfigData.Alerts.Telegram {
-		if profile.Enabled {
-			provider := notifMeta{name: "telegram", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendTelegramMessage(event, bytes.NewReader(snap), provider)
-			}
-		}
+	metrics.NotificationDuration.WithLabelValues(provider.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.Notifications.WithLabelValues(provider.name, strconv.Itoa(provider.index), "failed").Inc()
+		log.Warn().
+			Str("event_id", event.ID).
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Err(err).
+			Msg("Unable to send alert")
+		config.State.NotifFailure(provider.name, provider.index, err.Error())
+		setStatus(event, provider.name, provider.index, history.StatusFailed, err.Error())
+		return err
 	}
-	// Webhook
-	for id, profile := range config.ConfigData.Alerts.Webhook {
-		if profile.Enabled {
-			provider := notifMeta{name: "webhook", index: id}
-			if checkAlertFilters(events, profile.Filters, provider) {
-				go SendWebhook(event, provider)
-			}
-		}
+
+	// Provider status is left unchanged, since nothing was actually sent
+	if config.IsDryRun() {
+		setStatus(event, provider.name, provider.index, history.StatusDryRun, "")
+		metrics.Notifications.WithLabelValues(provider.name, strconv.Itoa(provider.index), "dry_run").Inc()
+		return nil
 	}
+	metrics.Notifications.WithLabelValues(provider.name, strconv.Itoa(provider.index), "sent").Inc()
+	setStatus(event, provider.name, provider.index, history.StatusSent, "")
+
+	log.Info().
+		Str("event_id", event.ID).
+		Str("provider", provider.name).
+		Int("provider_id", provider.index).
+		Msg("Alert " + action)
+	config.State.NotifSuccess(provider.name, provider.index)
+	return nil
 }
 
 // GetSnapshot downloads a snapshot from Frigate
-func GetSnapshot(event models.Event) io.Reader {
+func GetSnapshot(ctx context.Context, event models.Event) io.Reader {
 	var snapurl *url.URL
-	if config.ConfigData.Alerts.General.SnapHiRes {
+	if config.Current().Alerts.General.SnapHiRes {
 		evtTime := fmt.Sprintf("%v", event.StartTime)
-		snapurl, _ = url.Parse(config.ConfigData.Frigate.Server + "/api/" + event.Camera + "/recordings/" + evtTime + "/snapshot.jpg")
+		snapurl, _ = url.Parse(config.Current().Frigate.Server + "/api/" + event.Camera + "/recordings/" + evtTime + "/snapshot.jpg")
 	} else {
 		// Add optional snapshot modifiers
-		snapurl, _ = url.Parse(config.ConfigData.Frigate.Server + "/api/events/" + event.ID + "/snapshot.jpg")
+		snapurl, _ = url.Parse(config.Current().Frigate.Server + "/api/events/" + event.ID + "/snapshot.jpg")
 		q := snapurl.Query()
-		if config.ConfigData.Alerts.General.SnapBbox {
+		if config.Current().Alerts.General.SnapBbox {
 			q.Add("bbox", "1")
 		}
-		if config.ConfigData.Alerts.General.SnapTimestamp {
+		if config.Current().Alerts.General.SnapTimestamp {
 			q.Add("timestamp", "1")
 		}
-		if config.ConfigData.Alerts.General.SnapCrop {
+		if config.Current().Alerts.General.SnapCrop {
 			q.Add("crop", "1")
 		}
 		snapurl.RawQuery = q.Encode()
@@ -178,15 +250,24 @@ func GetSnapshot(event models.Event) io.Reader {
 
 	var response []byte
 
+	start := time.Now()
+	defer func() {
+		metrics.SnapshotDuration.Observe(time.Since(start).Seconds())
+	}()
+
 	attempts := 0
-	max_attempts := config.ConfigData.Alerts.General.MaxSnapRetry
+	max_attempts := config.Current().Alerts.General.MaxSnapRetry
 	for attempts < max_attempts {
 		var err error
-		response, err = util.HTTPGet(snapurl.String(), config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
+		response, err = util.HTTPGet(ctx, snapurl.String(), config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
 		if err != nil {
 			attempts += 1
 			if err.Error() == "404" {
-				time.Sleep(2 * time.Second)
+				metrics.SnapshotRetries.Inc()
+				if !util.Wait(ctx, 2*time.Second) {
+					metrics.Snapshots.WithLabelValues("failed").Inc()
+					return nil
+				}
 				log.Info().
 					Str("event_id", event.ID).
 					Int("attempt", attempts).
@@ -198,6 +279,7 @@ func GetSnapshot(event models.Event) io.Reader {
 					Str("event_id", event.ID).
 					Err(err).
 					Msgf("Could not access snapshot")
+				metrics.Snapshots.WithLabelValues("failed").Inc()
 				return nil
 			}
 		} else {
@@ -205,25 +287,29 @@ func GetSnapshot(event models.Event) io.Reader {
 		}
 	}
 	if attempts == max_attempts {
+		metrics.Snapshots.WithLabelValues("failed").Inc()
 		return nil
 	}
+	metrics.Snapshots.WithLabelValues("success").Inc()
 	return bytes.NewReader(response)
 }
 
 // GetClip downloads a event video clip from Frigate
-func GetClip(event models.Event) io.Reader {
-	clipurl := config.ConfigData.Frigate.Server + "/api/events/" + event.ID + "/clip.mp4"
+func GetClip(ctx context.Context, event models.Event) io.Reader {
+	clipurl := config.Current().Frigate.Server + "/api/events/" + event.ID + "/clip.mp4"
 	var response []byte
 
 	attempts := 0
-	max_attempts := config.ConfigData.Alerts.General.MaxSnapRetry
+	max_attempts := config.Current().Alerts.General.MaxSnapRetry
 	for attempts < max_attempts {
 		var err error
-		response, err = util.HTTPGet(clipurl, config.ConfigData.Frigate.Insecure, "", config.ConfigData.Frigate.Headers...)
+		response, err = util.HTTPGet(ctx, clipurl, config.Current().Frigate.Insecure, "", config.Current().Frigate.Headers...)
 		if err != nil {
 			attempts += 1
 			if err.Error() == "404" {
-				time.Sleep(2 * time.Second)
+				if !util.Wait(ctx, 2*time.Second) {
+					return nil
+				}
 				log.Info().
 					Str("event_id", event.ID).
 					Int("attempt", attempts).
@@ -241,7 +327,9 @@ func GetClip(event models.Event) io.Reader {
 			// Frigate is probably still processing the clip
 			// Wait a bit and try again
 			attempts += 1
-			time.Sleep(2 * time.Second)
+			if !util.Wait(ctx, 2*time.Second) {
+				return nil
+			}
 			log.Info().
 				Str("event_id", event.ID).
 				Int("attempt", attempts).
@@ -266,18 +354,18 @@ func setExtras(events []models.Event) models.Event {
 	key := events[0]
 
 	// Set Event link
-	key.Extra.EventLink = config.ConfigData.Frigate.PublicURL + "/api/events/" + key.ID + "/clip.mp4"
+	key.Extra.EventLink = config.Current().Frigate.PublicURL + "/api/events/" + key.ID + "/clip.mp4"
 
 	// Add Frigate Major version metadata
-	key.Extra.FrigateMajorVersion = config.Internal.FrigateVersion
+	key.Extra.FrigateMajorVersion = config.State.FrigateVersion()
 
 	// Transform camera names, example: "test_camera" to "Test Camera"
 	caser := cases.Title(language.Und)
 	key.Extra.CameraName = caser.String(strings.ReplaceAll(key.Camera, "_", " "))
 
 	// Assign Frigate URL to extra event fields
-	key.Extra.LocalURL = config.ConfigData.Frigate.Server
-	key.Extra.PublicURL = config.ConfigData.Frigate.PublicURL
+	key.Extra.LocalURL = config.Current().Frigate.Server
+	key.Extra.PublicURL = config.Current().Frigate.PublicURL
 
 	// Create list of all detected objects / license plates (mostly applicable to /reviews)
 	var labelList []string
@@ -312,10 +400,7 @@ func setExtras(events []models.Event) models.Event {
 	// If certain time format is provided, re-format date / time string
 	eventTime := time.Unix(int64(key.StartTime), 0)
 	key.Extra.UnixStartTime = eventTime.Unix()
-	key.Extra.FormattedTime = eventTime.String()
-	if config.ConfigData.Alerts.General.TimeFormat != "" {
-		key.Extra.FormattedTime = eventTime.Format(config.ConfigData.Alerts.General.TimeFormat)
-	}
+	key.Extra.FormattedTime = formatTime(eventTime)
 
 	// Calc TopScore percentage
 	key.Extra.TopScorePercent = fmt.Sprintf("%v%%", int((key.TopScore * 100)))
@@ -328,34 +413,65 @@ func setExtras(events []models.Event) models.Event {
 
 // Build notification based on template
 func renderMessage(sourceTemplate string, event models.Event, mtype string, provider string) string {
-	// Render template
-	var tmpl *template.Template
-	var err error
-	if sourceTemplate == "markdown" || sourceTemplate == "plaintext" || sourceTemplate == "html" || sourceTemplate == "json" {
-		tmpl = template.Must(template.ParseFS(TemplateFiles, "templates/"+sourceTemplate+".template"))
-	} else {
-		tmpl, err = template.New("custom").Funcs(template.FuncMap{"env": includeenv}).Parse(sourceTemplate)
-		if err != nil {
-			log.Warn().Err(err).Msg("Failed to render event message")
+	// Manual alerts replace the title & built-in templates with text provided via API
+	if manual := event.Extra.Manual; manual != nil {
+		if mtype == "title" && manual.Title != "" {
+			return manual.Title
+		}
+		if mtype == "message" && isBuiltinTemplate(sourceTemplate) {
+			return manualMessage(manual.Message, sourceTemplate)
 		}
 	}
 
-	var renderedTemplate bytes.Buffer
-	err = tmpl.Execute(&renderedTemplate, event)
+	rendered, err := executeTemplate(sourceTemplate, event)
 	if err != nil {
-		log.Fatal().
+		log.Error().
 			Err(err).
-			Msgf("Failed to render event message")
+			Str("event_id", event.ID).
+			Str("provider", provider).
+			Msgf("Failed to render %s template", mtype)
 	}
 
 	log.Debug().
 		Str("event_id", event.ID).
 		Str("provider", provider).
-		Str("rendered_template", renderedTemplate.String()).
+		Str("rendered_template", rendered).
 		Msgf("Rendered %s template", mtype)
 
-	return renderedTemplate.String()
+	return rendered
+}
 
+// isBuiltinTemplate returns whether template is the name of a built-in template, rather than a custom template
+func isBuiltinTemplate(sourceTemplate string) bool {
+	return sourceTemplate == "markdown" || sourceTemplate == "plaintext" || sourceTemplate == "html" || sourceTemplate == "json"
+}
+
+// executeTemplate renders a built-in or custom template & returns any parse or execution errors
+func executeTemplate(sourceTemplate string, event models.Event) (string, error) {
+	var tmpl *template.Template
+	var err error
+	switch sourceTemplate {
+	case "json":
+		// Matches default webhook payload
+		payload, err := json.Marshal(webhookPayload(event))
+		return string(payload), err
+	case "markdown", "plaintext", "html":
+		tmpl, err = template.ParseFS(TemplateFiles, "templates/"+sourceTemplate+".template")
+	default:
+		tmpl, err = template.New("custom").Funcs(template.FuncMap{"env": includeenv}).Parse(sourceTemplate)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, event)
+	return rendered.String(), err
+}
+
+// RenderTemplate renders a built-in or custom template for events, the same as when sending a notification
+func RenderTemplate(sourceTemplate string, events []models.Event) (string, error) {
+	return executeTemplate(sourceTemplate, setExtras(events))
 }
 
 // Build HTTP headers or params based on template
@@ -365,16 +481,16 @@ func renderHTTPKV(list []map[string]string, event models.Event, kvtype string, p
 	for _, item := range list {
 		for k, v := range item {
 			// Render
+			var renderedTemplate bytes.Buffer
 			tmpl, err := template.New("custom").Funcs(template.FuncMap{"env": includeenv}).Parse(v)
-			if err != nil {
-				log.Warn().Err(err).Msgf("Failed to render HTTP %s", kvtype)
+			if err == nil {
+				err = tmpl.Execute(&renderedTemplate, event)
 			}
-
-			var renderedTemplate bytes.Buffer
-			err = tmpl.Execute(&renderedTemplate, event)
 			if err != nil {
-				log.Fatal().
+				log.Error().
 					Err(err).
+					Str("event_id", event.ID).
+					Str("provider", provider).
 					Msgf("Failed to render HTTP %s", kvtype)
 			}
 
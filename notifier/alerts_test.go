@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestReloadDuringSend(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	original := config.Current()
+	defer config.Set(original)
+
+	profile := models.Webhook{AlertCommon: models.AlertCommon{Enabled: true}, Server: server.URL, Method: "POST"}
+	config.Set(&config.Config{Alerts: models.Alerts{Webhook: []models.Webhook{profile, profile}}})
+	event := models.Event{ID: "event-1"}
+	provider := notifMeta{name: "webhook", index: 1}
+
+	// Shrink provider list while alerts are being sent
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendAlert(context.Background(), webhookNotifier{}, event, nil, provider)
+			config.State.Status()
+		}()
+	}
+	config.Set(&config.Config{Alerts: models.Alerts{Webhook: []models.Webhook{profile}}})
+	wg.Wait()
+
+	// Removed profile returns an error instead of sending
+	if err := sendAlert(context.Background(), webhookNotifier{}, event, nil, provider); err == nil {
+		t.Error("Expected: error sending via removed profile")
+	}
+	status := config.State.Status()
+	if len(status.Notifications.Providers["webhook"]) != 1 {
+		t.Errorf("Expected: 1 webhook profile status, Got: %v", status.Notifications.Providers["webhook"])
+	}
+}
+
+func TestRenderMessageError(t *testing.T) {
+	event := models.Event{ID: "event-1", Camera: "front_door"}
+
+	// Invalid templates are logged & rendered as much as possible, rather than stopping the app
+	if result := renderMessage("{{ .Camera ", event, "message", "test"); result != "" {
+		t.Errorf("Expected: empty message, Got: %v", result)
+	}
+	if result := renderMessage("{{ .Camera }} {{ .Missing }}", event, "message", "test"); result != "front_door " {
+		t.Errorf("Expected: partial message, Got: %v", result)
+	}
+	headers := renderHTTPKV([]map[string]string{{"X-Camera": "{{ .Missing }}"}}, event, "headers", "test")
+	if len(headers) != 1 || headers[0]["X-Camera"] != "" {
+		t.Errorf("Expected: empty header value, Got: %v", headers)
+	}
+}
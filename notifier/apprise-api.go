@@ -1,8 +1,10 @@
 package notifier
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -30,10 +32,52 @@ type AppriseAPIAttachment struct {
 	Mimetype string `json:"mimetype"`
 }
 
-// SendAppriseAPI forwards alert messages to Apprise API notification server
-func SendAppriseAPI(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.AppriseAPI[provider.index]
-	status := &config.Internal.Status.Notifications.AppriseAPI[provider.index]
+type appriseAPINotifier struct{}
+
+func init() {
+	config.RegisterNotifier(appriseAPINotifier{})
+}
+
+func (appriseAPINotifier) Name() string {
+	return "apprise_api"
+}
+
+func (appriseAPINotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.AppriseAPI)
+}
+
+func (appriseAPINotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.AppriseAPI)
+	var appriseapiErrors []string
+	log.Debug().Msgf("Alerting enabled for Apprise API profile ID %v", id)
+	if profile.Server == "" {
+		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("No Apprise API server specified! Profile ID %v", id))
+	}
+	if profile.Token == "" && len(profile.URLs) == 0 {
+		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("No Apprise API token or notification URLs specified! Profile ID %v", id))
+	}
+	if profile.Token != "" && len(profile.URLs) != 0 {
+		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("Only Apprise API token or notification URLs may be configured, not both! Profile ID %v", id))
+	}
+	if profile.Token != "" && len(profile.Tags) == 0 {
+		appriseapiErrors = append(appriseapiErrors, fmt.Sprintf("If using Apprise API token, tags must also be configured! Profile ID %v", id))
+	}
+
+	// Check if Apprise API server URL contains protocol, assume HTTP if not specified
+	if !strings.Contains(profile.Server, "http://") && !strings.Contains(profile.Server, "https://") {
+		log.Debug().Msgf("No protocol specified on Apprise API Server. Assuming http://. If this is incorrect, please adjust the config file. Profile ID %v", id)
+		profile.Server = fmt.Sprintf("http://%s", profile.Server)
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Apprise API", profile.Template); msg != "" {
+		appriseapiErrors = append(appriseapiErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+	return appriseapiErrors
+}
+
+// Send forwards alert messages to Apprise API notification server
+func (appriseAPINotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.AppriseAPI)
 
 	// Build notification
 	var message string
@@ -47,7 +91,7 @@ func SendAppriseAPI(event models.Event, snapshot io.Reader, provider notifMeta)
 	if profile.Title != "" {
 		title = renderMessage(profile.Title, event, "title", "apprise_api")
 	} else {
-		title = renderMessage(config.ConfigData.Alerts.General.Title, event, "title", "apprise_api")
+		title = renderMessage(config.Current().Alerts.General.Title, event, "title", "apprise_api")
 	}
 
 	payload := AppriseAPIPayload{
@@ -62,6 +106,30 @@ func SendAppriseAPI(event models.Event, snapshot io.Reader, provider notifMeta)
 		payload.Tags = strings.Join(profile.Tags, ",")
 	}
 
+	header := map[string]string{"Content-Type": "application/json"}
+
+	// Build URL
+	appriseapiURL := profile.Server
+	if !strings.HasSuffix(appriseapiURL, "/notify") {
+		appriseapiURL += "/notify"
+	}
+	if profile.Token != "" {
+		appriseapiURL += "/" + profile.Token
+	}
+
+	// Notification URLs include credentials for each service
+	logPayload := payload
+	if logPayload.URLs != "" {
+		logPayload.URLs = config.SecretRedacted
+	}
+	request := dryRunRequest{Method: "POST", URL: appriseapiURL, Secrets: []string{profile.Token}, Headers: []map[string]string{header}, Body: logPayload}
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
 	if event.HasSnapshot {
 		img, _ := io.ReadAll(snapshot)
 		attach := AppriseAPIAttachment{
@@ -72,45 +140,19 @@ func SendAppriseAPI(event models.Event, snapshot io.Reader, provider notifMeta)
 		payload.Attachment = append(payload.Attachment, attach)
 	}
 
-	header := map[string]string{"Content-Type": "application/json"}
-
 	data, err := json.Marshal(payload)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "apprise_api").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 
-	// Build URL
-	appriseapiURL := profile.Server
-	if !strings.HasSuffix(appriseapiURL, "/notify") {
-		appriseapiURL += "/notify"
-	}
-	if profile.Token != "" {
-		appriseapiURL += "/" + profile.Token
-	}
-
-	response, err := util.HTTPPost(appriseapiURL, profile.Insecure, data, "", header)
+	response, err := util.HTTPPost(ctx, appriseapiURL, profile.Insecure, data, "", header)
 	if err != nil {
-		log.Warn().
+		log.Debug().
 			Str("event_id", event.ID).
-			Str("provider", "Apprise API").
+			Str("provider", "apprise_api").
 			Str("response", string(response)).
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
-	}
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Apprise API").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+			Msg("Apprise API error response")
+		return err
+	}
+	return nil
 }
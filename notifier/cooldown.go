@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/util"
+)
+
+var providerCooldowns = util.NewCooldown()
+
+// providerInCooldown checks if a notification was recently sent via this provider profile for the same camera & label
+func providerInCooldown(events []models.Event, cooldown int, provider notifMeta) bool {
+	if cooldown <= 0 {
+		return false
+	}
+	windows := make(map[string]time.Duration)
+	var ids []string
+	for _, event := range events {
+		label := event.Label
+		if label == "" {
+			label = event.Extra.Audio
+		}
+		windows[fmt.Sprintf("%s/%d/%s/%s", provider.name, provider.index, event.Camera, label)] = time.Duration(cooldown) * time.Second
+		if event.ID != "" {
+			ids = append(ids, event.ID)
+		}
+	}
+
+	if providerCooldowns.Allow(windows, ids) {
+		return false
+	}
+	log.Debug().
+		Str("event_id", events[0].ID).
+		Str("provider", provider.name).
+		Int("provider_id", provider.index).
+		Msg("Notification dropped - Cooldown period")
+	return true
+}
@@ -0,0 +1,225 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+const digestBucket = "digest"
+
+// Default time to collect alerts before sending a digest, if not configured
+const defaultDigestInterval = 3600
+
+// digest collects alerts for a single provider profile until the next summary is sent
+type digest struct {
+	Provider  string               `json:"provider"`
+	ProfileID int                  `json:"provider_id"`
+	Start     time.Time            `json:"start"`
+	Total     int                  `json:"total"`
+	Counts    []models.DigestCount `json:"counts"`
+	// Highest scoring alert, whose details & snapshot are sent with the summary. Only this single snapshot is attached
+	Event    models.Event `json:"event"`
+	Snapshot []byte       `json:"snapshot,omitempty"`
+}
+
+var (
+	digests    = make(map[string]*digest)
+	digestLock sync.Mutex
+	digestOnce sync.Once
+)
+
+// StartDigests restores any pending digests from local storage & begins sending digests when due
+func StartDigests() {
+	digestOnce.Do(func() {
+		loadDigests()
+		go func() {
+			ticker := time.NewTicker(10 * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					flushDigests()
+				case <-pool.quit:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// loadDigests restores pending digests from local storage
+func loadDigests() {
+	if !storage.Ready() {
+		return
+	}
+	digestLock.Lock()
+	defer digestLock.Unlock()
+	storage.ForEach(digestBucket, func(key string, value []byte) error {
+		var d digest
+		if err := json.Unmarshal(value, &d); err != nil {
+			log.Warn().
+				Str("digest", key).
+				Err(err).
+				Msg("Unable to read pending digest")
+			return nil
+		}
+		digests[key] = &d
+		return nil
+	})
+	if len(digests) > 0 {
+		log.Debug().
+			Int("pending", len(digests)).
+			Msg("Restored pending digests")
+	}
+}
+
+// addToDigest collects an alert to be included in the next digest for this provider profile
+func addToDigest(event models.Event, events []models.Event, snapshot []byte, provider notifMeta) {
+	digestLock.Lock()
+	defer digestLock.Unlock()
+
+	key := fmt.Sprintf("%s/%d", provider.name, provider.index)
+	d, ok := digests[key]
+	if !ok {
+		d = &digest{Provider: provider.name, ProfileID: provider.index, Start: time.Now()}
+		digests[key] = d
+	}
+	d.Total++
+	for _, e := range events {
+		d.count(e)
+	}
+
+	// Keep highest scoring alert with a snapshot to represent the digest
+	if d.Total == 1 || (event.HasSnapshot && !d.Event.HasSnapshot) ||
+		(event.HasSnapshot == d.Event.HasSnapshot && event.TopScore >= d.Event.TopScore) {
+		d.Event = event
+		d.Snapshot = snapshot
+	}
+
+	if storage.Ready() {
+		if err := storage.Put(digestBucket, key, d); err != nil {
+			log.Warn().
+				Str("provider", provider.name).
+				Int("provider_id", provider.index).
+				Err(err).
+				Msg("Unable to save pending digest")
+		}
+	}
+
+	log.Debug().
+		Str("event_id", event.ID).
+		Str("provider", provider.name).
+		Int("provider_id", provider.index).
+		Int("total", d.Total).
+		Msg("Alert added to digest")
+}
+
+// count increments number of alerts for the event camera & label
+func (d *digest) count(event models.Event) {
+	label := event.Label
+	if label == "" {
+		label = event.Extra.Audio
+	}
+	for i := range d.Counts {
+		if d.Counts[i].Camera == event.Camera && d.Counts[i].Label == label {
+			d.Counts[i].Count++
+			return
+		}
+	}
+	caser := cases.Title(language.Und)
+	d.Counts = append(d.Counts, models.DigestCount{
+		Camera:     event.Camera,
+		CameraName: caser.String(strings.ReplaceAll(event.Camera, "_", " ")),
+		Label:      label,
+		Count:      1,
+	})
+}
+
+// flushDigests sends each digest whose interval has passed. Due digests are removed while holding the lock,
+// then sent after it is released, so new alerts can still be collected while digests are sent
+func flushDigests() {
+	type dueDigest struct {
+		notifier config.Notifier
+		digest   *digest
+	}
+	var due []dueDigest
+
+	digestLock.Lock()
+	now := time.Now()
+	for key, d := range digests {
+		n, ok := config.GetNotifier(d.Provider)
+		var profile *models.AlertCommon
+		if ok {
+			profiles := n.Profiles(config.Current())
+			ok = d.ProfileID < len(profiles) && profiles[d.ProfileID].Common().Enabled
+			if ok {
+				profile = profiles[d.ProfileID].Common()
+			}
+		}
+		// Wait for interval to pass, unless digest was disabled since alerts were collected
+		if ok && profile.Digest.Enabled {
+			interval := profile.Digest.Interval
+			if interval == 0 {
+				interval = defaultDigestInterval
+			}
+			if now.Before(d.Start.Add(time.Duration(interval) * time.Second)) {
+				continue
+			}
+		}
+
+		delete(digests, key)
+		if storage.Ready() {
+			storage.Delete(digestBucket, key)
+		}
+		if !ok {
+			log.Warn().
+				Str("provider", d.Provider).
+				Int("provider_id", d.ProfileID).
+				Int("total", d.Total).
+				Msg("Digest discarded - Notification provider profile no longer enabled")
+			continue
+		}
+		due = append(due, dueDigest{notifier: n, digest: d})
+	}
+	digestLock.Unlock()
+
+	for _, item := range due {
+		d := item.digest
+		event := d.Event
+		// Digest covers many alerts, so result is not recorded to history of a single event
+		event.Extra.HistoryID = ""
+		event.Extra.Digest = &models.DigestSummary{
+			Start:          d.Start,
+			End:            now,
+			FormattedStart: formatTime(d.Start),
+			FormattedEnd:   formatTime(now),
+			Total:          d.Total,
+			Counts:         d.Counts,
+		}
+		log.Info().
+			Str("provider", d.Provider).
+			Int("provider_id", d.ProfileID).
+			Int("total", d.Total).
+			Msg("Sending digest")
+		dispatchAlert(item.notifier, event, d.Snapshot, notifMeta{name: d.Provider, index: d.ProfileID})
+	}
+}
+
+// formatTime formats a timestamp using the configured time format, if set
+func formatTime(t time.Time) string {
+	if config.Current().Alerts.General.TimeFormat != "" {
+		return t.Format(config.Current().Alerts.General.TimeFormat)
+	}
+	return t.String()
+}
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestAddToDigest(t *testing.T) {
+	// Setup
+	defer config.Update(func(c *config.Config) {
+		c.Alerts.Discord = []models.Discord{{AlertCommon: models.AlertCommon{Enabled: true, Digest: models.Digest{Enabled: true}}}}
+	})()
+	provider := notifMeta{name: "discord", index: 0}
+
+	first := models.Event{ID: "event-1", Camera: "back_yard", Label: "person", TopScore: 0.9, HasSnapshot: false}
+	second := models.Event{ID: "event-2", Camera: "back_yard", Label: "person", TopScore: 0.7, HasSnapshot: true}
+	third := models.Event{ID: "event-3", Camera: "back_yard", Label: "cat", TopScore: 0.8, HasSnapshot: true}
+	addToDigest(first, []models.Event{first}, nil, provider)
+	addToDigest(second, []models.Event{second}, []byte("snapshot"), provider)
+	addToDigest(third, []models.Event{third}, []byte("snapshot"), provider)
+
+	d, ok := digests["discord/0"]
+	if !ok {
+		t.Fatal("Expected: digest for discord/0")
+	}
+
+	// Check totals & counts
+	if d.Total != 3 {
+		t.Errorf("Expected: 3, Got: %v", d.Total)
+	}
+	expected := []models.DigestCount{
+		{Camera: "back_yard", CameraName: "Back Yard", Label: "person", Count: 2},
+		{Camera: "back_yard", CameraName: "Back Yard", Label: "cat", Count: 1},
+	}
+	if len(d.Counts) != len(expected) {
+		t.Fatalf("Expected: %v, Got: %v", expected, d.Counts)
+	}
+	for i := range expected {
+		if d.Counts[i] != expected[i] {
+			t.Errorf("Expected: %v, Got: %v", expected[i], d.Counts[i])
+		}
+	}
+
+	// Check highest scoring event with snapshot is kept
+	if d.Event.ID != "event-3" {
+		t.Errorf("Expected: event-3, Got: %v", d.Event.ID)
+	}
+
+	// Check digest not sent before interval
+	flushDigests()
+	if _, ok := digests["discord/0"]; !ok {
+		t.Error("Expected: digest still pending")
+	}
+	delete(digests, "discord/0")
+}
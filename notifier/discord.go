@@ -10,87 +10,159 @@ import (
 	"github.com/0x2142/frigate-notify/config"
 	"github.com/0x2142/frigate-notify/models"
 	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
 )
 
-// SendDiscordMessage pushes alert message to Discord via webhook
-func SendDiscordMessage(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Discord[provider.index]
-	status := &config.Internal.Status.Notifications.Discord[provider.index]
+type discordNotifier struct{}
 
-	var err error
-	var message string
-	// Build notification
-	if profile.Template != "" {
-		message = renderMessage(profile.Template, event, "message", "Discord")
+func init() {
+	config.RegisterNotifier(discordNotifier{})
+}
+
+func (discordNotifier) Name() string {
+	return "discord"
+}
+
+func (discordNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Discord)
+}
+
+func (discordNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Discord)
+	var discordErrors []string
+	log.Debug().Msgf("Alerting enabled for Discord profile ID %v", id)
+	if profile.Webhook == "" {
+		discordErrors = append(discordErrors, fmt.Sprintf("No Discord webhook specified! Profile ID %v", id))
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Discord", profile.Template); msg != "" {
+		discordErrors = append(discordErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+	return discordErrors
+}
+
+// Send pushes alert message to Discord via webhook
+func (d discordNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	_, err := d.SendMessage(ctx, event, snapshot, p)
+	return err
+}
+
+// SendMessage pushes alert message to Discord via webhook & returns the message ID
+func (discordNotifier) SendMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) (string, error) {
+	profile := p.(*models.Discord)
+
+	title, message := discordMessage(event, profile)
+
+	create := discord.NewWebhookMessageCreateBuilder()
+	if profile.DisableEmbed {
+		create.SetContent(message)
 	} else {
-		message = renderMessage("markdown", event, "message", "Discord")
+		embed := discord.NewEmbedBuilder().SetDescription(message).SetTitle(title).SetColor(5793266)
+		if event.HasSnapshot {
+			embed.SetImage("attachment://snapshot.jpg")
+		}
+		create.SetEmbeds(embed.Build())
+	}
+	// Attach snapshot if one was saved
+	if event.HasSnapshot {
+		create.SetFiles(discord.NewFile("snapshot.jpg", "", snapshot))
+	}
+	payload := create.Build()
+
+	request := dryRunRequest{Method: "POST", URL: profile.Webhook, Secrets: []string{webhookToken(profile.Webhook)}, Body: payload}
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return "", nil
 	}
 
 	// Connect to Discord
 	client, err := webhook.NewWithURL(profile.Webhook)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Discord").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
+		return "", err
 	}
 	defer client.Close(context.TODO())
 
-	var title string
-	if profile.Title != "" {
-		title = renderMessage(profile.Title, event, "title", "discord")
-	} else {
-		title = renderMessage(config.ConfigData.Alerts.General.Title, event, "title", "discord")
+	msg, err := client.CreateMessage(payload, rest.WithCtx(ctx))
+	log.Trace().
+		Str("event_id", event.ID).
+		Interface("payload", msg).
+		Msg("Send Discord Alert")
+	if err != nil {
+		return "", err
 	}
+	return msg.ID.String(), nil
+}
 
-	title = fmt.Sprintf("**%v**\n\n", title)
-	message = title + message
+// EditMessage updates a previously sent Discord message with new event details & snapshot
+func (discordNotifier) EditMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile, messageID string) error {
+	profile := p.(*models.Discord)
 
-	// Send alert & attach snapshot if one was saved
-	var msg *discord.Message
-	if event.HasSnapshot {
-		image := discord.NewFile("snapshot.jpg", "", snapshot)
-		if profile.DisableEmbed {
-			msg, err = client.CreateMessage(discord.NewWebhookMessageCreateBuilder().SetContent(message).SetFiles(image).Build())
-		} else {
-			embed := discord.NewEmbedBuilder().SetDescription(message).SetTitle(title).SetImage("attachment://snapshot.jpg").SetColor(5793266).Build()
-			msg, err = client.CreateMessage(discord.NewWebhookMessageCreateBuilder().SetEmbeds(embed).SetFiles(image).Build())
-		}
-		log.Trace().
-			Str("event_id", event.ID).
-			Int("provider_id", provider.index).
-			Interface("payload", msg).
-			Msg("Send Discord Alert")
+	msgID, err := snowflake.Parse(messageID)
+	if err != nil {
+		return err
+	}
+
+	title, message := discordMessage(event, profile)
+
+	update := discord.NewWebhookMessageUpdateBuilder()
+	if profile.DisableEmbed {
+		update.SetContent(message)
 	} else {
-		if profile.DisableEmbed {
-			msg, err = client.CreateMessage(discord.NewWebhookMessageCreateBuilder().SetContent(message).Build())
-		} else {
-			embed := discord.NewEmbedBuilder().SetDescription(message).SetTitle(title).SetColor(5793266).Build()
-			msg, err = client.CreateMessage(discord.NewWebhookMessageCreateBuilder().SetEmbeds(embed).Build())
+		embed := discord.NewEmbedBuilder().SetDescription(message).SetTitle(title).SetColor(5793266)
+		if event.HasSnapshot {
+			embed.SetImage("attachment://snapshot.jpg")
 		}
-		log.Trace().
-			Str("event_id", event.ID).
-			Int("provider_id", provider.index).
-			Interface("payload", msg).
-			Msg("Send Discord Alert")
+		update.SetEmbeds(embed.Build())
 	}
+	// Replace existing snapshot
+	if event.HasSnapshot {
+		update.RetainAttachments().SetFiles(discord.NewFile("snapshot.jpg", "", snapshot))
+	}
+	payload := update.Build()
+
+	request := dryRunRequest{Method: "PATCH", URL: profile.Webhook + "/messages/" + messageID, Secrets: []string{webhookToken(profile.Webhook)}, Body: payload}
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
+	// Connect to Discord
+	client, err := webhook.NewWithURL(profile.Webhook)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Discord").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-	}
-	log.Info().
+		return err
+	}
+	defer client.Close(context.TODO())
+
+	msg, err := client.UpdateMessage(msgID, payload, rest.WithCtx(ctx))
+	log.Trace().
 		Str("event_id", event.ID).
-		Str("provider", "Discord").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+		Interface("payload", msg).
+		Msg("Edit Discord Alert")
+	return err
+}
+
+// discordMessage renders Discord notification title & message
+func discordMessage(event models.Event, profile *models.Discord) (string, string) {
+	var message string
+	if profile.Template != "" {
+		message = renderMessage(profile.Template, event, "message", "Discord")
+	} else {
+		message = renderMessage("markdown", event, "message", "Discord")
+	}
+
+	var title string
+	if profile.Title != "" {
+		title = renderMessage(profile.Title, event, "title", "discord")
+	} else {
+		title = renderMessage(config.Current().Alerts.General.Title, event, "title", "discord")
+	}
+
+	title = fmt.Sprintf("**%v**\n\n", title)
+	return title, title + message
 }
@@ -0,0 +1,29 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateDiscord(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Discord = make([]models.Discord, 1)
+
+	// Test valid config
+	c.Alerts.Discord[0].Webhook = "https://something.test"
+	result := discordNotifier{}.Validate(&c, 0, &c.Alerts.Discord[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing webhook config
+	c.Alerts.Discord[0].Webhook = ""
+	result = discordNotifier{}.Validate(&c, 0, &c.Alerts.Discord[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
@@ -0,0 +1,126 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+type providerContextKey struct{}
+
+// withProvider adds the provider profile an alert is being sent via to ctx, so it can be included in logs
+func withProvider(ctx context.Context, provider notifMeta) context.Context {
+	return context.WithValue(ctx, providerContextKey{}, provider)
+}
+
+// providerFromContext returns the provider profile an alert is being sent via
+func providerFromContext(ctx context.Context) notifMeta {
+	provider, _ := ctx.Value(providerContextKey{}).(notifMeta)
+	return provider
+}
+
+// dryRunRequest is the request a provider would send for an alert, logged in dry run mode
+type dryRunRequest struct {
+	Method string
+	URL    string
+	// Values to remove from the URL, ex: Discord webhook token
+	Secrets []string
+	Headers []map[string]string
+	// Request body, either encoded JSON, text or a value to log as JSON. Secret fields must be redacted by the provider
+	Body interface{}
+	// Files uploaded with the request, ex: snapshot.jpg
+	Files []string
+}
+
+// dryRun returns whether dry run mode is enabled. If so, the request that would have been sent is logged instead,
+// with credentials in the URL & headers redacted
+func dryRun(ctx context.Context, event models.Event, request dryRunRequest) bool {
+	if !config.IsDryRun() {
+		return false
+	}
+	provider := providerFromContext(ctx)
+	entry := log.Info().
+		Str("event_id", event.ID).
+		Str("provider", provider.name).
+		Int("provider_id", provider.index)
+	if request.Method != "" {
+		entry = entry.Str("method", request.Method)
+	}
+	if request.URL != "" {
+		entry = entry.Str("url", redactURL(request.URL, request.Secrets))
+	}
+	if len(request.Headers) > 0 {
+		entry = entry.Interface("headers", config.RedactKeys(request.Headers))
+	}
+	switch body := request.Body.(type) {
+	case nil:
+	case []byte:
+		if json.Valid(body) {
+			entry = entry.RawJSON("body", body)
+		} else {
+			entry = entry.Str("body", string(body))
+		}
+	case string:
+		entry = entry.Str("body", body)
+	default:
+		entry = entry.Interface("body", body)
+	}
+	if len(request.Files) > 0 {
+		entry = entry.Strs("files", request.Files)
+	}
+	entry.Msg("Dry run - Alert not sent")
+	return true
+}
+
+// redactURL removes credentials from a URL for logging, including passwords, query parameters with
+// credential-like names & any of the given secrets
+func redactURL(raw string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			raw = strings.ReplaceAll(raw, secret, config.SecretRedacted)
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return config.SecretRedacted
+	}
+	// Placeholder is added after encoding, so it isn't escaped
+	user := u.User
+	if _, ok := user.Password(); ok {
+		u.User = nil
+	}
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			key, _, _ := strings.Cut(param, "=")
+			if name, err := url.QueryUnescape(key); err == nil && config.IsSensitiveKey(name) {
+				params[i] = key + "=" + config.SecretRedacted
+			}
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+	if u.User == nil && user != nil {
+		return strings.Replace(u.String(), "//", "//"+url.User(user.Username()).String()+":"+config.SecretRedacted+"@", 1)
+	}
+	return u.String()
+}
+
+// webhookToken returns the last element of a webhook URL path, which is the secret token for Discord & Mattermost webhooks
+func webhookToken(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	token := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if token == "." || token == "/" {
+		return ""
+	}
+	return token
+}
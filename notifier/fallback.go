@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// sendFallback sends alert via the fallback profile configured for a failed provider profile, if any.
+// Called from a worker, so the fallback is sent inline rather than waiting for another worker
+func sendFallback(ctx context.Context, event models.Event, snapshot []byte, provider notifMeta) {
+	n, ok := config.GetNotifier(provider.name)
+	if !ok {
+		return
+	}
+	profiles := n.Profiles(config.Current())
+	if provider.index >= len(profiles) {
+		return
+	}
+	fallback, id, ok := config.GetFallback(config.Current(), profiles[provider.index].Common())
+	if !ok {
+		return
+	}
+
+	target := notifMeta{name: fallback.Name(), index: id, fallbackFor: messageKey(provider)}
+	log.Warn().
+		Str("event_id", event.ID).
+		Str("provider", provider.name).
+		Int("provider_id", provider.index).
+		Str("fallback", target.name).
+		Int("fallback_id", target.index).
+		Msg("Sending alert via fallback provider")
+	setStatus(event, target.name, target.index, history.StatusPending, "Fallback for "+target.fallbackFor)
+	if queueEnabled() {
+		enqueueAlert(fallback, event, snapshot, target)
+		return
+	}
+	if err := sendAlert(ctx, fallback, event, snapshot, target); err != nil && ctx.Err() == nil {
+		sendFallback(ctx, event, snapshot, target)
+	}
+}
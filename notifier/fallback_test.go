@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+var fallbackProfiles = []models.AlertCommon{
+	{Enabled: true, Name: "primary", Fallback: models.Fallback{Provider: "fallback_test", Profile: "backup"}},
+	{Enabled: false, Name: "backup"},
+}
+
+// fallbackNotifier fails to send via its first profile & reports alerts sent via other profiles
+type fallbackNotifier struct {
+	sent chan string
+}
+
+func (f fallbackNotifier) Name() string { return "fallback_test" }
+func (f fallbackNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(fallbackProfiles)
+}
+func (f fallbackNotifier) Validate(c *config.Config, id int, p config.Profile) []string { return nil }
+func (f fallbackNotifier) Send(_ context.Context, _ models.Event, _ io.Reader, p config.Profile) error {
+	if p.Common().Name == "primary" {
+		return errors.New("unavailable")
+	}
+	f.sent <- p.Common().Name
+	return nil
+}
+
+func TestFallback(t *testing.T) {
+	n := fallbackNotifier{sent: make(chan string, 1)}
+	if _, ok := config.GetNotifier(n.Name()); !ok {
+		config.RegisterNotifier(n)
+	}
+
+	// Failed alert is sent via fallback profile
+	deliverAlert(n, models.Event{ID: "event-1"}, nil, notifMeta{name: n.Name(), index: 0})
+	select {
+	case name := <-n.sent:
+		if name != "backup" {
+			t.Errorf("Expected: alert sent via backup profile, Got: %v", name)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected: alert sent via fallback")
+	}
+}
@@ -4,166 +4,206 @@ import (
 	"slices"
 	"time"
 
+	"github.com/0x2142/frigate-notify/config"
 	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/snooze"
 	"github.com/rs/zerolog/log"
 )
 
-// checkAlertFilters will determine which notification provider is able to send this alert
-func checkAlertFilters(events []models.Event, filters models.AlertFilter, provider notifMeta) bool {
-	log.Trace().
-		Str("provider", provider.name).
-		Int("provider_id", provider.index).
-		Msg("Checking alert filters")
+// alertFilter is a single check applied by a notification provider profile to determine if it should send an alert
+type alertFilter struct {
+	name  string
+	check func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string)
+}
 
-	// Collect event details
-	var cameras, labels, sublabels, zones []string
-	for _, event := range events {
-		if !slices.Contains(cameras, event.Camera) {
-			cameras = append(cameras, event.Camera)
+// eventDetails collects cameras, labels, sublabels & zones from all events in an alert
+type eventDetails struct {
+	cameras, labels, sublabels, zones []string
+}
+
+// alertFilters are all provider checks, in the order they are applied
+var alertFilters = []alertFilter{
+	{"quiet_hours", func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string) {
+		// Check against quiet hours
+		currentTime, _ := time.Parse("15:04:05", time.Now().Format("15:04:05"))
+		start, _ := time.Parse("15:04", filters.Quiet.Start)
+		end, _ := time.Parse("15:04", filters.Quiet.End)
+		log.Trace().
+			Time("current_time", currentTime).
+			Time("quiet_start", start).
+			Time("quiet_end", end).
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Msg("Check quiet hours")
+		// Check if quiet period is overnight
+		if end.Before(start) {
+			if currentTime.After(start) || currentTime.Before(end) {
+				return false, "Quiet hours"
+			}
 		}
-		if !slices.Contains(labels, event.Label) {
-			labels = append(labels, event.Label)
+		// Otherwise check if between start & end times
+		if currentTime.After(start) && currentTime.Before(end) {
+			return false, "Quiet hours"
 		}
-		if !slices.Contains(sublabels, event.SubLabel) {
-			sublabels = append(sublabels, event.SubLabel)
+		return true, ""
+	}},
+	{"cameras", func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string) {
+		// Check filtered cameras
+		log.Trace().
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Strs("cameras", details.cameras).
+			Strs("allowed", filters.Cameras).
+			Msg("Check allowed cameras")
+		if !matchesFilter(details.cameras, filters.Cameras) {
+			return false, "Camera not on filter list"
 		}
-		for _, zone := range event.CurrentZones {
-			if !slices.Contains(zones, zone) {
-				zones = append(zones, zone)
-			}
+		return true, ""
+	}},
+	{"zones", func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string) {
+		// Check filtered zones
+		log.Trace().
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Strs("zones", details.zones).
+			Strs("allowed", filters.Zones).
+			Msg("Check allowed zone")
+		if !matchesFilter(details.zones, filters.Zones) {
+			return false, "Zone not on filter list"
 		}
-	}
-
-	// Check against quiet hours
-	currentTime, _ := time.Parse("15:04:05", time.Now().Format("15:04:05"))
-	start, _ := time.Parse("15:04", filters.Quiet.Start)
-	end, _ := time.Parse("15:04", filters.Quiet.End)
-	log.Trace().
-		Time("current_time", currentTime).
-		Time("quiet_start", start).
-		Time("quiet_end", end).
-		Str("provider", provider.name).
-		Int("provider_id", provider.index).
-		Msg("Check quiet hours")
-	// Check if quiet period is overnight
-	if end.Before(start) {
-		if currentTime.After(start) || currentTime.Before(end) {
-			log.Debug().
-				Str("provider", provider.name).
-				Int("provider_id", provider.index).
-				Msg("Notification droppped - Quiet hours")
-			return false
+		return true, ""
+	}},
+	{"labels", func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string) {
+		// Check filtered Labels
+		log.Trace().
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Strs("labels", details.labels).
+			Strs("allowed", filters.Labels).
+			Msg("Check allowed label")
+		if !matchesFilter(details.labels, filters.Labels) {
+			return false, "Label not on filter list"
 		}
-	}
-	// Otherwise check if between start & end times
-	if currentTime.After(start) && currentTime.Before(end) {
-		log.Debug().
+		return true, ""
+	}},
+	{"sublabels", func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string) {
+		// Check filtered Sublabels
+		log.Trace().
 			Str("provider", provider.name).
 			Int("provider_id", provider.index).
-			Msg("Notification droppped - Quiet hours")
-		return false
-	}
+			Strs("sublabels", details.sublabels).
+			Strs("allowed", filters.Sublabels).
+			Msg("Check allowed sublabel")
+		if !matchesFilter(details.sublabels, filters.Sublabels) {
+			return false, "Sublabel not on filter list"
+		}
+		return true, ""
+	}},
+	{"snooze", func(details eventDetails, filters models.AlertFilter, provider notifMeta) (bool, string) {
+		// Check if provider profile is snoozed via API
+		if snooze.Provider(provider.name, provider.key, details.cameras, details.labels) {
+			return false, "Snoozed"
+		}
+		return true, ""
+	}},
+}
 
-	// Check filtered cameras
-	log.Trace().
-		Str("provider", provider.name).
-		Int("provider_id", provider.index).
-		Strs("cameras", cameras).
-		Strs("allowed", filters.Cameras).
-		Msg("Check allowed cameras")
-	if len(filters.Cameras) >= 1 {
-		match := false
-		for _, camera := range cameras {
-			if slices.Contains(filters.Cameras, camera) {
-				match = true
-				break
-			}
+// mutingFilters are alert filters that silence a provider profile for a time, rather than filtering which events it alerts on.
+// These are checked again before sending follow-ups & updates to previously sent alerts
+var mutingFilters = []string{"quiet_hours", "snooze"}
+
+// profileMuted returns whether a provider profile is in quiet hours or snoozed for events, along with the reason
+func profileMuted(events []models.Event, filters models.AlertFilter, provider notifMeta) (bool, string) {
+	details := collectDetails(events)
+	for _, filter := range alertFilters {
+		if !slices.Contains(mutingFilters, filter.name) {
+			continue
 		}
-		if !match {
-			log.Debug().
-				Str("provider", provider.name).
-				Int("provider_id", provider.index).
-				Msg("Notification droppped - Camera not on filter list")
-			return false
+		if ok, reason := filter.check(details, filters, provider); !ok {
+			return true, reason
 		}
 	}
+	return false, ""
+}
 
-	// Check filtered zones
+// checkAlertFilters will determine which notification provider is able to send this alert.
+// If not permitted, the reason the alert was dropped is also returned
+func checkAlertFilters(events []models.Event, filters models.AlertFilter, provider notifMeta) (bool, string) {
 	log.Trace().
 		Str("provider", provider.name).
 		Int("provider_id", provider.index).
-		Strs("zones", zones).
-		Strs("allowed", filters.Zones).
-		Msg("Check allowed zone")
-	if len(filters.Zones) >= 1 {
-		match := false
-		for _, zone := range zones {
-			if slices.Contains(filters.Zones, zone) {
-				match = true
-				break
-			}
-		}
-		if !match {
+		Msg("Checking alert filters")
+
+	details := collectDetails(events)
+	for _, filter := range alertFilters {
+		if ok, reason := filter.check(details, filters, provider); !ok {
 			log.Debug().
 				Str("provider", provider.name).
 				Int("provider_id", provider.index).
-				Msg("Notification droppped - Zone not on filter list")
-			return false
+				Msg("Notification droppped - " + reason)
+			return false, reason
 		}
 	}
 
-	// Check filtered Labels
+	// Alert permitted if all conditions pass
 	log.Trace().
 		Str("provider", provider.name).
 		Int("provider_id", provider.index).
-		Strs("labels", labels).
-		Strs("allowed", filters.Labels).
-		Msg("Check allowed label")
-	if len(filters.Labels) >= 1 {
-		match := false
-		for _, label := range labels {
-			if slices.Contains(filters.Labels, label) {
-				match = true
-				break
+		Msg("Alert filters passed!")
+	return true, ""
+}
+
+// ExplainAlertFilters runs every filter of each notification provider profile against events & returns the result of each
+func ExplainAlertFilters(events []models.Event) []models.ProviderExplanation {
+	details := collectDetails(events)
+	var results []models.ProviderExplanation
+	for _, n := range config.Notifiers() {
+		for id, p := range n.Profiles(config.Current()) {
+			profile := p.Common()
+			provider := notifMeta{name: n.Name(), index: id, key: config.ProfileKey(p)}
+			result := models.ProviderExplanation{Provider: provider.name, ProfileID: id, Name: profile.Name, Enabled: profile.Enabled, Notify: profile.Enabled}
+			for _, filter := range alertFilters {
+				ok, reason := filter.check(details, profile.Filters, provider)
+				result.Steps = append(result.Steps, models.FilterStep{Filter: filter.name, Passed: ok, Reason: reason})
+				result.Notify = result.Notify && ok
 			}
-		}
-		if !match {
-			log.Debug().
-				Str("provider", provider.name).
-				Int("provider_id", provider.index).
-				Msg("Notification droppped - Label not on filter list")
-			return false
+			results = append(results, result)
 		}
 	}
+	return results
+}
 
-	// Check filtered Sublabels
-	log.Trace().
-		Str("provider", provider.name).
-		Int("provider_id", provider.index).
-		Strs("sublabels", sublabels).
-		Strs("allowed", filters.Sublabels).
-		Msg("Check allowed sublabel")
-	if len(filters.Sublabels) >= 1 {
-		match := false
-		for _, sublabel := range sublabels {
-			if slices.Contains(filters.Sublabels, sublabel) {
-				match = true
-				break
-			}
+// collectDetails collects unique cameras, labels, sublabels & zones from events
+func collectDetails(events []models.Event) eventDetails {
+	var details eventDetails
+	for _, event := range events {
+		if !slices.Contains(details.cameras, event.Camera) {
+			details.cameras = append(details.cameras, event.Camera)
 		}
-		if !match {
-			log.Debug().
-				Str("provider", provider.name).
-				Int("provider_id", provider.index).
-				Msg("Notification droppped - Sublabel not on filter list")
-			return false
+		if !slices.Contains(details.labels, event.Label) {
+			details.labels = append(details.labels, event.Label)
+		}
+		if !slices.Contains(details.sublabels, event.SubLabel) {
+			details.sublabels = append(details.sublabels, event.SubLabel)
+		}
+		for _, zone := range event.CurrentZones {
+			if !slices.Contains(details.zones, zone) {
+				details.zones = append(details.zones, zone)
+			}
 		}
 	}
+	return details
+}
 
-	// Alert permitted if all conditions pass
-	log.Trace().
-		Str("provider", provider.name).
-		Int("provider_id", provider.index).
-		Msg("Alert filters passed!")
-	return true
+// matchesFilter returns whether any value is on the filter list, or true if no filter list is set
+func matchesFilter(values []string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if slices.Contains(filter, value) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// SendFollowUp sends a final notification once an event or review has ended, via each provider profile that sent the original alert
+func SendFollowUp(events []models.Event) {
+	if len(events) == 0 || !config.Current().Alerts.General.NotifyEnd {
+		return
+	}
+	key := alertKey(events[0])
+	record, ok := getMessages(key)
+	if !ok || len(record.Messages) == 0 {
+		log.Debug().
+			Str("event_id", events[0].ID).
+			Msg("No alert previously sent, skipping follow-up")
+		return
+	}
+
+	event := setExtras(events)
+	event.Extra.Ended = true
+	event.Extra.Duration = eventDuration(events)
+
+	// Collect final snapshot, if available
+	snap := collectSnapshot(appContext(), events)
+	if snap == nil {
+		event.HasSnapshot = false
+	}
+
+	log.Info().
+		Str("event_id", event.ID).
+		Str("duration", event.Extra.Duration).
+		Str("zones", event.Extra.ZoneList).
+		Msg("Sending follow-up for ended event...")
+	for _, n := range config.Notifiers() {
+		for id, p := range n.Profiles(config.Current()) {
+			profile := p.Common()
+			provider := notifMeta{name: n.Name(), index: id, key: config.ProfileKey(p)}
+			if _, sent := record.Messages[messageKey(provider)]; !profile.Enabled || !sent {
+				continue
+			}
+			if muted, reason := profileMuted(events, profile.Filters, provider); muted {
+				log.Debug().
+					Str("event_id", event.ID).
+					Str("provider", provider.name).
+					Int("provider_id", provider.index).
+					Msg("Follow-up skipped - " + reason)
+				continue
+			}
+			dispatchAlert(n, event, snap, provider)
+		}
+	}
+}
+
+// eventDuration returns time between the start of the first event & end of the last event
+func eventDuration(events []models.Event) string {
+	start := events[0].StartTime
+	var end float64
+	for _, event := range events {
+		start = min(start, event.StartTime)
+		if endTime, ok := event.EndTime.(float64); ok {
+			end = max(end, endTime)
+		}
+	}
+	if end <= start {
+		return ""
+	}
+	return time.Duration((end - start) * float64(time.Second)).Round(time.Second).String()
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+// replyNotifier records calls to reply to messages
+type replyNotifier struct {
+	editNotifier
+	replies []string
+}
+
+func (r *replyNotifier) ReplyMessage(_ context.Context, _ models.Event, _ io.Reader, _ config.Profile, messageID string) error {
+	r.replies = append(r.replies, messageID)
+	return nil
+}
+
+// plainNotifier records calls to send messages
+type plainNotifier struct {
+	sent int
+}
+
+func (p *plainNotifier) Name() string { return "plain_test" }
+func (p *plainNotifier) Profiles(c *config.Config) []config.Profile {
+	return []config.Profile{&models.AlertCommon{Enabled: true}}
+}
+func (p *plainNotifier) Validate(c *config.Config, id int, profile config.Profile) []string {
+	return nil
+}
+func (p *plainNotifier) Send(context.Context, models.Event, io.Reader, config.Profile) error {
+	p.sent++
+	return nil
+}
+
+func TestEventDuration(t *testing.T) {
+	tests := []struct {
+		events   []models.Event
+		expected string
+	}{
+		{[]models.Event{{StartTime: 100, EndTime: 165.4}}, "1m5s"},
+		{[]models.Event{{StartTime: 100, EndTime: 110.0}, {StartTime: 90, EndTime: 130.0}}, "40s"},
+		{[]models.Event{{StartTime: 100}}, ""},
+	}
+
+	for i, test := range tests {
+		result := eventDuration(test.events)
+		if result != test.expected {
+			t.Errorf("Test %d - Expected: %v, Got: %v", i, test.expected, result)
+		}
+	}
+}
+
+func TestFollowUp(t *testing.T) {
+	// Setup
+	if err := storage.Open(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Unable to open data store: %v", err)
+	}
+	defer storage.Close()
+	defer config.Update(func(c *config.Config) { c.Alerts.General.NotifyEnd = true })()
+
+	event := models.Event{ID: "event-1"}
+	replier := &replyNotifier{}
+	plain := &plainNotifier{}
+	replyProvider := notifMeta{name: "edit_test", index: 0}
+	plainProvider := notifMeta{name: "plain_test", index: 0}
+
+	// Alerts are recorded, but not edited unless update_messages is enabled
+	sendAlert(context.Background(), replier, event, nil, replyProvider)
+	sendAlert(context.Background(), replier, event, nil, replyProvider)
+	sendAlert(context.Background(), plain, event, nil, plainProvider)
+	if replier.sent != 2 || len(replier.edited) != 0 {
+		t.Errorf("Expected: 2 messages sent & 0 edited, Got: %v sent & %v edited", replier.sent, len(replier.edited))
+	}
+	record, ok := getMessages("event-1")
+	if !ok || len(record.Messages) != 2 {
+		t.Fatalf("Expected: 2 providers recorded, Got: %v", record.Messages)
+	}
+
+	// Follow-up is sent as reply where supported, otherwise as a new message
+	event.Extra.Ended = true
+	sendAlert(context.Background(), replier, event, nil, replyProvider)
+	sendAlert(context.Background(), plain, event, nil, plainProvider)
+	if len(replier.replies) != 1 || replier.replies[0] != "message-1" {
+		t.Errorf("Expected: reply to message-1, Got: %v", replier.replies)
+	}
+	if plain.sent != 2 {
+		t.Errorf("Expected: 2 plain messages sent, Got: %v", plain.sent)
+	}
+}
+
+func TestProfileMuted(t *testing.T) {
+	events := []models.Event{{ID: "event-1", Camera: "driveway", Label: "person"}}
+	provider := notifMeta{name: "plain_test", index: 0}
+
+	if muted, reason := profileMuted(events, models.AlertFilter{}, provider); muted {
+		t.Errorf("Expected: not muted, Got: %v", reason)
+	}
+
+	// Profile quiet hours mute follow-ups, other filters are not checked again
+	quiet := models.AlertFilter{Cameras: []string{"backyard"}}
+	quiet.Quiet.Start = time.Now().Add(-time.Hour).Format("15:04")
+	quiet.Quiet.End = time.Now().Add(time.Hour).Format("15:04")
+	if muted, reason := profileMuted(events, quiet, provider); !muted || reason != "Quiet hours" {
+		t.Errorf("Expected: muted by quiet hours, Got: %v", reason)
+	}
+	if muted, _ := profileMuted(events, models.AlertFilter{Cameras: []string{"backyard"}}, provider); muted {
+		t.Error("Expected: camera filter not checked")
+	}
+}
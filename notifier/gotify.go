@@ -1,8 +1,10 @@
 package notifier
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -34,16 +36,51 @@ type gotifyPayload struct {
 	} `json:"extras,omitempty"`
 }
 
-// SendGotifyPush forwards alert messages to Gotify push notification server
-func SendGotifyPush(event models.Event, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Gotify[provider.index]
-	status := &config.Internal.Status.Notifications.Gotify[provider.index]
+type gotifyNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(gotifyNotifier{})
+}
+
+func (gotifyNotifier) Name() string {
+	return "gotify"
+}
+
+func (gotifyNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Gotify)
+}
+
+func (gotifyNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Gotify)
+	var gotifyErrors []string
+	log.Debug().Msgf("Alerting enabled for Gotify profile ID %v", id)
+	if profile.Server == "" {
+		gotifyErrors = append(gotifyErrors, fmt.Sprintf("No Gotify server specified! Profile ID %v", id))
+	}
+	if profile.Token == "" {
+		gotifyErrors = append(gotifyErrors, fmt.Sprintf("No Gotify token specified! Profile ID %v", id))
+	}
+	// Check if Gotify server URL contains protocol, assume HTTP if not specified
+	if !strings.Contains(profile.Server, "http://") && !strings.Contains(profile.Server, "https://") {
+		log.Debug().Msgf("No protocol specified on Gotify Server. Assuming http://. If this is incorrect, please adjust the config file. Profile ID %v", id)
+		profile.Server = fmt.Sprintf("http://%s", profile.Server)
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Gotify", profile.Template); msg != "" {
+		gotifyErrors = append(gotifyErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+	return gotifyErrors
+}
+
+// Send forwards alert messages to Gotify push notification server
+func (gotifyNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.Gotify)
 
 	var snapshotURL string
-	if config.ConfigData.Frigate.PublicURL != "" {
-		snapshotURL = config.ConfigData.Frigate.PublicURL + "/api/events/" + event.ID + "/snapshot.jpg"
+	if config.Current().Frigate.PublicURL != "" {
+		snapshotURL = config.Current().Frigate.PublicURL + "/api/events/" + event.ID + "/snapshot.jpg"
 	} else {
-		snapshotURL = config.ConfigData.Frigate.Server + "/api/events/" + event.ID + "/snapshot.jpg"
+		snapshotURL = config.Current().Frigate.Server + "/api/events/" + event.ID + "/snapshot.jpg"
 	}
 	// Build notification
 	var message string
@@ -53,65 +90,45 @@ func SendGotifyPush(event models.Event, provider notifMeta) {
 		message = renderMessage("markdown", event, "message", "Gotify")
 	}
 
-	if event.HasSnapshot {
+	// Snapshot is linked from Frigate, so manual alert images cannot be included
+	if event.HasSnapshot && event.Extra.Manual == nil {
 		message += fmt.Sprintf("\n\n![](%s)", snapshotURL)
 	}
 	var title string
 	if profile.Title != "" {
 		title = renderMessage(profile.Title, event, "title", "gotify")
 	} else {
-		title = renderMessage(config.ConfigData.Alerts.General.Title, event, "title", "gotify")
+		title = renderMessage(config.Current().Alerts.General.Title, event, "title", "gotify")
 	}
 	payload := gotifyPayload{
 		Message:  message,
 		Title:    title,
-		Priority: config.ConfigData.Alerts.Gotify[provider.index].Priority,
+		Priority: profile.Priority,
 	}
 	payload.Extras.ClientDisplay.ContentType = "text/markdown"
 	payload.Extras.ClientNotification.BigImageURL = snapshotURL
 
+	gotifyURL := fmt.Sprintf("%s/message?token=%s&", profile.Server, profile.Token)
+	header := map[string]string{"Content-Type": "application/json"}
+
 	data, err := json.Marshal(payload)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Gotify").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 
-	gotifyURL := fmt.Sprintf("%s/message?token=%s&", profile.Server, profile.Token)
+	if dryRun(ctx, event, dryRunRequest{Method: "POST", URL: gotifyURL, Headers: []map[string]string{header}, Body: data}) {
+		return nil
+	}
 
-	header := map[string]string{"Content-Type": "application/json"}
-	response, err := util.HTTPPost(gotifyURL, profile.Insecure, data, "", header)
+	response, err := util.HTTPPost(ctx, gotifyURL, profile.Insecure, data, "", header)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Gotify").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 	// Check for errors:
 	if strings.Contains(string(response), "error") {
 		var errorMessage gotifyError
 		json.Unmarshal(response, &errorMessage)
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Gotify").
-			Int("provider_id", provider.index).
-			Msgf("Unable to send alert: %v - %v", errorMessage.Error, errorMessage.ErrorDescription)
-		status.NotifFailure(errorMessage.ErrorDescription)
-		return
+		return fmt.Errorf("%v - %v", errorMessage.Error, errorMessage.ErrorDescription)
 	}
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Gotify").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+	return nil
 }
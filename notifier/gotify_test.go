@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateGotify(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Gotify = make([]models.Gotify, 1)
+
+	// Test valid config
+	c.Alerts.Gotify[0].Server = "https://something.test"
+	c.Alerts.Gotify[0].Token = "abcdefg"
+	result := gotifyNotifier{}.Validate(&c, 0, &c.Alerts.Gotify[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing server config
+	c.Alerts.Gotify[0].Server = ""
+	result = gotifyNotifier{}.Validate(&c, 0, &c.Alerts.Gotify[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing token config
+	c.Alerts.Gotify[0].Token = ""
+	result = gotifyNotifier{}.Validate(&c, 0, &c.Alerts.Gotify[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// SendManualAlert sends an alert submitted via API to all enabled providers. Provider filters, cooldowns & digests
+// are applied the same as alerts for Frigate events, using the camera & label provided.
+// Returns the generated event ID & notification history ID
+func SendManualAlert(alert models.ManualAlert, camera, label string, snapshot []byte) (string, string) {
+	now := time.Now()
+	config.State.SetLastNotification(now)
+
+	var event models.Event
+	event.ID = generatedID(now, "manual")
+	event.Camera = camera
+	event.Label = label
+	event.StartTime = float64(now.UnixMicro()) / 1e6
+	event.HasSnapshot = snapshot != nil
+
+	events := []models.Event{event}
+	event = setExtras(events)
+	event.Extra.Manual = &alert
+
+	log.Info().
+		Str("event_id", event.ID).
+		Str("camera", camera).
+		Str("label", label).
+		Msg("Sending manual alert")
+
+	return event.ID, sendToProviders(events, event, snapshot)
+}
+
+// generatedID returns an event ID in the same format as Frigate, for alerts not generated by a Frigate event
+func generatedID(t time.Time, suffix string) string {
+	return fmt.Sprintf("%d.%06d-%s", t.Unix(), t.Nanosecond()/1000, suffix)
+}
+
+// manualMessage formats text of a manual alert to match a built-in template
+func manualMessage(message string, sourceTemplate string) string {
+	if sourceTemplate == "html" {
+		return strings.ReplaceAll(html.EscapeString(message), "\n", "<br />\n")
+	}
+	return message
+}
@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestRenderManualAlert(t *testing.T) {
+	event := models.Event{Camera: "front_door"}
+	event.Extra.Manual = &models.ManualAlert{Title: "Doorbell", Message: "Someone at the <door>\nPlease answer"}
+
+	// Title & built-in templates are replaced
+	if result := renderMessage("{{ .Camera }}", event, "title", "test"); result != "Doorbell" {
+		t.Errorf("Expected: Doorbell, Got: %v", result)
+	}
+	expected := "Someone at the &lt;door&gt;<br />\nPlease answer"
+	if result := renderMessage("html", event, "message", "test"); result != expected {
+		t.Errorf("Expected: %v, Got: %v", expected, result)
+	}
+
+	// Custom templates are still rendered
+	if result := renderMessage("{{ .Camera }}: {{ .Extra.Manual.Message }}", event, "message", "test"); result != "front_door: Someone at the <door>\nPlease answer" {
+		t.Errorf("Expected: rendered custom template, Got: %v", result)
+	}
+
+	// Configured title is used if none provided
+	event.Extra.Manual.Title = ""
+	if result := renderMessage("{{ .Camera }}", event, "title", "test"); result != "front_door" {
+		t.Errorf("Expected: front_door, Got: %v", result)
+	}
+}
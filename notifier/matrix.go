@@ -3,8 +3,10 @@ package notifier
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -18,31 +20,218 @@ import (
 	"github.com/0x2142/frigate-notify/models"
 )
 
-// SendMatrix pushes alert message to Matrix chat via webhook
-func SendMatrix(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Matrix[provider.index]
-	status := &config.Internal.Status.Notifications.Matrix[provider.index]
+type matrixNotifier struct{}
 
-	var err error
-	var message string
-	// Build notification
+func init() {
+	config.RegisterNotifier(matrixNotifier{})
+}
+
+func (matrixNotifier) Name() string {
+	return "matrix"
+}
+
+func (matrixNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Matrix)
+}
+
+func (matrixNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Matrix)
+	var matrixErrors []string
+	log.Debug().Msgf("Alerting enabled for Matrix profile ID %v", id)
+	if profile.Server == "" {
+		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix homeserver specified! Profile ID %v", id))
+	}
+	// Check username / auth token
+	if profile.Username == "" {
+		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix username specified! Profile ID %v", id))
+	}
+	if profile.Password == "" {
+		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix password specified! Profile ID %v", id))
+	}
+	if profile.RoomID == "" {
+		matrixErrors = append(matrixErrors, fmt.Sprintf("No Matrix room ID specified! Profile ID %v", id))
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Matrix", profile.Template); msg != "" {
+		matrixErrors = append(matrixErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+	return matrixErrors
+}
+
+// Send pushes alert message to Matrix chat
+func (m matrixNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	_, err := m.SendMessage(ctx, event, snapshot, p)
+	return err
+}
+
+// SendMessage pushes alert message to Matrix chat & returns the message event IDs, ex: "<text_id>,<image_id>"
+func (matrixNotifier) SendMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) (string, error) {
+	profile := p.(*models.Matrix)
+
+	content := matrixText(matrixMessage(event, profile))
+
+	request := matrixRequest(profile, content)
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return "", nil
+	}
+
+	m, err := matrixConnect(ctx, profile)
+	if err != nil {
+		return "", err
+	}
+
+	// Send snapshot image if available
+	var imageID id.EventID
+	if event.HasSnapshot {
+		content, err := matrixSnapshot(ctx, m, snapshot)
+		if err != nil {
+			return "", err
+		}
+		resp, err := m.SendMessageEvent(ctx, id.RoomID(profile.RoomID), evt.EventMessage, content)
+		if err != nil {
+			return "", err
+		}
+		imageID = resp.EventID
+	}
+
+	// Send event details
+	resp, err := m.SendMessageEvent(ctx, id.RoomID(profile.RoomID), evt.EventMessage, content)
+	if err != nil {
+		return "", err
+	}
+	if imageID != "" {
+		return fmt.Sprintf("%s,%s", resp.EventID, imageID), nil
+	}
+	return resp.EventID.String(), nil
+}
+
+// EditMessage replaces a previously sent Matrix message with new event details & snapshot
+func (matrixNotifier) EditMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile, messageID string) error {
+	profile := p.(*models.Matrix)
+
+	textID, imageID, _ := strings.Cut(messageID, ",")
+
+	content := matrixText(matrixMessage(event, profile))
+	content.SetEdit(id.EventID(textID))
+
+	request := matrixRequest(profile, content)
+	if event.HasSnapshot && imageID != "" {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
+	m, err := matrixConnect(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	// Replace snapshot image, if one was previously sent
+	if event.HasSnapshot && imageID != "" {
+		content, err := matrixSnapshot(ctx, m, snapshot)
+		if err != nil {
+			return err
+		}
+		content.SetEdit(id.EventID(imageID))
+		_, err = m.SendMessageEvent(ctx, id.RoomID(profile.RoomID), evt.EventMessage, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Replace event details
+	_, err = m.SendMessageEvent(ctx, id.RoomID(profile.RoomID), evt.EventMessage, content)
+	return err
+}
+
+// ReplyMessage sends follow-up with event clip to Matrix chat as a reply to a previously sent alert
+func (matrixNotifier) ReplyMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile, messageID string) error {
+	profile := p.(*models.Matrix)
+
+	textID, _, _ := strings.Cut(messageID, ",")
+
+	content := matrixText(matrixMessage(event, profile))
+	content.RelatesTo = (&evt.RelatesTo{}).SetReplyTo(id.EventID(textID))
+
+	// Clip isn't downloaded in dry run mode, so it is listed if Frigate has one
+	request := matrixRequest(profile, content)
+	if event.HasClip {
+		request.Files = []string{"clip.mp4"}
+	} else if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
+	m, err := matrixConnect(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	// Send event details
+	_, err = m.SendMessageEvent(ctx, id.RoomID(profile.RoomID), evt.EventMessage, content)
+	if err != nil {
+		return err
+	}
+
+	// Send event clip if available, otherwise final snapshot
+	var clip io.Reader
+	if event.HasClip {
+		clip = GetClip(ctx, event)
+	}
+	var media *evt.MessageEventContent
+	if clip != nil {
+		media, err = matrixClip(ctx, m, clip)
+	} else if event.HasSnapshot {
+		media, err = matrixSnapshot(ctx, m, snapshot)
+	}
+	if err != nil || media == nil {
+		return err
+	}
+	media.RelatesTo = (&evt.RelatesTo{}).SetReplyTo(id.EventID(textID))
+	_, err = m.SendMessageEvent(ctx, id.RoomID(profile.RoomID), evt.EventMessage, media)
+	return err
+}
+
+// matrixMessage renders Matrix notification message
+func matrixMessage(event models.Event, profile *models.Matrix) string {
 	if profile.Template != "" {
-		message = renderMessage(profile.Template, event, "message", "Matrix")
-	} else {
-		message = renderMessage("html", event, "message", "Matrix")
+		return renderMessage(profile.Template, event, "message", "Matrix")
+	}
+	return renderMessage("html", event, "message", "Matrix")
+}
+
+// matrixText returns text message content for Matrix notification message
+func matrixText(message string) *evt.MessageEventContent {
+	return &evt.MessageEventContent{
+		MsgType:       evt.MsgText,
+		Format:        "org.matrix.custom.html",
+		FormattedBody: message,
+	}
+}
+
+// matrixRequest returns the Matrix request for a message, for dry run mode. Messages are sent encrypted if the room
+// requires it, so the unencrypted content is logged
+func matrixRequest(profile *models.Matrix, content *evt.MessageEventContent) dryRunRequest {
+	return dryRunRequest{
+		Method: "PUT",
+		URL:    profile.Server + "/_matrix/client/v3/rooms/" + profile.RoomID + "/send/m.room.message",
+		Body:   content,
 	}
+}
+
+// matrixConnect logs in to Matrix homeserver & joins configured room
+func matrixConnect(ctx context.Context, profile *models.Matrix) (*mautrix.Client, error) {
 
 	// New matrix client
 	m, err := mautrix.NewClient(profile.Server, "", "")
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Matrix").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return nil, err
 	}
 
 	// Ignore self-signed certs if set
@@ -58,80 +247,50 @@ func SendMatrix(event models.Event, snapshot io.Reader, provider notifMeta) {
 	m.StateStore = mautrix.NewMemoryStateStore()
 	ch, err := cryptohelper.NewCryptoHelper(m, []byte("asdf"), "./matrix.db")
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Matrix").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return nil, err
 	}
 	ch.LoginAs = &mautrix.ReqLogin{
 		Type:       mautrix.AuthTypePassword,
 		Identifier: mautrix.UserIdentifier{Type: mautrix.IdentifierTypeUser, User: profile.Username},
 		Password:   profile.Password,
 	}
-	ch.Init(context.Background())
+	ch.Init(ctx)
 	m.Crypto = ch
 
 	// Join room if needed
-	_, err = m.JoinRoomByID(context.Background(), id.RoomID(profile.RoomID))
+	_, err = m.JoinRoomByID(ctx, id.RoomID(profile.RoomID))
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Matrix").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return nil, err
 	}
+	return m, nil
+}
 
-	// Send snapshot image if available
-	if event.HasSnapshot {
-		var media *mautrix.RespMediaUpload
-		img, _ := io.ReadAll(snapshot)
-		media, _ = m.UploadMedia(context.Background(), mautrix.ReqUploadMedia{ContentBytes: img, ContentType: "image/jpeg", FileName: "snapshot.jpg"})
-		_, err = m.SendMessageEvent(context.Background(), id.RoomID(profile.RoomID), evt.EventMessage, &evt.MessageEventContent{
-			MsgType: evt.MsgImage,
-			Body:    "snapshot.jpg",
-			URL:     media.ContentURI.CUString(),
-			Info:    &evt.FileInfo{MimeType: "image/jpeg"},
-		})
-		if err != nil {
-			log.Warn().
-				Str("event_id", event.ID).
-				Str("provider", "Matrix").
-				Int("provider_id", provider.index).
-				Err(err).
-				Msg("Unable to send alert")
-			status.NotifFailure(err.Error())
-			return
-		}
+// matrixSnapshot uploads snapshot & returns image message content
+func matrixSnapshot(ctx context.Context, m *mautrix.Client, snapshot io.Reader) (*evt.MessageEventContent, error) {
+	img, _ := io.ReadAll(snapshot)
+	media, err := m.UploadMedia(ctx, mautrix.ReqUploadMedia{ContentBytes: img, ContentType: "image/jpeg", FileName: "snapshot.jpg"})
+	if err != nil {
+		return nil, err
 	}
+	return &evt.MessageEventContent{
+		MsgType: evt.MsgImage,
+		Body:    "snapshot.jpg",
+		URL:     media.ContentURI.CUString(),
+		Info:    &evt.FileInfo{MimeType: "image/jpeg"},
+	}, nil
+}
 
-	// Send event details
-	_, err = m.SendMessageEvent(context.Background(), id.RoomID(profile.RoomID), evt.EventMessage, &evt.MessageEventContent{
-		MsgType:       evt.MsgText,
-		Format:        "org.matrix.custom.html",
-		FormattedBody: message,
-	})
+// matrixClip uploads event clip & returns video message content
+func matrixClip(ctx context.Context, m *mautrix.Client, clip io.Reader) (*evt.MessageEventContent, error) {
+	video, _ := io.ReadAll(clip)
+	media, err := m.UploadMedia(ctx, mautrix.ReqUploadMedia{ContentBytes: video, ContentType: "video/mp4", FileName: "clip.mp4"})
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Matrix").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
-	}
-
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Matrix").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+		return nil, err
+	}
+	return &evt.MessageEventContent{
+		MsgType: evt.MsgVideo,
+		Body:    "clip.mp4",
+		URL:     media.ContentURI.CUString(),
+		Info:    &evt.FileInfo{MimeType: "video/mp4"},
+	}, nil
 }
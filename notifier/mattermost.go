@@ -1,7 +1,12 @@
 package notifier
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
@@ -24,19 +29,54 @@ type MattermostAttachment struct {
 	ImageURL string `json:"image_url,omitempty"`
 }
 
-// SendMatterMost pushes alert message to Mattermost via webhook
-func SendMattermost(event models.Event, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Mattermost[provider.index]
-	status := &config.Internal.Status.Notifications.Mattermost[provider.index]
+type mattermostNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(mattermostNotifier{})
+}
+
+func (mattermostNotifier) Name() string {
+	return "mattermost"
+}
+
+func (mattermostNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Mattermost)
+}
+
+func (mattermostNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Mattermost)
+	var mattermostErrors []string
+	log.Debug().Msgf("Alerting enabled for Mattermost profile ID %v", id)
+	if profile.Webhook == "" {
+		mattermostErrors = append(mattermostErrors, fmt.Sprintf("No Mattermost webhook specified! Profile ID %v", id))
+	}
+	// Set default priority if not specified
+	if profile.Priority == "" {
+		profile.Priority = "standard"
+	}
+	// Check valid priority if set
+	validPriorities := []string{"standard", "important", "urgent"}
+	if !slices.Contains(validPriorities, strings.ToLower(profile.Priority)) {
+		mattermostErrors = append(mattermostErrors, fmt.Sprintf("Invalid priority for Mattermost (valid: standard, urgent, or important). Profile ID %v", id))
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Mattermost", profile.Template); msg != "" {
+		mattermostErrors = append(mattermostErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+	return mattermostErrors
+}
+
+// Send pushes alert message to Mattermost via webhook
+func (mattermostNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.Mattermost)
 
 	var snapshotURL string
-	if config.ConfigData.Frigate.PublicURL != "" {
-		snapshotURL = config.ConfigData.Frigate.PublicURL + "/api/events/" + event.ID + "/snapshot.jpg"
+	if config.Current().Frigate.PublicURL != "" {
+		snapshotURL = config.Current().Frigate.PublicURL + "/api/events/" + event.ID + "/snapshot.jpg"
 	} else {
-		snapshotURL = config.ConfigData.Frigate.Server + "/api/events/" + event.ID + "/snapshot.jpg"
+		snapshotURL = config.Current().Frigate.Server + "/api/events/" + event.ID + "/snapshot.jpg"
 	}
 
-	var err error
 	var message string
 	// Build notification
 	if profile.Template != "" {
@@ -50,39 +90,21 @@ func SendMattermost(event models.Event, provider notifMeta) {
 	payload := MattermostPayload{Text: message, Channel: profile.Channel, Username: profile.Username}
 	payload.Priority.Priority = profile.Priority
 
-	if event.HasSnapshot {
+	// Snapshot is linked from Frigate, so manual alert images cannot be included
+	if event.HasSnapshot && event.Extra.Manual == nil {
 		attach := MattermostAttachment{ImageURL: snapshotURL}
 		payload.Attachments = append(payload.Attachments, attach)
 	}
 
 	data, err := json.Marshal(payload)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Mattermost").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 
-	_, err = util.HTTPPost(profile.Webhook, profile.Insecure, []byte(data), "", headers...)
-
-	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Mattermost").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+	if dryRun(ctx, event, dryRunRequest{Method: "POST", URL: profile.Webhook, Secrets: []string{webhookToken(profile.Webhook)}, Headers: headers, Body: data}) {
+		return nil
 	}
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Mattermost").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+
+	_, err = util.HTTPPost(ctx, profile.Webhook, profile.Insecure, []byte(data), "", headers...)
+	return err
 }
@@ -0,0 +1,29 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateMattermost(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Mattermost = make([]models.Mattermost, 1)
+
+	// Test valid config
+	c.Alerts.Mattermost[0].Webhook = "https://webhook.test"
+	result := mattermostNotifier{}.Validate(&c, 0, &c.Alerts.Mattermost[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing server config
+	c.Alerts.Mattermost[0].Webhook = ""
+	result = mattermostNotifier{}.Validate(&c, 0, &c.Alerts.Mattermost[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
@@ -0,0 +1,202 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+const messageBucket = "messages"
+
+// How long to keep message IDs for sent alerts
+const messageRetention = 24 * time.Hour
+
+// sentMessages records provider message IDs sent for an event or review, so they can be updated later
+type sentMessages struct {
+	Content  string            `json:"content"`
+	Messages map[string]string `json:"messages"`
+	Updated  time.Time         `json:"updated"`
+}
+
+var messageLock sync.Mutex
+
+// updatesEnabled returns whether previously sent alerts should be edited when event details change
+func updatesEnabled() bool {
+	return config.Current().Alerts.General.UpdateMessages && storage.Ready()
+}
+
+// trackingEnabled returns whether sent alerts should be recorded, so they can be updated or followed up later
+func trackingEnabled() bool {
+	return (config.Current().Alerts.General.UpdateMessages || config.Current().Alerts.General.NotifyEnd) && storage.Ready()
+}
+
+// alertKey returns the ID used to track messages for an alert, which is the review ID in reviews mode or event ID otherwise
+func alertKey(event models.Event) string {
+	// Digests summarize many events, while test & manual alerts are one-off, so are never updated
+	if event.Extra.Digest != nil || event.Extra.Test || event.Extra.Manual != nil {
+		return ""
+	}
+	if event.Extra.ReviewID != "" {
+		return event.Extra.ReviewID
+	}
+	return event.ID
+}
+
+// alertContent summarizes event details shown in notifications, used to check if an update is needed
+func alertContent(event models.Event) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%v",
+		event.Extra.ZoneList,
+		event.Extra.LabelList,
+		event.Extra.SubLabelList,
+		event.Extra.LicensePlateList,
+		event.Extra.TopScorePercent,
+		event.HasSnapshot)
+}
+
+func messageKey(provider notifMeta) string {
+	return fmt.Sprintf("%s/%d", provider.name, provider.index)
+}
+
+// AlertSent returns whether alerts have previously been sent for an event or review ID
+func AlertSent(key string) bool {
+	if !trackingEnabled() || key == "" {
+		return false
+	}
+	var record sentMessages
+	ok, _ := storage.Get(messageBucket, key, &record)
+	return ok
+}
+
+// getMessages returns all messages previously sent for an event or review ID
+func getMessages(key string) (sentMessages, bool) {
+	messageLock.Lock()
+	defer messageLock.Unlock()
+
+	var record sentMessages
+	if !trackingEnabled() || key == "" {
+		return record, false
+	}
+	ok, _ := storage.Get(messageBucket, key, &record)
+	return record, ok
+}
+
+// recordContent saves details of the latest alert for an event or review & returns false if they have not changed
+func recordContent(key string, content string) bool {
+	messageLock.Lock()
+	defer messageLock.Unlock()
+
+	var record sentMessages
+	ok, err := storage.Get(messageBucket, key, &record)
+	if err != nil {
+		return true
+	}
+	if ok && record.Content == content {
+		return false
+	}
+	if !ok {
+		pruneMessages()
+	}
+	record.Content = content
+	record.Updated = time.Now()
+	storage.Put(messageBucket, key, record)
+	return true
+}
+
+// getMessageID returns the ID of a message previously sent via a provider profile, if any
+func getMessageID(key string, provider notifMeta) string {
+	record, _ := getMessages(key)
+	return record.Messages[messageKey(provider)]
+}
+
+// saveMessageID records the ID of a message sent via a provider profile.
+// Providers that cannot edit or reply to messages are recorded with an empty ID
+func saveMessageID(key string, provider notifMeta, messageID string) {
+	messageLock.Lock()
+	defer messageLock.Unlock()
+
+	var record sentMessages
+	storage.Get(messageBucket, key, &record)
+	if record.Messages == nil {
+		record.Messages = make(map[string]string)
+	}
+	record.Messages[messageKey(provider)] = messageID
+	record.Updated = time.Now()
+	if err := storage.Put(messageBucket, key, record); err != nil {
+		log.Warn().
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Err(err).
+			Msg("Unable to save message ID")
+	}
+}
+
+// pruneMessages removes message IDs for alerts that have not been updated recently
+func pruneMessages() {
+	var expired []string
+	storage.ForEach(messageBucket, func(key string, value []byte) error {
+		var record sentMessages
+		if err := json.Unmarshal(value, &record); err != nil || time.Since(record.Updated) > messageRetention {
+			expired = append(expired, key)
+		}
+		return nil
+	})
+	for _, key := range expired {
+		storage.Delete(messageBucket, key)
+	}
+	if len(expired) > 0 {
+		log.Trace().
+			Str("alerts", strings.Join(expired, ",")).
+			Msg("Removed expired message IDs")
+	}
+}
+
+// UpdateAlert edits previously sent alerts for an event or review if details have changed
+func UpdateAlert(events []models.Event) {
+	if len(events) == 0 || !updatesEnabled() || !AlertSent(alertKey(events[0])) {
+		return
+	}
+
+	event := setExtras(events)
+	snap := collectSnapshot(appContext(), events)
+	if snap == nil {
+		event.HasSnapshot = false
+	}
+
+	key := alertKey(event)
+	if !recordContent(key, alertContent(event)) {
+		log.Debug().
+			Str("event_id", event.ID).
+			Msg("Alert unchanged, skipping update")
+		return
+	}
+
+	log.Info().
+		Str("event_id", event.ID).
+		Str("zones", event.Extra.ZoneList).
+		Str("sublabels", event.Extra.SubLabelList).
+		Msg("Updating previously sent alerts...")
+	for _, n := range config.Notifiers() {
+		if _, ok := n.(config.MessageEditor); !ok {
+			continue
+		}
+		for id, p := range n.Profiles(config.Current()) {
+			profile := p.Common()
+			provider := notifMeta{name: n.Name(), index: id, key: config.ProfileKey(p)}
+			// Skip provider profiles snoozed via API or in quiet hours
+			if muted, _ := profileMuted(events, profile.Filters, provider); muted {
+				continue
+			}
+			if profile.Enabled && getMessageID(key, provider) != "" {
+				dispatchAlert(n, event, snap, provider)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+// editNotifier records calls to send & edit messages
+type editNotifier struct {
+	sent   int
+	edited []string
+}
+
+func (e *editNotifier) Name() string { return "edit_test" }
+func (e *editNotifier) Profiles(c *config.Config) []config.Profile {
+	return []config.Profile{&models.AlertCommon{Enabled: true}}
+}
+func (e *editNotifier) Validate(c *config.Config, id int, p config.Profile) []string { return nil }
+func (e *editNotifier) Send(context.Context, models.Event, io.Reader, config.Profile) error {
+	e.sent++
+	return nil
+}
+func (e *editNotifier) SendMessage(context.Context, models.Event, io.Reader, config.Profile) (string, error) {
+	e.sent++
+	return "message-1", nil
+}
+func (e *editNotifier) EditMessage(ctx context.Context, _ models.Event, _ io.Reader, _ config.Profile, messageID string) error {
+	e.edited = append(e.edited, messageID)
+	return nil
+}
+
+func TestAlertKey(t *testing.T) {
+	event := models.Event{ID: "event-1"}
+	if result := alertKey(event); result != "event-1" {
+		t.Errorf("Expected: event-1, Got: %v", result)
+	}
+	event.Extra.ReviewID = "review-1"
+	if result := alertKey(event); result != "review-1" {
+		t.Errorf("Expected: review-1, Got: %v", result)
+	}
+	event.Extra.Digest = &models.DigestSummary{}
+	if result := alertKey(event); result != "" {
+		t.Errorf("Expected: empty key, Got: %v", result)
+	}
+}
+
+func TestUpdateMessages(t *testing.T) {
+	// Setup
+	if err := storage.Open(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Unable to open data store: %v", err)
+	}
+	defer storage.Close()
+	defer config.Update(func(c *config.Config) { c.Alerts.General.UpdateMessages = true })()
+
+	event := models.Event{ID: "event-1"}
+	provider := notifMeta{name: "edit_test", index: 0}
+	n := &editNotifier{}
+
+	// Check nothing sent yet
+	if AlertSent("event-1") {
+		t.Error("Expected: alert not sent")
+	}
+
+	// Check content changes are detected
+	if !recordContent("event-1", "zone_a") {
+		t.Error("Expected: new content recorded")
+	}
+	if recordContent("event-1", "zone_a") {
+		t.Error("Expected: unchanged content")
+	}
+	if !AlertSent("event-1") {
+		t.Error("Expected: alert sent")
+	}
+
+	// First alert sends new message, second edits it
+	sendAlert(context.Background(), n, event, nil, provider)
+	sendAlert(context.Background(), n, event, nil, provider)
+	if n.sent != 1 {
+		t.Errorf("Expected: 1 message sent, Got: %v", n.sent)
+	}
+	if len(n.edited) != 1 || n.edited[0] != "message-1" {
+		t.Errorf("Expected: message-1 edited, Got: %v", n.edited)
+	}
+
+	// Other events are sent as new messages
+	sendAlert(context.Background(), n, models.Event{ID: "event-2"}, nil, provider)
+	if n.sent != 2 {
+		t.Errorf("Expected: 2 messages sent, Got: %v", n.sent)
+	}
+}
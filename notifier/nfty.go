@@ -1,6 +1,8 @@
 package notifier
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -12,10 +14,46 @@ import (
 	"github.com/0x2142/frigate-notify/util"
 )
 
-// SendNtfyPush forwards alert messages to Ntfy server
-func SendNtfyPush(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Ntfy[provider.index]
-	status := &config.Internal.Status.Notifications.Ntfy[provider.index]
+type ntfyNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(ntfyNotifier{})
+}
+
+func (ntfyNotifier) Name() string {
+	return "ntfy"
+}
+
+func (ntfyNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Ntfy)
+}
+
+func (ntfyNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Ntfy)
+	var ntfyErrors []string
+	log.Debug().Msgf("Alerting enabled for Ntfy profile ID %v", id)
+	if profile.Server == "" {
+		ntfyErrors = append(ntfyErrors, fmt.Sprintf("No Ntfy server specified! Profile ID %v", id))
+	}
+	if profile.Topic == "" {
+		ntfyErrors = append(ntfyErrors, fmt.Sprintf("No Ntfy topic specified! Profile ID %v", id))
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Ntfy", profile.Template); msg != "" {
+		ntfyErrors = append(ntfyErrors, msg+fmt.Sprintf("Profile ID %v", id))
+	}
+
+	// Check HTTP header template syntax
+	if msg := config.ValidateTemplate("Ntfy HTTP Headers", c.Alerts.General.Title); msg != "" {
+		ntfyErrors = append(ntfyErrors, msg+fmt.Sprintf("Profile ID %v", id))
+	}
+
+	return ntfyErrors
+}
+
+// Send forwards alert messages to Ntfy server
+func (ntfyNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.Ntfy)
 
 	// Build notification
 	var message string
@@ -32,7 +70,7 @@ func SendNtfyPush(event models.Event, snapshot io.Reader, provider notifMeta) {
 	if profile.Title != "" {
 		title = renderMessage(profile.Title, event, "title", "ntfy")
 	} else {
-		title = renderMessage(config.ConfigData.Alerts.General.Title, event, "title", "ntfy")
+		title = renderMessage(config.Current().Alerts.General.Title, event, "title", "ntfy")
 	}
 	var headers []map[string]string
 	headers = append(headers, map[string]string{"Content-Type": "text/markdown"})
@@ -67,34 +105,22 @@ func SendNtfyPush(event models.Event, snapshot io.Reader, provider notifMeta) {
 
 	headers = renderHTTPKV(headers, event, "headers", "Ntfy")
 
-	resp, err := util.HTTPPost(NtfyURL, profile.Insecure, attachment, "", headers...)
+	request := dryRunRequest{Method: "POST", URL: NtfyURL, Headers: headers}
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
+	resp, err := util.HTTPPost(ctx, NtfyURL, profile.Insecure, attachment, "", headers...)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Ntfy").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 
 	// Ntfy returns HTTP 200 even if there is an error, so we need to inspect returned body
 	if strings.Contains(string(resp), "error") {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Ntfy").
-			Int("provider_id", provider.index).
-			Str("error", string(resp)).
-			Msg("Unable to send alert")
-		status.NotifFailure(string(resp))
-
+		return errors.New(string(resp))
 	}
-
-	log.Info().
-		Str("event_id", event.ID).
-		Int("provider_id", provider.index).
-		Str("provider", "Ntfy").
-		Msg("Alert sent")
-	status.NotifSuccess()
+	return nil
 }
@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateNtfy(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Ntfy = make([]models.Ntfy, 1)
+
+	// Test valid config
+	c.Alerts.Ntfy[0].Server = "https://ntfy.test"
+	c.Alerts.Ntfy[0].Topic = "frigate"
+	result := ntfyNotifier{}.Validate(&c, 0, &c.Alerts.Ntfy[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing server config
+	c.Alerts.Ntfy[0].Server = ""
+	result = ntfyNotifier{}.Validate(&c, 0, &c.Alerts.Ntfy[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing topic config
+	c.Alerts.Ntfy[0].Topic = ""
+	result = ntfyNotifier{}.Validate(&c, 0, &c.Alerts.Ntfy[0])
+	expected = 2
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// Sample snapshot attached to synthetic test notifications
+//
+//go:embed sample_snapshot.jpg
+var sampleSnapshot []byte
+
+type testTarget struct {
+	notifier config.Notifier
+	provider notifMeta
+	profile  string
+}
+
+// SyntheticEvent builds a test event & sample snapshot, so notifications can be tested before Frigate has any events
+func SyntheticEvent(settings models.NotifTestSynthetic) (models.Event, []byte) {
+	now := time.Now()
+	var event models.Event
+	event.ID = generatedID(now, "test")
+	event.Camera = settings.Camera
+	event.Label = settings.Label
+	event.Zones = settings.Zones
+	event.CurrentZones = settings.Zones
+	event.EnteredZones = settings.Zones
+	event.StartTime = float64(now.UnixMicro()) / 1e6
+	event.HasSnapshot = true
+	event.TopScore = 0.9
+	event.Data.TopScore = 0.9
+	return event, sampleSnapshot
+}
+
+// SendTest sends a test notification to selected provider profiles, or all enabled profiles if none are selected.
+// Filters, cooldowns, digests & the notification queue are skipped, and results are returned once all profiles finish.
+// If snapshot is nil, it is downloaded from Frigate
+func SendTest(ctx context.Context, events []models.Event, snapshot []byte, targets []models.NotifTestTarget) ([]models.NotifTestResult, error) {
+	selected, err := testTargets(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	event := setExtras(events)
+	event.Extra.Test = true
+	if snapshot == nil {
+		snapshot = collectSnapshot(ctx, events)
+	}
+	event.HasSnapshot = snapshot != nil
+
+	log.Info().
+		Str("event_id", event.ID).
+		Int("providers", len(selected)).
+		Msg("Sending test notification")
+
+	results := make([]models.NotifTestResult, len(selected))
+	var wg sync.WaitGroup
+	for i, target := range selected {
+		results[i] = models.NotifTestResult{
+			Provider:  target.provider.name,
+			ProfileID: target.provider.index,
+			Name:      target.profile,
+			Status:    history.StatusSent,
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sendAlert(ctx, target.notifier, event, snapshot, target.provider); err != nil {
+				results[i].Status = history.StatusFailed
+				results[i].Error = err.Error()
+			} else if config.IsDryRun() {
+				results[i].Status = history.StatusDryRun
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// testTargets returns provider profiles selected for a test notification. Disabled profiles may be selected
+// directly, so new profiles can be tested before enabling them
+func testTargets(targets []models.NotifTestTarget) ([]testTarget, error) {
+	var selected []testTarget
+	if len(targets) == 0 {
+		for _, n := range config.Notifiers() {
+			for id, p := range n.Profiles(config.Current()) {
+				profile := p.Common()
+				if profile.Enabled {
+					selected = append(selected, testTarget{notifier: n, provider: notifMeta{name: n.Name(), index: id}, profile: profile.Name})
+				}
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no notification providers enabled")
+		}
+		return selected, nil
+	}
+
+	for _, target := range targets {
+		n, ok := config.GetNotifier(target.Provider)
+		if !ok {
+			return nil, fmt.Errorf("unknown notification provider: %s", target.Provider)
+		}
+		profiles := n.Profiles(config.Current())
+		found := false
+		for id, p := range profiles {
+			profile := p.Common()
+			if target.ProfileID != nil && *target.ProfileID != id {
+				continue
+			}
+			if target.Name != "" && target.Name != profile.Name {
+				continue
+			}
+			found = true
+			selected = append(selected, testTarget{notifier: n, provider: notifMeta{name: n.Name(), index: id}, profile: profile.Name})
+		}
+		if !found {
+			return nil, fmt.Errorf("no matching profile configured for notification provider: %s", target.Provider)
+		}
+	}
+	return selected, nil
+}
@@ -1,7 +1,10 @@
 package notifier
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,10 +15,56 @@ import (
 	"github.com/gregdel/pushover"
 )
 
-// SendPushoverMessage sends alert message through Pushover service
-func SendPushoverMessage(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Pushover[provider.index]
-	status := &config.Internal.Status.Notifications.Pushover[provider.index]
+type pushoverNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(pushoverNotifier{})
+}
+
+func (pushoverNotifier) Name() string {
+	return "pushover"
+}
+
+func (pushoverNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Pushover)
+}
+
+func (pushoverNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Pushover)
+	var pushoverErrors []string
+	log.Debug().Msgf("Alerting enabled for Pushover profile ID %v", id)
+	if profile.Token == "" {
+		pushoverErrors = append(pushoverErrors, fmt.Sprintf("No Pushover API token specified! Profile ID %v", id))
+	}
+	if profile.Userkey == "" {
+		pushoverErrors = append(pushoverErrors, fmt.Sprintf("No Pushover user key specified! Profile ID %v", id))
+	}
+	if profile.Priority < -2 || profile.Priority > 2 {
+		pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover priority must be between -2 and 2! Profile ID %v", id))
+	}
+	// Priority 2 is emergency, needs a retry interval & expiration set
+	if profile.Priority == 2 {
+		if profile.Retry == 0 || profile.Expire == 0 {
+			pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover retry interval & expiration must be set with priority 2! Profile ID %v", id))
+		}
+		if profile.Retry < 30 {
+			pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover retry cannot be less than 30 seconds! Profile ID %v", id))
+		}
+	}
+	if profile.TTL < 0 {
+		pushoverErrors = append(pushoverErrors, fmt.Sprintf("Pushover TTL cannot be negative! Profile ID %v", id))
+	}
+
+	// Check template syntax
+	if msg := config.ValidateTemplate("Pushover", profile.Template); msg != "" {
+		pushoverErrors = append(pushoverErrors, msg+fmt.Sprintf("Profile ID %v", id))
+	}
+	return pushoverErrors
+}
+
+// Send sends alert message through Pushover service
+func (pushoverNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.Pushover)
 
 	// Build notification
 	var message string
@@ -34,7 +83,7 @@ func SendPushoverMessage(event models.Event, snapshot io.Reader, provider notifM
 	if profile.Title != "" {
 		title = renderMessage(profile.Title, event, "title", "pushover")
 	} else {
-		title = renderMessage(config.ConfigData.Alerts.General.Title, event, "title", "pushover")
+		title = renderMessage(config.Current().Alerts.General.Title, event, "title", "pushover")
 	}
 	notif := &pushover.Message{
 		Message:  message,
@@ -66,51 +115,51 @@ func SendPushoverMessage(event models.Event, snapshot io.Reader, provider notifM
 		notif.DeviceName = devices
 	}
 
+	request := dryRunRequest{Method: "POST", URL: pushover.APIEndpoint + "/messages.json", Body: pushoverForm(notif)}
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
 	log.Trace().
 		Interface("payload", notif).
 		Interface("recipient", "--secret removed--").
-		Int("provider_id", provider.index).
 		Msg("Send Pushover alert")
 
 	// Send notification
 	if event.HasSnapshot {
 		notif.AddAttachment(snapshot)
-		response, err := push.SendMessage(notif, recipient)
-		log.Trace().
-			Interface("payload", response).
-			Int("provider_id", provider.index).
-			Msg("Pushover response")
-		if err != nil {
-			log.Warn().
-				Str("event_id", event.ID).
-				Str("provider", "Pushover").
-				Int("provider_id", provider.index).
-				Msgf("Unable to send alert: %v", err)
-			status.NotifFailure(err.Error())
-			return
-		}
-	} else {
-		response, err := push.SendMessage(notif, recipient)
-		log.Trace().
-			Interface("payload", response).
-			Int("provider_id", provider.index).
-			Msg("Pushover response")
-		if err != nil {
-			log.Warn().
-				Str("event_id", event.ID).
-				Str("provider", "Pushover").
-				Int("provider_id", provider.index).
-				Msgf("Unable to send alert: %v", err)
-			status.NotifFailure(err.Error())
-			return
-		}
 	}
+	response, err := push.SendMessage(notif, recipient)
+	log.Trace().
+		Interface("payload", response).
+		Msg("Pushover response")
+	return err
+}
 
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Pushover").
-		Int("provider_id", provider.index).
-		Msgf("Alert sent")
-	status.NotifSuccess()
-
+// pushoverForm returns the form fields sent to Pushover for a message, with app token & user key redacted
+func pushoverForm(notif *pushover.Message) map[string]string {
+	form := map[string]string{
+		"token":    config.SecretRedacted,
+		"user":     config.SecretRedacted,
+		"message":  notif.Message,
+		"priority": strconv.Itoa(notif.Priority),
+		"html":     "1",
+	}
+	optional := map[string]string{"title": notif.Title, "url": notif.URL, "url_title": notif.URLTitle, "sound": notif.Sound, "device": notif.DeviceName}
+	for k, v := range optional {
+		if v != "" {
+			form[k] = v
+		}
+	}
+	if notif.Priority == pushover.PriorityEmergency {
+		form["retry"] = strconv.FormatFloat(notif.Retry.Seconds(), 'f', -1, 64)
+		form["expire"] = strconv.FormatFloat(notif.Expire.Seconds(), 'f', -1, 64)
+	}
+	if notif.TTL != 0 {
+		form["ttl"] = strconv.FormatFloat(notif.TTL.Seconds(), 'f', -1, 64)
+	}
+	return form
 }
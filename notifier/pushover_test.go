@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidatePushover(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Pushover = make([]models.Pushover, 1)
+
+	// Test valid config
+	c.Alerts.Pushover[0].Token = "abcd"
+	c.Alerts.Pushover[0].Userkey = "abcd"
+	c.Alerts.Pushover[0].Priority = 1
+	result := pushoverNotifier{}.Validate(&c, 0, &c.Alerts.Pushover[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing token
+	c.Alerts.Pushover[0].Token = ""
+	result = pushoverNotifier{}.Validate(&c, 0, &c.Alerts.Pushover[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing Userkey
+	c.Alerts.Pushover[0].Userkey = ""
+	result = pushoverNotifier{}.Validate(&c, 0, &c.Alerts.Pushover[0])
+	expected = 2
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test priority 2 missing retry / expiration config
+	c.Alerts.Pushover[0].Priority = 2
+	result = pushoverNotifier{}.Validate(&c, 0, &c.Alerts.Pushover[0])
+	expected = 4
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test priority 2 with low retry interval
+	c.Alerts.Pushover[0].Retry = 2
+	c.Alerts.Pushover[0].Expire = 10
+	result = pushoverNotifier{}.Validate(&c, 0, &c.Alerts.Pushover[0])
+	expected = 3
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test negative TTL
+	c.Alerts.Pushover[0].TTL = -2
+	result = pushoverNotifier{}.Validate(&c, 0, &c.Alerts.Pushover[0])
+	expected = 4
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
@@ -0,0 +1,323 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/history"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+const (
+	queueBucket      = "queue"
+	deadLetterBucket = "dead_letter"
+)
+
+// queueEntry is the stored form of a queued alert, including the data needed to re-send it
+type queueEntry struct {
+	models.QueueItem
+	Event    models.Event `json:"event"`
+	Snapshot []byte       `json:"snapshot,omitempty"`
+	// Identifies the provider profile to send via, as ProfileID may change when profiles are added or removed
+	ProfileKey string `json:"profile_key,omitempty"`
+}
+
+var (
+	queueWake    = make(chan struct{}, 1)
+	queueOnce    sync.Once
+	inFlight     = make(map[string]bool)
+	inFlightLock sync.Mutex
+)
+
+// StartQueue begins processing the outbound notification queue, if local storage is available
+func StartQueue() {
+	if !storage.Ready() {
+		return
+	}
+	queueOnce.Do(func() {
+		log.Debug().
+			Int("queued", storage.Count(queueBucket)).
+			Int("dead_letter", storage.Count(deadLetterBucket)).
+			Msg("Starting notification queue")
+		go runQueue()
+	})
+}
+
+// queueEnabled returns whether alerts should be sent via the queue
+func queueEnabled() bool {
+	return config.Current().App.Queue.Enabled && storage.Ready()
+}
+
+// enqueueAlert stores alert for delivery, falling back to sending immediately if it cannot be stored
+func enqueueAlert(n config.Notifier, event models.Event, snapshot []byte, provider notifMeta) {
+	profile, err := currentProfile(n, provider.index)
+	var id string
+	if err == nil {
+		id, err = storage.NextID(queueBucket)
+	}
+	if err == nil {
+		now := time.Now()
+		entry := queueEntry{
+			QueueItem: models.QueueItem{
+				ID:          id,
+				Provider:    provider.name,
+				ProfileID:   provider.index,
+				EventID:     event.ID,
+				Camera:      event.Camera,
+				Label:       event.Label,
+				Created:     now,
+				NextAttempt: now,
+				FallbackFor: provider.fallbackFor,
+			},
+			Event:      event,
+			Snapshot:   snapshot,
+			ProfileKey: config.ProfileKey(profile),
+		}
+		err = storage.Put(queueBucket, id, entry)
+	}
+	if err != nil {
+		log.Warn().
+			Str("event_id", event.ID).
+			Str("provider", provider.name).
+			Int("provider_id", provider.index).
+			Err(err).
+			Msg("Unable to queue alert, sending without retry")
+		deliverAlert(n, event, snapshot, provider)
+		return
+	}
+	log.Trace().
+		Str("event_id", event.ID).
+		Str("provider", provider.name).
+		Int("provider_id", provider.index).
+		Str("queue_id", id).
+		Msg("Alert queued")
+	wakeQueue()
+}
+
+// wakeQueue triggers an immediate check for queued alerts
+func wakeQueue() {
+	select {
+	case queueWake <- struct{}{}:
+	default:
+	}
+}
+
+// runQueue periodically delivers any queued alerts that are due
+func runQueue() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		processQueue()
+		select {
+		case <-ticker.C:
+		case <-queueWake:
+		case <-pool.quit:
+			return
+		}
+	}
+}
+
+// processQueue starts delivery of each queued alert that is due & not already in progress
+func processQueue() {
+	now := time.Now()
+	var due []queueEntry
+	err := storage.ForEach(queueBucket, func(key string, value []byte) error {
+		var entry queueEntry
+		if err := json.Unmarshal(value, &entry); err != nil {
+			log.Warn().
+				Str("queue_id", key).
+				Err(err).
+				Msg("Unable to read queued alert")
+			return nil
+		}
+		if entry.NextAttempt.After(now) {
+			return nil
+		}
+		inFlightLock.Lock()
+		defer inFlightLock.Unlock()
+		if !inFlight[entry.ID] {
+			inFlight[entry.ID] = true
+			due = append(due, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Unable to read notification queue")
+	}
+
+	for _, entry := range due {
+		ok := submit(func(ctx context.Context) {
+			deliverQueued(ctx, entry)
+		})
+		// Alert stays queued & will be sent after restart
+		if !ok {
+			inFlightLock.Lock()
+			delete(inFlight, entry.ID)
+			inFlightLock.Unlock()
+		}
+	}
+}
+
+// deliverQueued attempts to send a queued alert, then removes, re-schedules, or dead-letters it
+func deliverQueued(ctx context.Context, entry queueEntry) {
+	defer func() {
+		inFlightLock.Lock()
+		delete(inFlight, entry.ID)
+		inFlightLock.Unlock()
+	}()
+
+	n, ok := config.GetNotifier(entry.Provider)
+	if !ok {
+		deadLetter(entry, fmt.Errorf("unknown notification provider: %v", entry.Provider))
+		return
+	}
+	// Find profile again, in case profiles were added, removed or reordered since alert was queued
+	if entry.ProfileKey != "" {
+		id, ok := config.FindProfileKey(config.Current(), n, entry.ProfileKey)
+		if !ok {
+			deadLetter(entry, fmt.Errorf("notification provider profile no longer configured"))
+			return
+		}
+		entry.ProfileID = id
+	}
+	profiles := n.Profiles(config.Current())
+	// Fallback profiles may be disabled so they only receive failed alerts
+	if entry.ProfileID >= len(profiles) || (!profiles[entry.ProfileID].Common().Enabled && entry.FallbackFor == "") {
+		deadLetter(entry, fmt.Errorf("notification provider profile no longer enabled"))
+		return
+	}
+
+	entry.Attempts++
+	provider := notifMeta{name: entry.Provider, index: entry.ProfileID, fallbackFor: entry.FallbackFor}
+	err := sendAlert(ctx, n, entry.Event, entry.Snapshot, provider)
+	if err != nil && ctx.Err() != nil {
+		// Delivery cancelled during shutdown, so leave alert queued to be sent after restart
+		log.Debug().
+			Str("queue_id", entry.ID).
+			Msg("Queued alert cancelled, will retry after restart")
+		return
+	}
+	if err == nil {
+		if err := storage.Delete(queueBucket, entry.ID); err != nil {
+			log.Warn().
+				Str("queue_id", entry.ID).
+				Err(err).
+				Msg("Unable to remove alert from queue")
+		}
+		return
+	}
+
+	if entry.Attempts >= config.Current().App.Queue.MaxAttempts {
+		deadLetter(entry, err)
+		sendFallback(ctx, entry.Event, entry.Snapshot, provider)
+		return
+	}
+
+	entry.LastError = err.Error()
+	entry.NextAttempt = time.Now().Add(retryDelay(entry.Attempts))
+	setStatus(entry.Event, entry.Provider, entry.ProfileID, history.StatusPending, "Retrying after error: "+err.Error())
+	log.Debug().
+		Str("event_id", entry.EventID).
+		Str("provider", entry.Provider).
+		Int("provider_id", entry.ProfileID).
+		Int("attempts", entry.Attempts).
+		Time("next_attempt", entry.NextAttempt).
+		Msg("Alert scheduled for retry")
+	if err := storage.Put(queueBucket, entry.ID, entry); err != nil {
+		log.Warn().
+			Str("queue_id", entry.ID).
+			Err(err).
+			Msg("Unable to update queued alert")
+	}
+}
+
+// retryDelay returns exponential backoff delay after the specified number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := time.Duration(config.Current().App.Queue.InitialDelay) * time.Second
+	max := time.Duration(config.Current().App.Queue.MaxDelay) * time.Second
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
+
+// deadLetter moves a queued alert to the dead letter queue
+func deadLetter(entry queueEntry, reason error) {
+	entry.LastError = reason.Error()
+	log.Error().
+		Str("event_id", entry.EventID).
+		Str("provider", entry.Provider).
+		Int("provider_id", entry.ProfileID).
+		Int("attempts", entry.Attempts).
+		Err(reason).
+		Msg("Alert moved to dead letter queue")
+	if err := storage.Move(queueBucket, deadLetterBucket, entry.ID, entry); err != nil {
+		log.Warn().
+			Str("queue_id", entry.ID).
+			Err(err).
+			Msg("Unable to move alert to dead letter queue")
+	}
+}
+
+// QueuedItems returns all alerts waiting for delivery
+func QueuedItems() ([]models.QueueItem, error) {
+	return listItems(queueBucket)
+}
+
+// DeadLetterItems returns all alerts that could not be delivered
+func DeadLetterItems() ([]models.QueueItem, error) {
+	return listItems(deadLetterBucket)
+}
+
+func listItems(bucket string) ([]models.QueueItem, error) {
+	items := []models.QueueItem{}
+	err := storage.ForEach(bucket, func(key string, value []byte) error {
+		var entry queueEntry
+		if err := json.Unmarshal(value, &entry); err != nil {
+			return err
+		}
+		items = append(items, entry.QueueItem)
+		return nil
+	})
+	return items, err
+}
+
+// RetryDeadLetter moves an alert from the dead letter queue back to the queue for immediate delivery
+func RetryDeadLetter(id string) (bool, error) {
+	var entry queueEntry
+	ok, err := storage.Get(deadLetterBucket, id, &entry)
+	if !ok || err != nil {
+		return ok, err
+	}
+	entry.Attempts = 0
+	entry.NextAttempt = time.Now()
+	if err := storage.Move(deadLetterBucket, queueBucket, id, entry); err != nil {
+		return true, err
+	}
+	log.Info().
+		Str("event_id", entry.EventID).
+		Str("provider", entry.Provider).
+		Int("provider_id", entry.ProfileID).
+		Msg("Retrying alert from dead letter queue")
+	wakeQueue()
+	return true, nil
+}
+
+// DeleteDeadLetter removes an alert from the dead letter queue
+func DeleteDeadLetter(id string) (bool, error) {
+	var entry queueEntry
+	ok, err := storage.Get(deadLetterBucket, id, &entry)
+	if !ok || err != nil {
+		return ok, err
+	}
+	return true, storage.Delete(deadLetterBucket, id)
+}
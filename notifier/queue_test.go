@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+func TestRetryDelay(t *testing.T) {
+	defer config.Update(func(c *config.Config) {
+		c.App.Queue.InitialDelay = 5
+		c.App.Queue.MaxDelay = 60
+	})()
+
+	tests := map[int]time.Duration{
+		1:  5 * time.Second,
+		2:  10 * time.Second,
+		4:  40 * time.Second,
+		5:  60 * time.Second,
+		50: 60 * time.Second,
+	}
+	for attempts, expected := range tests {
+		if result := retryDelay(attempts); result != expected {
+			t.Errorf("Attempts: %v, Expected: %v, Got: %v", attempts, expected, result)
+		}
+	}
+}
+
+func TestQueuedProfileChanged(t *testing.T) {
+	// Setup
+	if err := storage.Open(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Unable to open data store: %v", err)
+	}
+	defer storage.Close()
+	received := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.Path
+	}))
+	defer server.Close()
+	first := models.Webhook{AlertCommon: models.AlertCommon{Enabled: true, Name: "first"}, Server: server.URL + "/first", Method: "POST"}
+	second := models.Webhook{AlertCommon: models.AlertCommon{Enabled: true, Name: "second"}, Server: server.URL + "/second", Method: "POST"}
+	defer config.Update(func(c *config.Config) {
+		c.App.Queue = models.Queue{Enabled: true, MaxAttempts: 1}
+		c.Alerts.Webhook = []models.Webhook{first, second}
+	})()
+	event := models.Event{ID: "event-1"}
+
+	nextQueued := func() queueEntry {
+		t.Helper()
+		items, _ := QueuedItems()
+		if len(items) != 1 {
+			t.Fatalf("Expected: 1 queued alert, Got: %v", items)
+		}
+		var entry queueEntry
+		storage.Get(queueBucket, items[0].ID, &entry)
+		return entry
+	}
+
+	// Queued alert is sent via the same profile after earlier profiles are removed
+	enqueueAlert(webhookNotifier{}, event, nil, notifMeta{name: "webhook", index: 1})
+	entry := nextQueued()
+	defer config.Update(func(c *config.Config) { c.Alerts.Webhook = []models.Webhook{second} })()
+	deliverQueued(context.Background(), entry)
+	select {
+	case path := <-received:
+		if path != "/second" {
+			t.Errorf("Expected: sent via second profile, Got: %v", path)
+		}
+	default:
+		t.Error("Expected: queued alert sent")
+	}
+
+	// Queued alert for a removed profile is dead-lettered instead of sent via another profile
+	enqueueAlert(webhookNotifier{}, event, nil, notifMeta{name: "webhook", index: 0})
+	entry = nextQueued()
+	defer config.Update(func(c *config.Config) { c.Alerts.Webhook = []models.Webhook{first} })()
+	deliverQueued(context.Background(), entry)
+	select {
+	case path := <-received:
+		t.Errorf("Expected: no alert sent, Got: %v", path)
+	default:
+	}
+	if items, _ := DeadLetterItems(); len(items) != 1 {
+		t.Errorf("Expected: 1 dead letter alert, Got: %v", items)
+	}
+}
@@ -1,10 +1,13 @@
 package notifier
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"regexp"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 
@@ -20,12 +23,50 @@ type SignalPayload struct {
 	Attachments []string `json:"base64_attachments"`
 }
 
-// SendSignalMessage pushes alert message to Signal via webhook
-func SendSignalMessage(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Signal[provider.index]
-	status := &config.Internal.Status.Notifications.Signal[provider.index]
+type signalNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(signalNotifier{})
+}
+
+func (signalNotifier) Name() string {
+	return "signal"
+}
+
+func (signalNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Signal)
+}
+
+func (signalNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Signal)
+	var signalErrors []string
+	log.Debug().Msgf("Alerting enabled for Signal profile ID %v", id)
+	if profile.Server == "" {
+		signalErrors = append(signalErrors, fmt.Sprintf("No Signal server specified! Profile ID %v", id))
+	}
+	if profile.Account == "" {
+		signalErrors = append(signalErrors, fmt.Sprintf("No Signal account specified! Profile ID %v", id))
+	}
+	// Check if Signal server URL contains protocol, assume HTTP if not specified
+	if !strings.Contains(profile.Server, "http://") && !strings.Contains(profile.Server, "https://") {
+		log.Debug().Msgf("No protocol specified on Signal Server. Assuming http://. If this is incorrect, please adjust the config file. Profile ID %v", id)
+		profile.Server = fmt.Sprintf("http://%s", profile.Server)
+	}
+	// Check recipients list
+	if len(profile.Recipients) == 0 {
+		log.Debug().Msgf("No message recipients configured for Signal. Profile ID %v", id)
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Signal", profile.Template); msg != "" {
+		signalErrors = append(signalErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+	return signalErrors
+}
+
+// Send pushes alert message to Signal via REST API
+func (signalNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.Signal)
 
-	var err error
 	var message string
 	// Build notification
 	if profile.Template != "" {
@@ -46,12 +87,21 @@ func SendSignalMessage(event models.Event, snapshot io.Reader, provider notifMet
 	}
 
 	// Check if sending account has + prefix if needed
-	if r.Match([]byte(profile.Account)) {
-		profile.Account = "+" + profile.Account
+	account := profile.Account
+	if r.Match([]byte(account)) {
+		account = "+" + account
 	}
 
 	// Build payload
-	payload := SignalPayload{Message: message, Number: profile.Account, Recipients: recipients}
+	payload := SignalPayload{Message: message, Number: account, Recipients: recipients}
+	url := profile.Server + "/v2/send"
+	request := dryRunRequest{Method: "POST", URL: url, Body: payload}
+	if event.HasSnapshot {
+		request.Files = []string{"snapshot.jpg"}
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
 	if event.HasSnapshot {
 		img, _ := io.ReadAll(snapshot)
 		attach := base64.StdEncoding.EncodeToString(img)
@@ -60,33 +110,9 @@ func SendSignalMessage(event models.Event, snapshot io.Reader, provider notifMet
 
 	data, err := json.Marshal(payload)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Signal").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 
-	url := profile.Server + "/v2/send"
-	_, err = util.HTTPPost(url, profile.Insecure, []byte(data), "")
-
-	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Signal").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
-	}
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Signal").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+	_, err = util.HTTPPost(ctx, url, profile.Insecure, []byte(data), "")
+	return err
 }
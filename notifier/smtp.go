@@ -1,8 +1,12 @@
 package notifier
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,16 +14,72 @@ import (
 
 	"github.com/0x2142/frigate-notify/config"
 	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/util"
 	"github.com/wneessen/go-mail"
 )
 
 var threads map[string]string
 var date string
 
-// SendSMTP forwards alert data via email
-func SendSMTP(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.SMTP[provider.index]
-	status := &config.Internal.Status.Notifications.SMTP[provider.index]
+type smtpNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(smtpNotifier{})
+}
+
+func (smtpNotifier) Name() string {
+	return "smtp"
+}
+
+func (smtpNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.SMTP)
+}
+
+func (smtpNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.SMTP)
+	var smtpErrors []string
+	authTypes := []string{"plain", "plain-noenc", "login", "login-noenc", "noauth", "cram-md5", "xoauth2", "scram-sha-1", "scram-sha-1-plus", "scram-sha-256", "scram-sha-256-plus", "autodiscover"}
+	validThreading := []string{"day", "camera", "zone"}
+	log.Debug().Msgf("Alerting enabled for SMTP profile ID %v", id)
+	if profile.Server == "" {
+		smtpErrors = append(smtpErrors, fmt.Sprintf("No SMTP server specified! Profile ID %v", id))
+	}
+	if profile.Recipient == "" {
+		smtpErrors = append(smtpErrors, fmt.Sprintf("No SMTP recipients specified! Profile ID %v", id))
+	}
+	if profile.AuthType == "" {
+		profile.AuthType = "plain"
+	}
+	if !slices.Contains(authTypes, strings.ToLower(profile.AuthType)) {
+		smtpErrors = append(smtpErrors, fmt.Sprintf("Invalid SMTP Authentication type. Profile ID %v", id))
+	}
+	if profile.User != "" && profile.Password == "" {
+		smtpErrors = append(smtpErrors, fmt.Sprintf("SMTP username in config, but no password provided! Profile ID %v", id))
+	}
+	if profile.Port == 0 {
+		profile.Port = 25
+	}
+	if profile.Thread == "" {
+		profile.Thread = "day"
+	}
+	if !slices.Contains(validThreading, profile.Thread) {
+		smtpErrors = append(smtpErrors, fmt.Sprintf("SMTP threading must be `day` or `camera`. Profile ID %v", id))
+	}
+	// Copy `user` to `from` if `from` not explicitly configured
+	if profile.From == "" && profile.User != "" {
+		profile.From = profile.User
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("SMTP", profile.Template); msg != "" {
+		smtpErrors = append(smtpErrors, msg+fmt.Sprintf(" Profile ID %v", id))
+	}
+
+	return smtpErrors
+}
+
+// Send forwards alert data via email
+func (smtpNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.SMTP)
 
 	// Check if new day & need to roll over email threading
 	currentDate := time.Now().Local().Format("20060102")
@@ -52,7 +112,7 @@ func SendSMTP(event models.Event, snapshot io.Reader, provider notifMeta) {
 	if profile.Title != "" {
 		title = renderMessage(profile.Title, event, "title", "smtp")
 	} else {
-		title = renderMessage(config.ConfigData.Alerts.General.Title, event, "title", "smtp")
+		title = renderMessage(config.Current().Alerts.General.Title, event, "title", "smtp")
 	}
 	m.Subject(title)
 	m.SetMessageID()
@@ -79,18 +139,37 @@ func SendSMTP(event models.Event, snapshot io.Reader, provider notifMeta) {
 		m.SetGenHeader(mail.HeaderReferences, msgID)
 	}
 
+	// Convert message body to HTML
+	m.SetBodyString(mail.TypeTextHTML, message)
+
+	if config.IsDryRun() {
+		// Snapshot is attached afterwards, so it isn't included in the logged email
+		var email bytes.Buffer
+		if _, err := m.WriteTo(&email); err != nil {
+			return err
+		}
+		request := dryRunRequest{URL: fmt.Sprintf("smtp://%s:%d", profile.Server, profile.Port), Body: email.String()}
+		if event.HasSnapshot {
+			request.Files = []string{"snapshot.jpg"}
+		}
+		dryRun(ctx, event, request)
+		return nil
+	}
+
 	// Attach snapshot if one exists
 	if event.HasSnapshot {
 		m.AttachReader("snapshot.jpg", snapshot)
 	}
 
-	// Convert message body to HTML
-	m.SetBodyString(mail.TypeTextHTML, message)
-
-	time.Sleep(5 * time.Second)
+	if !util.Wait(ctx, 5*time.Second) {
+		return ctx.Err()
+	}
 
 	// Set up SMTP Connection
 	c, err := mail.NewClient(profile.Server, mail.WithPort(profile.Port))
+	if err != nil {
+		return err
+	}
 	// Add authentication params if needed
 	if profile.User != "" && profile.Password != "" {
 		c.SetUsername(profile.User)
@@ -133,16 +212,6 @@ func SendSMTP(event models.Event, snapshot io.Reader, provider notifMeta) {
 		c.SetTLSConfig(&tls.Config{InsecureSkipVerify: true})
 	}
 
-	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "SMTP").
-			Err(err).
-			Int("provider_id", provider.index).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-	}
-
 	log.Trace().
 		Strs("sender", m.GetFromString()).
 		Strs("recipients", m.GetToString()).
@@ -153,26 +222,10 @@ func SendSMTP(event models.Event, snapshot io.Reader, provider notifMeta) {
 		Bool("tls", profile.TLS).
 		Str("username", profile.User).
 		Str("password", "--secret removed--").
-		Int("provider_id", provider.index).
 		Msg("Send SMTP Alert")
 
 	// Send message
-	if err := c.DialAndSend(m); err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "SMTP").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
-	}
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "SMTP").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+	return c.DialAndSendWithContext(ctx, m)
 }
 
 // ParseSMTPRecipients splits individual email addresses from config file
@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateSMTP(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.SMTP = make([]models.SMTP, 1)
+
+	// Test valid config
+	c.Alerts.SMTP[0].Server = "192.0.2.10"
+	c.Alerts.SMTP[0].Recipient = "someone@none.test"
+	c.Alerts.SMTP[0].User = "someuser"
+	c.Alerts.SMTP[0].Password = "abcd"
+	result := smtpNotifier{}.Validate(&c, 0, &c.Alerts.SMTP[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Check Default port set
+	if c.Alerts.SMTP[0].Port != 25 {
+		t.Errorf("Expected: port 25 , Got: %v", c.Alerts.SMTP[0].Port)
+	}
+
+	// Check SMTP From is copied
+	if c.Alerts.SMTP[0].User != c.Alerts.SMTP[0].From {
+		t.Errorf("Expected: %v, Got: %v", c.Alerts.SMTP[0].User, c.Alerts.SMTP[0].From)
+	}
+
+	// Test missing server
+	c.Alerts.SMTP[0].Server = ""
+	result = smtpNotifier{}.Validate(&c, 0, &c.Alerts.SMTP[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing recipient
+	c.Alerts.SMTP[0].Recipient = ""
+	result = smtpNotifier{}.Validate(&c, 0, &c.Alerts.SMTP[0])
+	expected = 2
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test invalid auth config
+	c.Alerts.SMTP[0].Password = ""
+	result = smtpNotifier{}.Validate(&c, 0, &c.Alerts.SMTP[0])
+	expected = 3
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
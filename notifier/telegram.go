@@ -1,7 +1,12 @@
 package notifier
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"net/http"
+	"path"
+	"strconv"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -11,43 +16,66 @@ import (
 	tgbotapi "github.com/OvyFlash/telegram-bot-api"
 )
 
-// SendTelegramMessage sends alert through Telegram to individual users
-func SendTelegramMessage(event models.Event, snapshot io.Reader, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Telegram[provider.index]
-	status := &config.Internal.Status.Notifications.Telegram[provider.index]
+type telegramNotifier struct{}
 
-	// Build notification
-	var message string
-	if profile.Template != "" {
-		message = renderMessage(profile.Template, event, "message", "Telegram")
-	} else {
-		message = renderMessage("html", event, "message", "Telegram")
-		message = strings.ReplaceAll(message, "<br />", "")
+func init() {
+	config.RegisterNotifier(telegramNotifier{})
+}
+
+func (telegramNotifier) Name() string {
+	return "telegram"
+}
+
+func (telegramNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Telegram)
+}
+
+func (telegramNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Telegram)
+	var telegramErrors []string
+	log.Debug().Msgf("Alerting enabled for Telegram profile ID %v", id)
+	if profile.ChatID == 0 {
+		telegramErrors = append(telegramErrors, fmt.Sprintf("No Telegram Chat ID specified! Profile ID %v", id))
+	}
+	if profile.Token == "" {
+		telegramErrors = append(telegramErrors, fmt.Sprintf("No Telegram bot token specified! Profile ID %v", id))
+	}
+	// Check template syntax
+	if msg := config.ValidateTemplate("Telegram", profile.Template); msg != "" {
+		telegramErrors = append(telegramErrors, msg+fmt.Sprintf(" Profile ID %v", id))
 	}
+	return telegramErrors
+}
 
-	bot, err := tgbotapi.NewBotAPI(profile.Token)
-	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Telegram").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
+// Send sends alert through Telegram to individual users
+func (t telegramNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	_, err := t.SendMessage(ctx, event, snapshot, p)
+	return err
+}
+
+// SendMessage sends alert through Telegram & returns the message type & ID, ex: "photo:123"
+func (telegramNotifier) SendMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) (string, error) {
+	profile := p.(*models.Telegram)
+
+	// Build notification
+	message := telegramMessage(event, profile)
 
-		return
+	bot, err := telegramBot(ctx, event, profile.Token)
+	if err != nil {
+		return "", err
 	}
 
 	// Collect event clip if available & configured
 	var clip io.Reader
 	if event.HasClip && profile.SendClip {
-		clip = GetClip(event)
+		clip = telegramClip(ctx, event)
 		if clip == nil {
 			event.HasClip = false
 		}
 	}
 
 	var response tgbotapi.Message
+	var msgType string
 	if event.HasClip && profile.SendClip {
 		msg := tgbotapi.NewVideo(profile.ChatID, tgbotapi.FileReader{Name: "Clip", Reader: clip})
 		if profile.MessageThreadID != 0 {
@@ -55,6 +83,7 @@ func SendTelegramMessage(event models.Event, snapshot io.Reader, provider notifM
 		}
 		msg.Caption = message
 		msg.ParseMode = "HTML"
+		msgType = "video"
 		response, err = bot.Send(msg)
 	} else if event.HasSnapshot {
 		// Attach & send snapshot
@@ -64,6 +93,7 @@ func SendTelegramMessage(event models.Event, snapshot io.Reader, provider notifM
 		}
 		msg.Caption = message
 		msg.ParseMode = "HTML"
+		msgType = "photo"
 		response, err = bot.Send(msg)
 	} else {
 		// Send plain text message if no snapshot available
@@ -72,27 +102,170 @@ func SendTelegramMessage(event models.Event, snapshot io.Reader, provider notifM
 			msg.MessageThreadID = profile.MessageThreadID
 		}
 		msg.ParseMode = "HTML"
+		msgType = "text"
 		response, err = bot.Send(msg)
 	}
 	log.Trace().
 		Interface("content", response).
-		Int("provider_id", provider.index).
 		Msg("Send Telegram Alert")
+	if err != nil || config.IsDryRun() {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d", msgType, response.MessageID), nil
+}
+
+// EditMessage updates a previously sent Telegram message with new event details & snapshot
+func (telegramNotifier) EditMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile, messageID string) error {
+	profile := p.(*models.Telegram)
+
+	msgType, rawID, _ := strings.Cut(messageID, ":")
+	msgID, err := strconv.Atoi(rawID)
+	if err != nil {
+		return fmt.Errorf("invalid Telegram message ID: %v", messageID)
+	}
+
+	message := telegramMessage(event, profile)
+
+	bot, err := telegramBot(ctx, event, profile.Token)
+	if err != nil {
+		return err
+	}
+
+	var response tgbotapi.Message
+	switch {
+	case msgType == "photo" && event.HasSnapshot:
+		// Replace snapshot & caption
+		photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileReader{Name: "Snapshot", Reader: snapshot})
+		photo.Caption = message
+		photo.ParseMode = "HTML"
+		response, err = bot.Send(tgbotapi.NewEditMessagePhoto(profile.ChatID, msgID, photo))
+	case msgType == "photo" || msgType == "video":
+		msg := tgbotapi.NewEditMessageCaption(profile.ChatID, msgID, message)
+		msg.ParseMode = "HTML"
+		response, err = bot.Send(msg)
+	default:
+		msg := tgbotapi.NewEditMessageText(profile.ChatID, msgID, message)
+		msg.ParseMode = "HTML"
+		response, err = bot.Send(msg)
+	}
+	log.Trace().
+		Interface("content", response).
+		Msg("Edit Telegram Alert")
+	return err
+}
+
+// ReplyMessage sends follow-up with event clip through Telegram as a reply to a previously sent alert
+func (telegramNotifier) ReplyMessage(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile, messageID string) error {
+	profile := p.(*models.Telegram)
+
+	_, rawID, _ := strings.Cut(messageID, ":")
+	msgID, err := strconv.Atoi(rawID)
+	if err != nil {
+		return fmt.Errorf("invalid Telegram message ID: %v", messageID)
+	}
+
+	message := telegramMessage(event, profile)
+
+	bot, err := telegramBot(ctx, event, profile.Token)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Telegram").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
-	}
-
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Telegram").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+		return err
+	}
+
+	// Collect event clip if available & enabled for this profile
+	var clip io.Reader
+	if event.HasClip && profile.SendClip {
+		clip = telegramClip(ctx, event)
+	}
+
+	reply := tgbotapi.ReplyParameters{MessageID: msgID, AllowSendingWithoutReply: true}
+	var response tgbotapi.Message
+	if clip != nil {
+		msg := tgbotapi.NewVideo(profile.ChatID, tgbotapi.FileReader{Name: "Clip", Reader: clip})
+		msg.MessageThreadID = profile.MessageThreadID
+		msg.ReplyParameters = reply
+		msg.Caption = message
+		msg.ParseMode = "HTML"
+		response, err = bot.Send(msg)
+	} else if event.HasSnapshot {
+		msg := tgbotapi.NewPhoto(profile.ChatID, tgbotapi.FileReader{Name: "Snapshot", Reader: snapshot})
+		msg.MessageThreadID = profile.MessageThreadID
+		msg.ReplyParameters = reply
+		msg.Caption = message
+		msg.ParseMode = "HTML"
+		response, err = bot.Send(msg)
+	} else {
+		msg := tgbotapi.NewMessage(profile.ChatID, message)
+		msg.MessageThreadID = profile.MessageThreadID
+		msg.ReplyParameters = reply
+		msg.ParseMode = "HTML"
+		response, err = bot.Send(msg)
+	}
+	log.Trace().
+		Interface("content", response).
+		Msg("Send Telegram Follow-up")
+	return err
+}
+
+// telegramMessage renders Telegram notification text
+func telegramMessage(event models.Event, profile *models.Telegram) string {
+	if profile.Template != "" {
+		return renderMessage(profile.Template, event, "message", "Telegram")
+	}
+	message := renderMessage("html", event, "message", "Telegram")
+	return strings.ReplaceAll(message, "<br />", "")
+}
+
+// telegramBot returns a Telegram bot API client. In dry run mode, the client logs requests instead of sending them
+func telegramBot(ctx context.Context, event models.Event, token string) (*tgbotapi.BotAPI, error) {
+	if !config.IsDryRun() {
+		return tgbotapi.NewBotAPI(token)
+	}
+	return tgbotapi.NewBotAPIWithClient(config.SecretRedacted, tgbotapi.APIEndpoint, telegramDryRun{ctx: ctx, event: event})
+}
+
+// telegramClip returns the event clip to send. Clips aren't downloaded in dry run mode, so an empty clip is used
+func telegramClip(ctx context.Context, event models.Event) io.Reader {
+	if config.IsDryRun() {
+		return http.NoBody
+	}
+	return GetClip(ctx, event)
+}
+
+// telegramDryRun is a Telegram API HTTP client for dry run mode, which logs requests & responds as if they succeeded
+type telegramDryRun struct {
+	ctx   context.Context
+	event models.Event
+}
+
+func (t telegramDryRun) Do(req *http.Request) (*http.Response, error) {
+	defer req.Body.Close()
+	response := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"ok":true,"result":{}}`))}
+	// Bot details are requested when the client is created
+	if path.Base(req.URL.Path) == "getMe" {
+		return response, nil
+	}
+
+	request := dryRunRequest{Method: req.Method, URL: req.URL.String()}
+	fields := make(map[string]string)
+	if form, err := req.MultipartReader(); err == nil {
+		for {
+			part, err := form.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FileName() != "" {
+				request.Files = append(request.Files, part.FileName())
+				continue
+			}
+			value, _ := io.ReadAll(part)
+			fields[part.FormName()] = string(value)
+		}
+	} else if err := req.ParseForm(); err == nil {
+		for k := range req.PostForm {
+			fields[k] = req.PostForm.Get(k)
+		}
+	}
+	request.Body = fields
+	dryRun(t.ctx, t.event, request)
+	return response, nil
 }
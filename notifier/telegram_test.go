@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateTelegram(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Telegram = make([]models.Telegram, 1)
+
+	// Test valid config
+	c.Alerts.Telegram[0].ChatID = 1234
+	c.Alerts.Telegram[0].Token = "abcd"
+	result := telegramNotifier{}.Validate(&c, 0, &c.Alerts.Telegram[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing Chat ID
+	c.Alerts.Telegram[0].ChatID = 0
+	result = telegramNotifier{}.Validate(&c, 0, &c.Alerts.Telegram[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing Token
+	c.Alerts.Telegram[0].Token = ""
+	result = telegramNotifier{}.Validate(&c, 0, &c.Alerts.Telegram[0])
+	expected = 2
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+}
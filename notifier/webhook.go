@@ -1,7 +1,9 @@
 package notifier
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/disgoorg/json"
@@ -31,85 +33,111 @@ type WebhookPayload struct {
 		Review string `json:"review,omitempty"`
 		Snap   string `json:"snapshot,omitempty"`
 	} `json:"links"`
+	Digest *models.DigestSummary `json:"digest,omitempty"`
+	Manual *models.ManualAlert   `json:"manual,omitempty"`
 }
 
-// SendWebhook sends alert through HTTP POST to target webhook
-func SendWebhook(event models.Event, provider notifMeta) {
-	profile := config.ConfigData.Alerts.Webhook[provider.index]
-	status := &config.Internal.Status.Notifications.Webhook[provider.index]
+type webhookNotifier struct{}
+
+func init() {
+	config.RegisterNotifier(webhookNotifier{})
+}
+
+func (webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (webhookNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Webhook)
+}
+
+func (webhookNotifier) Validate(c *config.Config, id int, p config.Profile) []string {
+	profile := p.(*models.Webhook)
+	var webhookErrors []string
+	log.Debug().Msgf("Alerting enabled for Webhook profile ID %v", id)
+	if profile.Server == "" {
+		webhookErrors = append(webhookErrors, fmt.Sprintf("No Webhook server specified! Profile ID %v", id))
+	}
+	// Check HTTP header template syntax
+	if msg := config.ValidateTemplate("Webhook HTTP Headers", c.Alerts.General.Title); msg != "" {
+		webhookErrors = append(webhookErrors, msg+fmt.Sprintf("Profile ID %v", id))
+	}
+
+	return webhookErrors
+}
+
+// Send sends alert through HTTP POST to target webhook
+func (webhookNotifier) Send(ctx context.Context, event models.Event, snapshot io.Reader, p config.Profile) error {
+	profile := p.(*models.Webhook)
 
 	// Build notification
 	var message string
 	payload, err := json.Marshal(profile.Template)
 	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Webhook").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+		return err
 	}
 	if string(payload) != "null" {
 		message = renderMessage(string(payload), event, "message", "Webhook")
 	} else {
-		defaultTemplate := WebhookPayload{
-			Time:         event.Extra.FormattedTime,
-			ID:           event.ID,
-			Camera:       event.Extra.CameraName,
-			Label:        event.Label,
-			LicensePlate: event.Data.RecognizedLicensePlate,
-			SubLabel:     event.SubLabel,
-			Score:        event.Extra.TopScorePercent,
-			Audio:        event.Extra.Audio,
-			CurrentZones: event.CurrentZones,
-			EnteredZones: event.EnteredZones,
-			HasClip:      event.HasClip,
-			HasSnap:      event.HasSnapshot,
-		}
-		if event.Extra.FrigateMajorVersion >= 14 {
-			defaultTemplate.Links.Camera = fmt.Sprintf("%s/#%s", event.Extra.PublicURL, event.Camera)
-		} else {
-			defaultTemplate.Links.Camera = fmt.Sprintf("%s/cameras/%s", event.Extra.PublicURL, event.Camera)
-		}
-		if event.HasClip {
-			defaultTemplate.Links.Clip = event.Extra.EventLink
-		}
-		if event.HasSnapshot {
-			defaultTemplate.Links.Snap = fmt.Sprintf("%s/api/events/%s/snapshot.jpg", event.Extra.PublicURL, event.ID)
-		}
-		if event.Extra.ReviewLink != "" {
-			defaultTemplate.Links.Review = event.Extra.ReviewLink
-		}
-		payload, _ = json.Marshal(defaultTemplate)
+		payload, _ = json.Marshal(webhookPayload(event))
 		message = string(payload)
 	}
 
 	headers := renderHTTPKV(profile.Headers, event, "headers", "Webhook")
 	params := renderHTTPKV(profile.Params, event, "params", "Webhook")
 	paramString := util.BuildHTTPParams(params...)
+
+	request := dryRunRequest{Method: strings.ToUpper(profile.Method), URL: profile.Server + paramString, Headers: headers}
+	if request.Method != "GET" {
+		request.Method = "POST"
+		request.Body = []byte(message)
+	}
+	if dryRun(ctx, event, request) {
+		return nil
+	}
+
 	if strings.ToUpper(profile.Method) == "GET" {
-		_, err = util.HTTPGet(profile.Server, profile.Insecure, paramString, headers...)
+		_, err = util.HTTPGet(ctx, profile.Server, profile.Insecure, paramString, headers...)
 
 	} else {
-		_, err = util.HTTPPost(profile.Server, profile.Insecure, []byte(message), paramString, headers...)
+		_, err = util.HTTPPost(ctx, profile.Server, profile.Insecure, []byte(message), paramString, headers...)
 	}
 
-	if err != nil {
-		log.Warn().
-			Str("event_id", event.ID).
-			Str("provider", "Webhook").
-			Int("provider_id", provider.index).
-			Err(err).
-			Msg("Unable to send alert")
-		status.NotifFailure(err.Error())
-		return
+	return err
+}
+
+// webhookPayload builds the default webhook payload, used if no custom template is set
+func webhookPayload(event models.Event) WebhookPayload {
+	defaultTemplate := WebhookPayload{
+		Time:         event.Extra.FormattedTime,
+		ID:           event.ID,
+		Camera:       event.Extra.CameraName,
+		Label:        event.Label,
+		LicensePlate: event.Data.RecognizedLicensePlate,
+		SubLabel:     event.SubLabel,
+		Score:        event.Extra.TopScorePercent,
+		Audio:        event.Extra.Audio,
+		CurrentZones: event.CurrentZones,
+		EnteredZones: event.EnteredZones,
+		HasClip:      event.HasClip,
+		HasSnap:      event.HasSnapshot,
+		Digest:       event.Extra.Digest,
+		Manual:       event.Extra.Manual,
+	}
+	if event.Extra.FrigateMajorVersion >= 14 {
+		defaultTemplate.Links.Camera = fmt.Sprintf("%s/#%s", event.Extra.PublicURL, event.Camera)
+	} else {
+		defaultTemplate.Links.Camera = fmt.Sprintf("%s/cameras/%s", event.Extra.PublicURL, event.Camera)
+	}
+	if event.HasClip {
+		defaultTemplate.Links.Clip = event.Extra.EventLink
+	}
+	// Manual alert snapshots are not stored in Frigate
+	if event.HasSnapshot && event.Extra.Manual == nil {
+		defaultTemplate.Links.Snap = fmt.Sprintf("%s/api/events/%s/snapshot.jpg", event.Extra.PublicURL, event.ID)
+	}
+	if event.Extra.ReviewLink != "" {
+		defaultTemplate.Links.Review = event.Extra.ReviewLink
 	}
-	log.Info().
-		Str("event_id", event.ID).
-		Str("provider", "Webhook").
-		Int("provider_id", provider.index).
-		Msg("Alert sent")
-	status.NotifSuccess()
+	return defaultTemplate
 }
@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestValidateWebhook(t *testing.T) {
+	c := config.Config{Alerts: models.Alerts{}}
+	c.Alerts.Webhook = make([]models.Webhook, 1)
+
+	// Test valid config
+	c.Alerts.Webhook[0].Server = "https://webhook.test"
+	result := webhookNotifier{}.Validate(&c, 0, &c.Alerts.Webhook[0])
+	expected := 0
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+	// Test missing server config
+	c.Alerts.Webhook[0].Server = ""
+	result = webhookNotifier{}.Validate(&c, 0, &c.Alerts.Webhook[0])
+	expected = 1
+	if len(result) != expected {
+		t.Errorf("Expected: %v error(s), Got: %v", expected, result)
+	}
+
+}
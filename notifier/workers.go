@@ -0,0 +1,153 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+)
+
+// Default number of alert delivery workers, if not configured
+const defaultWorkers = 10
+
+// Number of alerts that can be waiting for a free worker before new alerts are blocked
+const jobBuffer = 100
+
+// job is a single alert delivery, run by a worker
+type job func(ctx context.Context)
+
+// workerPool limits the number of alerts delivered at the same time
+type workerPool struct {
+	jobs    chan job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	lock    sync.RWMutex
+	stopped bool
+	quit    chan struct{}
+	quitOne sync.Once
+}
+
+var pool = newWorkerPool()
+
+func newWorkerPool() *workerPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerPool{ctx: ctx, cancel: cancel, quit: make(chan struct{})}
+}
+
+// StartWorkers starts the pool of workers used to deliver alerts
+func StartWorkers() {
+	count := config.Current().App.Delivery.Workers
+	if count <= 0 {
+		count = defaultWorkers
+	}
+	log.Debug().
+		Int("workers", count).
+		Msg("Starting alert delivery workers")
+	pool.start(count)
+}
+
+// Shutdown stops accepting new alerts & waits for pending alerts to be delivered.
+// Any alerts still in progress after timeout are cancelled
+func Shutdown(timeout time.Duration) {
+	log.Info().
+		Dur("timeout", timeout).
+		Msg("Waiting for pending alerts to be delivered...")
+	if pool.stop(timeout) {
+		log.Info().Msg("All pending alerts delivered")
+	} else {
+		log.Warn().Msg("Timed out waiting for pending alerts, remaining deliveries cancelled")
+	}
+}
+
+// appContext returns the context used for outbound requests, which is cancelled if shutdown times out
+func appContext() context.Context {
+	return pool.ctx
+}
+
+// submit hands an alert delivery to the worker pool & returns false if the app is shutting down
+func submit(j job) bool {
+	return pool.submit(j)
+}
+
+func (p *workerPool) start(count int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.jobs != nil || p.stopped {
+		return
+	}
+	p.jobs = make(chan job, jobBuffer)
+	for range count {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for j := range p.jobs {
+				j(p.ctx)
+			}
+		}()
+	}
+}
+
+// submit queues a job for the next free worker, blocking if too many jobs are waiting
+func (p *workerPool) submit(j job) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if p.stopped {
+		return false
+	}
+	// Workers may not be running yet, such as during startup
+	if p.jobs == nil {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			j(p.ctx)
+		}()
+		return true
+	}
+	// Stop signals quit before waiting for lock, so submitting to a full buffer cannot block shutdown
+	select {
+	case p.jobs <- j:
+		return true
+	case <-p.quit:
+		return false
+	}
+}
+
+// stop rejects new jobs & waits for pending jobs to finish, cancelling them after timeout.
+// Returns false if timeout was reached
+func (p *workerPool) stop(timeout time.Duration) bool {
+	p.quitOne.Do(func() { close(p.quit) })
+	p.lock.Lock()
+	if p.stopped {
+		p.lock.Unlock()
+		return true
+	}
+	p.stopped = true
+	if p.jobs != nil {
+		close(p.jobs)
+	}
+	p.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		p.cancel()
+	}
+
+	// Give cancelled jobs a moment to finish logging results
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	p := newWorkerPool()
+	p.start(2)
+
+	var lock sync.Mutex
+	var running, maxRunning int
+	var completed atomic.Int32
+	for range 6 {
+		p.submit(func(ctx context.Context) {
+			lock.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			lock.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			lock.Lock()
+			running--
+			lock.Unlock()
+			completed.Add(1)
+		})
+	}
+
+	// Pending jobs are finished before stopping
+	if !p.stop(time.Second) {
+		t.Error("Expected: all jobs finished before timeout")
+	}
+	if completed.Load() != 6 {
+		t.Errorf("Expected: 6 jobs completed, Got: %v", completed.Load())
+	}
+	if maxRunning > 2 {
+		t.Errorf("Expected: at most 2 jobs running at once, Got: %v", maxRunning)
+	}
+
+	// New jobs rejected after stopping
+	if p.submit(func(ctx context.Context) {}) {
+		t.Error("Expected: job rejected after stop")
+	}
+}
+
+func TestWorkerPoolTimeout(t *testing.T) {
+	p := newWorkerPool()
+	p.start(1)
+
+	cancelled := make(chan struct{})
+	p.submit(func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	// In-progress jobs are cancelled once timeout is reached
+	if p.stop(50 * time.Millisecond) {
+		t.Error("Expected: timeout waiting for jobs")
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Error("Expected: job cancelled")
+	}
+}
+
+func TestWorkerPoolStopFullBuffer(t *testing.T) {
+	p := newWorkerPool()
+	p.start(1)
+
+	started := make(chan struct{})
+	resubmitted := make(chan bool, 1)
+	p.submit(func(ctx context.Context) {
+		close(started)
+		// Fill buffer, then submit from the worker like a fallback alert
+		for range jobBuffer {
+			p.submit(func(ctx context.Context) {})
+		}
+		resubmitted <- p.submit(func(ctx context.Context) {})
+	})
+	<-started
+	time.Sleep(50 * time.Millisecond)
+
+	// Stopping unblocks submit & waits for buffered jobs
+	done := make(chan bool)
+	go func() { done <- p.stop(time.Second) }()
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Error("Expected: all jobs finished before timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected: stop not blocked by full buffer")
+	}
+	if <-resubmitted {
+		t.Error("Expected: job rejected while stopping")
+	}
+}
@@ -0,0 +1,275 @@
+package snooze
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+const bucket = "snoozes"
+
+// snoozeEntry is the stored form of a snooze, including the key identifying a snoozed provider profile
+type snoozeEntry struct {
+	models.Snooze
+	// Identifies the snoozed provider profile, as ProfileID may change when profiles are added or removed. See config.ProfileKey
+	ProfileKey string `json:"profile_key,omitempty"`
+}
+
+var (
+	lock    sync.Mutex
+	snoozes = make(map[string]snoozeEntry)
+	// Used for snooze IDs if local data store is not available
+	lastID uint64
+)
+
+// Load restores active snoozes from the local data store, so they are kept after a restart
+func Load() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	err := storage.ForEach(bucket, func(key string, value []byte) error {
+		var s snoozeEntry
+		if err := json.Unmarshal(value, &s); err != nil {
+			log.Warn().
+				Err(err).
+				Str("snooze_id", key).
+				Msg("Unable to read snooze")
+			return nil
+		}
+		snoozes[s.ID] = s
+		return nil
+	})
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Unable to load snoozes")
+		return
+	}
+	removeExpired(time.Now())
+	refreshProfiles()
+	log.Debug().
+		Int("snoozes", len(snoozes)).
+		Msg("Loaded active snoozes")
+}
+
+// Refresh updates provider profile snoozes after a config reload. Profile IDs are updated for profiles that moved,
+// & snoozes are removed if their profile no longer exists
+func Refresh() {
+	lock.Lock()
+	defer lock.Unlock()
+	refreshProfiles()
+}
+
+// refreshProfiles matches provider profile snoozes to the running config. Must be called while holding lock
+func refreshProfiles() {
+	var removed []string
+	for id, s := range snoozes {
+		if s.ProfileID == nil {
+			continue
+		}
+		n, ok := config.GetNotifier(s.Provider)
+		if !ok {
+			removed = append(removed, id)
+			continue
+		}
+		// Snoozes saved before profile keys were added are matched by position once
+		changed := false
+		if s.ProfileKey == "" {
+			if profiles := n.Profiles(config.Current()); *s.ProfileID < len(profiles) {
+				s.ProfileKey = config.ProfileKey(profiles[*s.ProfileID])
+				changed = true
+			}
+		}
+		index, ok := config.FindProfileKey(config.Current(), n, s.ProfileKey)
+		if !ok {
+			removed = append(removed, id)
+			continue
+		}
+		if index == *s.ProfileID && !changed {
+			continue
+		}
+		s.ProfileID = &index
+		snoozes[id] = s
+		if storage.Ready() {
+			if err := storage.Put(bucket, id, s); err != nil {
+				log.Warn().
+					Err(err).
+					Str("snooze_id", id).
+					Msg("Unable to update snooze")
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	for _, id := range removed {
+		delete(snoozes, id)
+	}
+	log.Info().
+		Strs("snooze_ids", removed).
+		Msg("Snoozes removed, notification provider profile no longer configured")
+	if storage.Ready() {
+		if err := storage.Delete(bucket, removed...); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Unable to remove snoozes")
+		}
+	}
+}
+
+// Add saves a new snooze & returns it with ID set. If a provider profile is snoozed, it is identified by profileKey
+func Add(s models.Snooze, profileKey string) (models.Snooze, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	s.Created = time.Now()
+	entry := snoozeEntry{Snooze: s}
+	if s.ProfileID != nil {
+		entry.ProfileKey = profileKey
+	}
+	if storage.Ready() {
+		id, err := storage.NextID(bucket)
+		if err != nil {
+			return s, err
+		}
+		entry.ID = id
+		if err := storage.Put(bucket, entry.ID, entry); err != nil {
+			return s, err
+		}
+	} else {
+		lastID++
+		entry.ID = fmt.Sprintf("%016d", lastID)
+		log.Warn().Msg("Local data store not available, snooze will not be kept after restart")
+	}
+	snoozes[entry.ID] = entry
+	s = entry.Snooze
+
+	log.Info().
+		Str("snooze_id", s.ID).
+		Str("camera", s.Camera).
+		Str("label", s.Label).
+		Str("provider", s.Provider).
+		Time("until", s.Until).
+		Msg("Notifications snoozed")
+	return s, nil
+}
+
+// List returns all active snoozes, soonest to expire first
+func List() []models.Snooze {
+	lock.Lock()
+	defer lock.Unlock()
+
+	removeExpired(time.Now())
+	list := make([]models.Snooze, 0, len(snoozes))
+	for _, s := range snoozes {
+		list = append(list, s.Snooze)
+	}
+	slices.SortFunc(list, func(a, b models.Snooze) int {
+		if c := a.Until.Compare(b.Until); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return list
+}
+
+// Cancel removes an active snooze. Returns false if snooze does not exist
+func Cancel(id string) (bool, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := snoozes[id]; !ok {
+		return false, nil
+	}
+	if storage.Ready() {
+		if err := storage.Delete(bucket, id); err != nil {
+			return false, err
+		}
+	}
+	delete(snoozes, id)
+
+	log.Info().
+		Str("snooze_id", id).
+		Msg("Snooze cancelled")
+	return true, nil
+}
+
+// Event returns whether an event from camera with label is snoozed for all notification providers
+func Event(camera, label string) bool {
+	return snoozed("", "", []string{camera}, []string{label})
+}
+
+// Provider returns whether a notification provider profile, identified by key from config.ProfileKey,
+// is snoozed for every camera & label in an alert
+func Provider(provider string, key string, cameras, labels []string) bool {
+	return snoozed(provider, key, cameras, labels)
+}
+
+// snoozed checks if each combination of camera & label matches an active snooze for provider profile,
+// or that applies to all providers if provider is empty
+func snoozed(provider string, key string, cameras, labels []string) bool {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(snoozes) == 0 {
+		return false
+	}
+	removeExpired(time.Now())
+	for _, camera := range cameras {
+		for _, label := range labels {
+			if !matchAny(provider, key, camera, label) {
+				return false
+			}
+		}
+	}
+	return len(cameras) > 0 && len(labels) > 0
+}
+
+// matchAny returns whether any active snooze matches provider profile, camera & label
+func matchAny(provider string, key string, camera, label string) bool {
+	for _, s := range snoozes {
+		if s.Provider != provider {
+			continue
+		}
+		if s.ProfileID != nil && s.ProfileKey != key {
+			continue
+		}
+		if (s.Camera == "" || s.Camera == camera) && (s.Label == "" || s.Label == label) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeExpired deletes snoozes that have expired. Must be called while holding lock
+func removeExpired(now time.Time) {
+	var expired []string
+	for id, s := range snoozes {
+		if !now.Before(s.Until) {
+			expired = append(expired, id)
+			delete(snoozes, id)
+		}
+	}
+	if len(expired) == 0 {
+		return
+	}
+	log.Debug().
+		Strs("snooze_ids", expired).
+		Msg("Snoozes expired")
+	if storage.Ready() {
+		if err := storage.Delete(bucket, expired...); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Unable to remove expired snoozes")
+		}
+	}
+}
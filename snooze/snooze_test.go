@@ -0,0 +1,123 @@
+package snooze
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0x2142/frigate-notify/config"
+	"github.com/0x2142/frigate-notify/models"
+	"github.com/0x2142/frigate-notify/storage"
+)
+
+func TestSnoozed(t *testing.T) {
+	snoozes = make(map[string]snoozeEntry)
+	defer func() { snoozes = make(map[string]snoozeEntry) }()
+
+	profile := 1
+	Add(models.Snooze{Camera: "driveway", Until: time.Now().Add(time.Hour)}, "")
+	Add(models.Snooze{Label: "cat", Provider: "discord", ProfileID: &profile, Until: time.Now().Add(time.Hour)}, "name:second")
+	Add(models.Snooze{Camera: "porch", Until: time.Now().Add(-time.Minute)}, "")
+
+	tests := []struct {
+		name     string
+		provider string
+		key      string
+		cameras  []string
+		labels   []string
+		expected bool
+	}{
+		{"camera snoozed", "", "", []string{"driveway"}, []string{"person"}, true},
+		{"camera not snoozed", "", "", []string{"backyard"}, []string{"person"}, false},
+		{"expired snooze", "", "", []string{"porch"}, []string{"person"}, false},
+		{"provider snooze not applied to event", "", "", []string{"backyard"}, []string{"cat"}, false},
+		{"provider profile snoozed", "discord", "name:second", []string{"backyard"}, []string{"cat"}, true},
+		{"other provider profile", "discord", "name:first", []string{"backyard"}, []string{"cat"}, false},
+		{"one label not snoozed", "discord", "name:second", []string{"backyard"}, []string{"cat", "dog"}, false},
+		{"no labels", "discord", "name:second", []string{"backyard"}, nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := snoozed(tc.provider, tc.key, tc.cameras, tc.labels)
+			if result != tc.expected {
+				t.Errorf("Expected: %v, Got: %v", tc.expected, result)
+			}
+		})
+	}
+
+	if len(List()) != 2 {
+		t.Errorf("Expected expired snooze to be removed, Got: %+v", List())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	snoozes = make(map[string]snoozeEntry)
+	defer func() { snoozes = make(map[string]snoozeEntry) }()
+
+	storage.Open(filepath.Join(t.TempDir(), "test.db"))
+	defer storage.Close()
+
+	active, _ := Add(models.Snooze{Camera: "driveway", Until: time.Now().Add(time.Hour)}, "")
+	cancelled, _ := Add(models.Snooze{Camera: "porch", Until: time.Now().Add(time.Hour)}, "")
+	if ok, err := Cancel(cancelled.ID); !ok || err != nil {
+		t.Fatal("Expected snooze to be cancelled, Got: ", ok, err)
+	}
+	if ok, _ := Cancel(cancelled.ID); ok {
+		t.Error("Expected cancelling missing snooze to return false")
+	}
+
+	// Check snoozes restored from storage
+	snoozes = make(map[string]snoozeEntry)
+	Load()
+	list := List()
+	if len(list) != 1 || list[0].ID != active.ID {
+		t.Errorf("Expected: [%s], Got: %+v", active.ID, list)
+	}
+}
+
+// testNotifier reads profiles from Discord settings, so snoozes can be matched to config
+type testNotifier struct{}
+
+func (testNotifier) Name() string { return "snooze_test" }
+func (testNotifier) Profiles(c *config.Config) []config.Profile {
+	return config.ProfileList(c.Alerts.Discord)
+}
+func (testNotifier) Validate(*config.Config, int, config.Profile) []string { return nil }
+func (testNotifier) Send(context.Context, models.Event, io.Reader, config.Profile) error {
+	return nil
+}
+
+func TestRefresh(t *testing.T) {
+	snoozes = make(map[string]snoozeEntry)
+	defer func() { snoozes = make(map[string]snoozeEntry) }()
+	if _, ok := config.GetNotifier("snooze_test"); !ok {
+		config.RegisterNotifier(testNotifier{})
+	}
+	first := models.Discord{AlertCommon: models.AlertCommon{Name: "first"}}
+	second := models.Discord{AlertCommon: models.AlertCommon{Name: "second"}}
+	defer config.Update(func(c *config.Config) { c.Alerts.Discord = []models.Discord{first, second} })()
+
+	profile := 1
+	s, _ := Add(models.Snooze{Label: "cat", Provider: "snooze_test", ProfileID: &profile, Until: time.Now().Add(time.Hour)}, config.ProfileKey(&second))
+
+	// Snooze follows profile when earlier profiles are removed, & doesn't apply to the profile now at its old position
+	defer config.Update(func(c *config.Config) { c.Alerts.Discord = []models.Discord{second, first} })()
+	Refresh()
+	list := List()
+	if len(list) != 1 || *list[0].ProfileID != 0 {
+		t.Errorf("Expected: snooze for profile 0, Got: %+v", list)
+	}
+	if !Provider("snooze_test", config.ProfileKey(&second), []string{"driveway"}, []string{"cat"}) || Provider("snooze_test", config.ProfileKey(&first), []string{"driveway"}, []string{"cat"}) {
+		t.Error("Expected: only second profile snoozed")
+	}
+
+	// Snooze removed with its profile
+	defer config.Update(func(c *config.Config) { c.Alerts.Discord = []models.Discord{first} })()
+	Refresh()
+	if list := List(); len(list) != 0 {
+		t.Errorf("Expected: snooze %s removed, Got: %+v", s.ID, list)
+	}
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotReady is returned when the local data store has not been opened
+var ErrNotReady = errors.New("local data store not available")
+
+var db *bolt.DB
+
+// Open creates or opens the local data store at the specified path
+func Open(path string) error {
+	if db != nil {
+		return nil
+	}
+	log.Debug().
+		Str("path", path).
+		Msg("Opening local data store...")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var err error
+	db, err = bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		db = nil
+		return err
+	}
+	log.Debug().Msg("Local data store ready")
+	return nil
+}
+
+// Close closes the local data store
+func Close() {
+	if db == nil {
+		return
+	}
+	log.Debug().Msg("Closing local data store")
+	db.Close()
+	db = nil
+}
+
+// Ready returns whether the local data store is open
+func Ready() bool {
+	return db != nil
+}
+
+// NextID returns a unique, increasing ID for a new item in bucket
+func NextID(bucket string) (string, error) {
+	if db == nil {
+		return "", ErrNotReady
+	}
+	var id uint64
+	err := db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		id, err = b.NextSequence()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return formatID(id), nil
+}
+
+// Put stores value as JSON under key in bucket
+func Put(bucket, key string, value interface{}) error {
+	if db == nil {
+		return ErrNotReady
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	})
+}
+
+// Get loads JSON value stored under key in bucket, returning false if not found
+func Get(bucket, key string, value interface{}) (bool, error) {
+	if db == nil {
+		return false, ErrNotReady
+	}
+	var data []byte
+	err := db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		if v := b.Get([]byte(key)); v != nil {
+			data = append(data, v...)
+		}
+		return nil
+	})
+	if err != nil || data == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, value)
+}
+
+// Delete removes one or more keys from bucket
+func Delete(bucket string, keys ...string) error {
+	if db == nil {
+		return ErrNotReady
+	}
+	return db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		for _, key := range keys {
+			if err := b.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Move atomically stores value under key in bucket "to" & removes key from bucket "from"
+func Move(from, to, key string, value interface{}) error {
+	if db == nil {
+		return ErrNotReady
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(tx *bolt.Tx) error {
+		dst, err := tx.CreateBucketIfNotExists([]byte(to))
+		if err != nil {
+			return err
+		}
+		if err := dst.Put([]byte(key), data); err != nil {
+			return err
+		}
+		if src := tx.Bucket([]byte(from)); src != nil {
+			return src.Delete([]byte(key))
+		}
+		return nil
+	})
+}
+
+// ForEach calls fn for each item in bucket, in key order. Returning an error from fn stops iteration
+func ForEach(bucket string, fn func(key string, value []byte) error) error {
+	if db == nil {
+		return ErrNotReady
+	}
+	return db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			return fn(string(k), v)
+		})
+	})
+}
+
+// ForEachReverse calls fn for each item in bucket, in reverse key order. Returning an error from fn stops iteration
+func ForEachReverse(bucket string, fn func(key string, value []byte) error) error {
+	if db == nil {
+		return ErrNotReady
+	}
+	return db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if err := fn(string(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Count returns number of items in bucket
+func Count(bucket string) int {
+	if db == nil {
+		return 0
+	}
+	var count int
+	db.View(func(tx *bolt.Tx) error {
+		if b := tx.Bucket([]byte(bucket)); b != nil {
+			count = b.Stats().KeyN
+		}
+		return nil
+	})
+	return count
+}
+
+// formatID zero-pads a sequence number so IDs sort in creation order
+func formatID(id uint64) string {
+	return fmt.Sprintf("%016d", id)
+}
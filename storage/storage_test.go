@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+func TestStorage(t *testing.T) {
+	// Setup
+	if err := Open(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("Unable to open data store: %v", err)
+	}
+	defer Close()
+
+	// Check IDs sort in creation order
+	first, _ := NextID("test")
+	second, _ := NextID("test")
+	if first >= second {
+		t.Errorf("Expected: %v < %v", first, second)
+	}
+
+	// Check put & get
+	Put("test", first, testItem{Name: "first"})
+	var result testItem
+	ok, err := Get("test", first, &result)
+	if !ok || err != nil || result.Name != "first" {
+		t.Errorf("Expected: first, Got: %v (%v)", result.Name, err)
+	}
+
+	// Check missing item
+	ok, _ = Get("test", "asdf", &result)
+	if ok {
+		t.Error("Expected: item not found")
+	}
+
+	// Check move between buckets
+	Move("test", "other", first, testItem{Name: "moved"})
+	if Count("test") != 0 || Count("other") != 1 {
+		t.Errorf("Expected: 0 & 1 items, Got: %v & %v", Count("test"), Count("other"))
+	}
+
+	// Check delete
+	Delete("other", first)
+	if Count("other") != 0 {
+		t.Errorf("Expected: 0 items, Got: %v", Count("other"))
+	}
+
+	// Check reverse iteration
+	Put("test", first, testItem{Name: "first"})
+	Put("test", second, testItem{Name: "second"})
+	var keys []string
+	ForEachReverse("test", func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if len(keys) != 2 || keys[0] != second {
+		t.Errorf("Expected: [%v %v], Got: %v", second, first, keys)
+	}
+}
+
+func TestNotReady(t *testing.T) {
+	if err := Put("test", "key", testItem{}); err != ErrNotReady {
+		t.Errorf("Expected: %v, Got: %v", ErrNotReady, err)
+	}
+}
@@ -0,0 +1,142 @@
+package stream
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+// Number of messages that can be waiting for a slow client before new messages are dropped
+const bufferSize = 100
+
+var (
+	lock        sync.RWMutex
+	subscribers = make(map[chan any]struct{})
+)
+
+// Subscribe returns a channel that receives each new stream message, along with a function to stop receiving messages
+func Subscribe() (<-chan any, func()) {
+	ch := make(chan any, bufferSize)
+	lock.Lock()
+	subscribers[ch] = struct{}{}
+	lock.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			lock.Lock()
+			delete(subscribers, ch)
+			lock.Unlock()
+		})
+	}
+}
+
+// publish sends a message to all subscribers, without waiting for slow clients
+func publish(msg any) {
+	lock.RLock()
+	defer lock.RUnlock()
+	for ch := range subscribers {
+		select {
+		case ch <- msg:
+		default:
+			log.Debug().Msg("Live stream client not keeping up, message dropped")
+		}
+	}
+}
+
+// active returns whether any clients are connected, so messages are only built when needed
+func active() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+	return len(subscribers) > 0
+}
+
+// EventReceived publishes a new event received from Frigate
+func EventReceived(event models.Event, source string) {
+	if !active() {
+		return
+	}
+	publish(models.StreamReceived{
+		Time:    time.Now(),
+		EventID: event.ID,
+		Camera:  event.Camera,
+		Labels:  []string{event.Label},
+		Zones:   event.CurrentZones,
+		Source:  source,
+	})
+}
+
+// ReviewReceived publishes a new review received from Frigate
+func ReviewReceived(review models.Review, source string) {
+	if !active() {
+		return
+	}
+	publish(models.StreamReceived{
+		Time:     time.Now(),
+		ReviewID: review.ID,
+		Camera:   review.Camera,
+		Labels:   append(slices.Clone(review.Data.Objects), review.Data.Audio...),
+		Zones:    review.Data.Zones,
+		Source:   source,
+	})
+}
+
+// Filtered publishes events that were dropped by a filter
+func Filtered(events []models.Event, reason string) {
+	if !active() || len(events) == 0 {
+		return
+	}
+	msg := models.StreamFiltered{
+		Time:     time.Now(),
+		EventID:  events[0].ID,
+		ReviewID: events[0].Extra.ReviewID,
+		Camera:   events[0].Camera,
+		Reason:   reason,
+	}
+	for _, event := range events {
+		label := event.Label
+		if label == "" {
+			label = event.Extra.Audio
+		}
+		if label != "" && !slices.Contains(msg.Labels, label) {
+			msg.Labels = append(msg.Labels, label)
+		}
+	}
+	publish(msg)
+}
+
+// FilteredReview publishes a review that was dropped before its detections were checked
+func FilteredReview(review models.Review, reason string) {
+	if !active() {
+		return
+	}
+	publish(models.StreamFiltered{
+		Time:     time.Now(),
+		ReviewID: review.ID,
+		Camera:   review.Camera,
+		Labels:   append(slices.Clone(review.Data.Objects), review.Data.Audio...),
+		Reason:   reason,
+	})
+}
+
+// Delivery publishes the result of sending an alert via a single provider profile
+func Delivery(event models.Event, provider string, index int, status, detail string) {
+	if !active() {
+		return
+	}
+	publish(models.StreamDelivery{
+		Time:      time.Now(),
+		EventID:   event.ID,
+		ReviewID:  event.Extra.ReviewID,
+		HistoryID: event.Extra.HistoryID,
+		Camera:    event.Camera,
+		Provider:  provider,
+		ProfileID: index,
+		Status:    status,
+		Detail:    detail,
+	})
+}
@@ -0,0 +1,45 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/0x2142/frigate-notify/models"
+)
+
+func TestStream(t *testing.T) {
+	// Messages are not built without any clients
+	Filtered([]models.Event{{ID: "event-1"}}, "Quiet hours")
+
+	messages, unsubscribe := Subscribe()
+	defer unsubscribe()
+
+	// Each client receives published messages
+	event := models.Event{ID: "event-2", Camera: "front_door", Label: "person"}
+	EventReceived(event, "mqtt")
+	Delivery(event, "discord", 0, "sent", "")
+	received := (<-messages).(models.StreamReceived)
+	if received.EventID != "event-2" || received.Source != "mqtt" || received.Labels[0] != "person" {
+		t.Errorf("Expected: event-2 received via mqtt, Got: %+v", received)
+	}
+	delivery := (<-messages).(models.StreamDelivery)
+	if delivery.Provider != "discord" || delivery.Status != "sent" {
+		t.Errorf("Expected: sent via discord, Got: %+v", delivery)
+	}
+	if len(messages) != 0 {
+		t.Errorf("Expected: no messages sent before subscribing, Got: %v", len(messages))
+	}
+
+	// Slow clients do not block publishing
+	for range bufferSize + 10 {
+		FilteredReview(models.Review{ID: "review-1"}, "Severity")
+	}
+	if len(messages) != bufferSize {
+		t.Errorf("Expected: %v buffered messages, Got: %v", bufferSize, len(messages))
+	}
+
+	// Clients stop receiving messages once unsubscribed
+	unsubscribe()
+	if active() {
+		t.Error("Expected: no active clients")
+	}
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Cooldown tracks recently sent notifications, so that repeat notifications can be suppressed for a period of time
+type Cooldown struct {
+	mu      sync.Mutex
+	entries map[string]cooldownEntry
+}
+
+type cooldownEntry struct {
+	eventIDs []string
+	expires  time.Time
+}
+
+// NewCooldown creates an empty cooldown tracker
+func NewCooldown() *Cooldown {
+	return &Cooldown{entries: make(map[string]cooldownEntry)}
+}
+
+// Allow returns false if every key is within a cooldown window started by a different set of events.
+// Otherwise, a new cooldown window is started for each key with a duration greater than zero & true is returned
+func (c *Cooldown) Allow(windows map[string]time.Duration, eventIDs []string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	// Clean up expired entries
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+
+	if len(windows) == 0 {
+		return true
+	}
+
+	// Check if all keys are already in cooldown
+	suppress := true
+	for key := range windows {
+		entry, ok := c.entries[key]
+		if !ok || slices.ContainsFunc(eventIDs, func(id string) bool { return slices.Contains(entry.eventIDs, id) }) {
+			suppress = false
+			break
+		}
+	}
+	if suppress {
+		return false
+	}
+
+	for key, window := range windows {
+		if window > 0 {
+			c.entries[key] = cooldownEntry{eventIDs: eventIDs, expires: now.Add(window)}
+		}
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -34,9 +35,9 @@ func init() {
 	cookies, _ = cookiejar.New(nil)
 }
 
-func GetFrigateVersion(headers []map[string]string) (int, error) {
+func GetFrigateVersion(ctx context.Context, headers []map[string]string) (int, error) {
 	url := fmt.Sprintf("%s/api/version", FrigateServer)
-	response, err := HTTPGet(url, FrigateInsecure, "", headers...)
+	response, err := HTTPGet(ctx, url, FrigateInsecure, "", headers...)
 	if err != nil {
 		return 0, err
 	}
@@ -45,12 +46,12 @@ func GetFrigateVersion(headers []map[string]string) (int, error) {
 	return version, nil
 }
 
-func checkFrigateAuth() error {
+func checkFrigateAuth(ctx context.Context) error {
 	log.Trace().Msg("Checking Frigate auth token...")
 	url := fmt.Sprintf("%s/api/profile", FrigateServer)
-	if _, err := HTTPGet(url, FrigateInsecure, ""); err != nil {
+	if _, err := HTTPGet(ctx, url, FrigateInsecure, ""); err != nil {
 		log.Trace().Msg("Frigate auth token expired or not obtained yet")
-		if err := getFrigateAuthToken(); err != nil {
+		if err := getFrigateAuthToken(ctx); err != nil {
 			return err
 		}
 		return nil
@@ -59,7 +60,7 @@ func checkFrigateAuth() error {
 	return nil
 }
 
-func getFrigateAuthToken() error {
+func getFrigateAuthToken(ctx context.Context) error {
 	log.Debug().Msg("Authenticating to Frigate...")
 	authurl := fmt.Sprintf("%s/api/login", FrigateServer)
 
@@ -73,7 +74,7 @@ func getFrigateAuthToken() error {
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
 	}
 
-	auth, _ := http.NewRequest(http.MethodPost, authurl, bytes.NewBuffer(auth_payload))
+	auth, _ := http.NewRequestWithContext(ctx, http.MethodPost, authurl, bytes.NewBuffer(auth_payload))
 
 	log.Trace().
 		Str("url", authurl).
@@ -2,15 +2,19 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -33,13 +37,82 @@ func BuildHTTPParams(params ...map[string]string) string {
 	return "?" + paramList.Encode()
 }
 
+// Wait pauses for the specified duration, returning false early if ctx is cancelled
+func Wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// RedactHeaders returns a copy of HTTP headers with authorization values removed, for logging
+func RedactHeaders(headers []map[string]string) []map[string]string {
+	var redacted []map[string]string
+	for _, h := range headers {
+		for k := range h {
+			if strings.ToLower(k) == "authorization" {
+				modifiedHeader := make(map[string]string)
+				modifiedHeader[k] = "--secret removed--"
+				redacted = append(redacted, modifiedHeader)
+			} else {
+				redacted = append(redacted, h)
+			}
+		}
+	}
+	return redacted
+}
+
+// ErrAddrNotAllowed is returned when a request connects to an IP address that is not permitted, see WithAllowedAddrs
+var ErrAddrNotAllowed = errors.New("address not allowed")
+
+type allowedAddrsKey struct{}
+
+// WithAllowedAddrs restricts HTTP GET requests made with ctx to IP addresses permitted by allowed. Addresses are
+// checked after DNS resolution for each connection, including redirects, so a host can't resolve elsewhere after it was checked
+func WithAllowedAddrs(ctx context.Context, allowed func(addr netip.Addr) bool) context.Context {
+	return context.WithValue(ctx, allowedAddrsKey{}, allowed)
+}
+
+// httpTransport returns the HTTP transport for a request, or nil to use the default transport
+func httpTransport(ctx context.Context, insecure bool) http.RoundTripper {
+	allowed, _ := ctx.Value(allowedAddrsKey{}).(func(netip.Addr) bool)
+	if !insecure && allowed == nil {
+		return nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Ignore SSL verification if set
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if allowed != nil {
+		dialer := &net.Dialer{
+			Timeout: 30 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(address)
+				if err != nil || !allowed(addrPort.Addr().Unmap()) {
+					return fmt.Errorf("%w: %s", ErrAddrNotAllowed, address)
+				}
+				return nil
+			},
+		}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+	return transport
+}
+
 func setUserAgent(req *http.Request) *http.Request {
 	req.Header.Add("User-Agent", AppUserAgent)
 	return req
 }
 
-// HTTPGet is a simple HTTP client function to return page body
-func HTTPGet(url string, insecure bool, params string, headers ...map[string]string) ([]byte, error) {
+// HTTPGet is a simple HTTP client function to return page body.
+// Request is aborted if ctx is cancelled
+func HTTPGet(ctx context.Context, url string, insecure bool, params string, headers ...map[string]string) ([]byte, error) {
 	// Append HTTP params if any
 	if len(params) > 0 {
 		url = url + params
@@ -47,28 +120,23 @@ func HTTPGet(url string, insecure bool, params string, headers ...map[string]str
 
 	// New HTTP Client
 	client := &http.Client{
-		Timeout: time.Duration(HTTPTimeout) * time.Second,
-		Jar:     cookies,
-	}
-
-	// Ignore SSL verification if set
-	if insecure {
-		client.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+		Timeout:   time.Duration(HTTPTimeout) * time.Second,
+		Jar:       cookies,
+		Transport: httpTransport(ctx, insecure),
 	}
 
 	// Set auth cookies if Frigate request & auth is enabled
 	if strings.HasPrefix(url, FrigateServer) && AuthEnabled {
 		// `/api/profile` is used to check token validity, so skip auth check
 		if !strings.HasSuffix(url, "/api/profile") {
-			if err := checkFrigateAuth(); err != nil {
+			if err := checkFrigateAuth(ctx); err != nil {
 				return nil, err
 			}
 		}
 	}
 
 	// Setup new HTTP Request
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
@@ -85,18 +153,7 @@ func HTTPGet(url string, insecure bool, params string, headers ...map[string]str
 	setUserAgent(req)
 
 	// Remove authorization header value for logging
-	var logheaders []map[string]string
-	for _, h := range headers {
-		for k := range h {
-			if strings.ToLower(k) == "authorization" {
-				modifiedHeader := make(map[string]string)
-				modifiedHeader[k] = "--secret removed--"
-				logheaders = append(logheaders, modifiedHeader)
-			} else {
-				logheaders = append(logheaders, h)
-			}
-		}
-	}
+	logheaders := RedactHeaders(headers)
 
 	// Send HTTP GET
 	log.Trace().
@@ -144,8 +201,9 @@ func HTTPGet(url string, insecure bool, params string, headers ...map[string]str
 }
 
 // HTTPPost performs an HTTP POST to the target URL
-// and includes auth parameters, ignoring certificates, etc
-func HTTPPost(url string, insecure bool, payload []byte, params string, headers ...map[string]string) ([]byte, error) {
+// and includes auth parameters, ignoring certificates, etc.
+// Request & any retries are aborted if ctx is cancelled
+func HTTPPost(ctx context.Context, url string, insecure bool, payload []byte, params string, headers ...map[string]string) ([]byte, error) {
 	// Append HTTP params if any
 	if len(params) > 0 {
 		url = url + params
@@ -164,7 +222,7 @@ func HTTPPost(url string, insecure bool, payload []byte, params string, headers
 	retry := 1
 	for retry <= 6 {
 		// Setup new HTTP Request
-		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payload))
 		if err != nil {
 			return nil, err
 		}
@@ -181,18 +239,7 @@ func HTTPPost(url string, insecure bool, payload []byte, params string, headers
 		setUserAgent(req)
 
 		// Remove authorization header value for logging
-		var logheaders []map[string]string
-		for _, h := range headers {
-			for k := range h {
-				if strings.ToLower(k) == "authorization" {
-					modifiedHeader := make(map[string]string)
-					modifiedHeader[k] = "--secret removed--"
-					logheaders = append(logheaders, modifiedHeader)
-				} else {
-					logheaders = append(logheaders, h)
-				}
-			}
-		}
+		logheaders := RedactHeaders(headers)
 
 		// Send HTTP POST
 		if json.Valid(payload) {
@@ -215,7 +262,7 @@ func HTTPPost(url string, insecure bool, payload []byte, params string, headers
 		if err == nil {
 			break
 		} else {
-			if retry == HTTPMaxAttempts {
+			if retry == HTTPMaxAttempts || ctx.Err() != nil {
 				log.Warn().
 					Int("attempt", retry).
 					Int("max_tries", HTTPMaxAttempts).
@@ -229,7 +276,9 @@ func HTTPPost(url string, insecure bool, payload []byte, params string, headers
 				Err(err).
 				Msg("HTTP Request failed, retrying in 2 seconds...")
 			retry += 1
-			time.Sleep(2 * time.Second)
+			if !Wait(ctx, 2*time.Second) {
+				return nil, ctx.Err()
+			}
 		}
 	}
 
@@ -0,0 +1,316 @@
+"use strict";
+
+const API = "../api/v1";
+const REFRESH_INTERVAL = 10000;
+const PREVIEW_DELAY = 500;
+
+// api sends a request to the Frigate-Notify API & returns the parsed response body.
+// Errors include any validation messages returned by the API
+async function api(method, path, body) {
+  const headers = {};
+  const token = localStorage.getItem("token");
+  if (token) {
+    headers["Authorization"] = "Bearer " + token;
+  }
+  if (body !== undefined) {
+    headers["Content-Type"] = "application/json";
+  }
+  const resp = await fetch(API + path, {
+    method: method,
+    headers: headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const data = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    const err = new Error(data.detail || resp.statusText || "Request failed");
+    err.status = resp.status;
+    err.details = (data.errors || []).map((e) => e.message);
+    throw err;
+  }
+  return data;
+}
+
+function showBanner(message) {
+  const banner = document.getElementById("banner");
+  banner.textContent = message;
+  banner.classList.toggle("hidden", !message);
+}
+
+function handleError(err) {
+  if (err.status === 401) {
+    showBanner("Authentication required - Enter an API token, or reload the page to sign in");
+  } else if (err.status === 403) {
+    showBanner("API credentials do not have access to this page");
+  } else {
+    showBanner(err.message);
+  }
+}
+
+function formatTime(value) {
+  if (!value || value.startsWith("0001-")) {
+    return "never";
+  }
+  return new Date(value).toLocaleString();
+}
+
+function cell(row, text, className) {
+  const td = row.insertCell();
+  td.textContent = text;
+  if (className) {
+    td.className = className;
+  }
+  return td;
+}
+
+function statusClass(status) {
+  if (["ok", "sent", "connected"].includes(status)) {
+    return "ok";
+  }
+  if (["failed", "partial", "error", "disconnected"].includes(status)) {
+    return "error";
+  }
+  return "";
+}
+
+// Dashboard
+
+async function loadStatus() {
+  const data = await api("GET", "/status");
+  const status = data.status;
+  const table = document.getElementById("status");
+  table.replaceChildren();
+  const rows = [
+    ["Health", status.health],
+    ["API", status.api],
+    ["Frigate API", status.frigate.api],
+    ["Frigate MQTT", status.frigate.mqtt],
+    ["Health monitor", status.monitor],
+    ["Last event", formatTime(status.last_event)],
+    ["Last notification", formatTime(status.last_notification)],
+  ];
+  for (const [name, value] of rows) {
+    const row = table.insertRow();
+    const th = document.createElement("th");
+    th.textContent = name;
+    row.appendChild(th);
+    cell(row, value, statusClass(value));
+  }
+
+  const body = document.querySelector("#providers tbody");
+  body.replaceChildren();
+  const providers = Object.keys(status.notifications).filter((key) => Array.isArray(status.notifications[key])).sort();
+  for (const provider of providers) {
+    for (const profile of status.notifications[provider]) {
+      if (!profile.enabled) {
+        continue;
+      }
+      const row = body.insertRow();
+      cell(row, provider);
+      cell(row, profile.id);
+      cell(row, profile.status, statusClass(profile.status));
+      cell(row, profile.sent);
+      cell(row, profile.failed);
+      cell(row, formatTime(profile.last_success));
+      cell(row, profile.last_error === "n/a" ? "" : profile.last_error, "error");
+    }
+  }
+
+  setNotifState(status.notifications.enabled, status.notif_timer ? status.notif_timer.until : undefined);
+}
+
+function setNotifState(enabled, until) {
+  const state = document.getElementById("notif-state");
+  state.textContent = enabled ? "enabled" : "disabled";
+  state.className = enabled ? "ok" : "error";
+  const revert = document.getElementById("notif-until");
+  revert.textContent = until ? "until " + formatTime(until) : "";
+  document.getElementById("notif-toggle").textContent = enabled ? "Disable notifications" : "Enable notifications";
+  document.getElementById("notif-toggle").dataset.enabled = enabled;
+}
+
+async function toggleNotifState() {
+  const enabled = document.getElementById("notif-toggle").dataset.enabled !== "true";
+  try {
+    const data = await api("POST", "/notif_state", { enabled: enabled });
+    setNotifState(data.enabled, data.until);
+  } catch (err) {
+    handleError(err);
+  }
+}
+
+async function loadHistory() {
+  const body = document.querySelector("#history tbody");
+  let data;
+  try {
+    data = await api("GET", "/history?page_size=20");
+  } catch (err) {
+    // History is disabled if no data store is available
+    if (err.status === 503) {
+      body.replaceChildren();
+      cell(body.insertRow(), "Notification history not available");
+      return;
+    }
+    throw err;
+  }
+  body.replaceChildren();
+  for (const item of data.items) {
+    const row = body.insertRow();
+    cell(row, formatTime(item.time));
+    cell(row, item.camera);
+    cell(row, (item.labels || []).join(", "));
+    cell(row, item.outcome + (item.reason ? " (" + item.reason + ")" : ""), statusClass(item.outcome));
+    const providers = (item.providers || []).map((p) => p.provider + "/" + p.provider_id + ": " + p.status);
+    cell(row, providers.join(", "));
+  }
+}
+
+async function refreshDashboard() {
+  try {
+    await Promise.all([loadStatus(), loadHistory()]);
+    showBanner("");
+  } catch (err) {
+    handleError(err);
+  }
+}
+
+// Template editor
+
+let previewTimer;
+
+function schedulePreview() {
+  clearTimeout(previewTimer);
+  previewTimer = setTimeout(renderPreview, PREVIEW_DELAY);
+}
+
+async function renderPreview() {
+  const builtin = document.getElementById("tmpl-builtin").value;
+  const source = document.getElementById("tmpl-source");
+  source.disabled = builtin !== "";
+
+  const request = { template: builtin || source.value };
+  const id = document.getElementById("tmpl-id").value.trim();
+  if (id) {
+    request.id = id;
+    const type = document.getElementById("tmpl-type").value;
+    if (type) {
+      request.type = type;
+    }
+  } else {
+    const zones = document.getElementById("tmpl-zones").value.split(",").map((z) => z.trim()).filter((z) => z);
+    request.synthetic = {
+      camera: document.getElementById("tmpl-camera").value.trim() || undefined,
+      label: document.getElementById("tmpl-label").value.trim() || undefined,
+      zones: zones,
+    };
+  }
+
+  const output = document.getElementById("tmpl-output");
+  const error = document.getElementById("tmpl-error");
+  if (!request.template) {
+    output.textContent = "";
+    error.classList.add("hidden");
+    return;
+  }
+  try {
+    const data = await api("POST", "/template/render", request);
+    output.textContent = data.output;
+    error.textContent = data.error || "";
+    error.classList.toggle("hidden", !data.error);
+  } catch (err) {
+    output.textContent = "";
+    error.textContent = "Unable to render template: " + err.message;
+    error.classList.remove("hidden");
+  }
+}
+
+// Config editor
+
+async function loadConfig() {
+  try {
+    const data = await api("GET", "/config");
+    document.getElementById("config-source").value = JSON.stringify(data.config, null, 2);
+    showConfigErrors([]);
+  } catch (err) {
+    handleError(err);
+  }
+}
+
+function showConfigErrors(errors) {
+  const list = document.getElementById("config-errors");
+  list.replaceChildren();
+  for (const message of errors) {
+    const item = document.createElement("li");
+    item.textContent = message;
+    list.appendChild(item);
+  }
+  list.classList.toggle("hidden", errors.length === 0);
+}
+
+async function saveConfig() {
+  let config;
+  try {
+    config = JSON.parse(document.getElementById("config-source").value);
+  } catch (err) {
+    showConfigErrors(["Invalid JSON: " + err.message]);
+    return;
+  }
+  const skipSave = document.getElementById("config-skipsave").checked;
+  try {
+    await api("PUT", "/config", { config: config, skipsave: skipSave });
+    showConfigErrors([]);
+    showBanner("");
+    alert("Config saved - Frigate-Notify is reloading with the new config");
+  } catch (err) {
+    if (err.details && err.details.length) {
+      showConfigErrors(err.details);
+    } else {
+      handleError(err);
+    }
+  }
+}
+
+// Setup
+
+function showTab(name) {
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.classList.toggle("active", tab.dataset.tab === name);
+  }
+  for (const panel of document.querySelectorAll(".panel")) {
+    panel.classList.toggle("hidden", panel.id !== name);
+  }
+  if (name === "templates") {
+    renderPreview();
+  }
+  if (name === "config") {
+    loadConfig();
+  }
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.addEventListener("click", () => showTab(tab.dataset.tab));
+  }
+
+  document.getElementById("token").value = localStorage.getItem("token") || "";
+  document.getElementById("token-form").addEventListener("submit", (e) => {
+    e.preventDefault();
+    localStorage.setItem("token", document.getElementById("token").value.trim());
+    refreshDashboard();
+  });
+
+  document.getElementById("notif-toggle").addEventListener("click", toggleNotifState);
+
+  for (const id of ["tmpl-builtin", "tmpl-source", "tmpl-id", "tmpl-type", "tmpl-camera", "tmpl-label", "tmpl-zones"]) {
+    document.getElementById(id).addEventListener("input", schedulePreview);
+  }
+
+  document.getElementById("config-reload").addEventListener("click", loadConfig);
+  document.getElementById("config-save").addEventListener("click", saveConfig);
+
+  refreshDashboard();
+  setInterval(() => {
+    if (!document.getElementById("dashboard").classList.contains("hidden")) {
+      refreshDashboard();
+    }
+  }, REFRESH_INTERVAL);
+});